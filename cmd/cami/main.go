@@ -13,12 +13,19 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/backup"
+	"github.com/lando/cami/internal/bundle"
 	"github.com/lando/cami/internal/cli"
 	"github.com/lando/cami/internal/config"
 	"github.com/lando/cami/internal/deploy"
+	"github.com/lando/cami/internal/discovery"
 	"github.com/lando/cami/internal/docs"
+	"github.com/lando/cami/internal/git"
 	"github.com/lando/cami/internal/manifest"
 	"github.com/lando/cami/internal/normalize"
+	"github.com/lando/cami/internal/plan"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/search"
+	"github.com/lando/cami/internal/spec"
 	"github.com/lando/cami/internal/tui"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -49,9 +56,20 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("USAGE:")
 	fmt.Println("  cami --mcp               Start MCP server (for Claude Code integration)")
-	fmt.Println("  cami list                List available agents")
+	fmt.Println("  cami list                List available agents, slash commands and skills")
+	fmt.Println("  cami which               Show which source an agent comes from")
+	fmt.Println("  cami search              Search agents by relevance, with filters")
 	fmt.Println("  cami deploy              Deploy agents to a project")
+	fmt.Println("  cami bundle              List agent bundles defined by sources")
+	fmt.Println("  cami remove              Remove deployed agents from a project")
+	fmt.Println("  cami sync                Update deployed agents in tracked projects")
+	fmt.Println("  cami apply               Converge a project to its .claude/cami.yaml")
+	fmt.Println("  cami lock                Pin deployed agents in .claude/cami-lock.yaml")
+	fmt.Println("  cami install             Install agents from a project's lock file")
+	fmt.Println("  cami diff                Diff deployed agents against their source")
 	fmt.Println("  cami scan                Scan deployed agents at a location")
+	fmt.Println("  cami lint                Check agents in sources for problems")
+	fmt.Println("  cami schema              Print the JSON Schema for agent frontmatter")
 	fmt.Println("  cami update-docs         Update CLAUDE.md with agent info")
 	fmt.Println("  cami source              Manage agent sources")
 	fmt.Println("  cami locations           Manage deployment locations")
@@ -81,132 +99,304 @@ func printHelp() {
 	fmt.Println("For more information, see: https://github.com/lando-labs/cami")
 }
 
-// loadAllAgents loads agents from all configured sources
-func loadAllAgents() ([]*agent.Agent, error) {
+// loadAllAgents loads agents from all configured sources. The report says
+// which files were skipped and why; nothing is printed, since stdout and
+// stderr may carry the MCP transport.
+func loadAllAgents() ([]*agent.Agent, *agent.LoadReport, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w - run 'cami source add <git-url>' to add agent sources", err)
+		return nil, nil, fmt.Errorf("failed to load config: %w - run 'cami source add <git-url>' to add agent sources", err)
 	}
 
 	if len(cfg.AgentSources) == 0 {
-		return nil, fmt.Errorf("no agent sources configured - run 'cami source add <git-url>' to add agent sources")
+		return nil, nil, fmt.Errorf("no agent sources configured - run 'cami source add <git-url>' to add agent sources")
 	}
 
-	// Convert config sources to agent sources
-	agentSources := make([]agent.AgentSource, len(cfg.AgentSources))
-	for i, src := range cfg.AgentSources {
-		agentSources[i] = agent.AgentSource{
-			Path:     src.Path,
-			Priority: src.Priority,
-		}
+	return agent.LoadAgentsFromSources(configAgentSources(cfg))
+}
+
+// loadAllCommands loads slash commands from all configured sources with priority
+func loadAllCommands() ([]*agent.Agent, *agent.LoadReport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	return agent.LoadAgentsFromSources(agentSources)
+	return agent.LoadCommandsFromSources(configAgentSources(cfg))
 }
 
-// updateDeploymentManifests updates both project and central manifests after deployment
-func updateDeploymentManifests(projectPath string, agents []*agent.Agent, results []*deploy.Result) error {
-	// Load config to get source information
+// loadAllSkills loads skills from all configured sources with priority
+func loadAllSkills() ([]*agent.Agent, *agent.LoadReport, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return agent.LoadSkillsFromSources(configAgentSources(cfg))
+}
+
+// loadAllArtifacts loads every agent, slash command and skill from all
+// configured sources, with the combined load report
+func loadAllArtifacts() ([]*agent.Agent, *agent.LoadReport, error) {
+	report := &agent.LoadReport{}
+
+	agents, agentReport, err := loadAllAgents()
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Merge(agentReport)
+
+	commands, commandReport, err := loadAllCommands()
+	if err != nil {
+		return nil, nil, err
 	}
+	report.Merge(commandReport)
 
-	// Create a map of file paths to source info
-	sourceMap := make(map[string]*config.AgentSource)
-	for _, src := range cfg.AgentSources {
-		sourceMap[src.Path] = &src
+	skills, skillReport, err := loadAllSkills()
+	if err != nil {
+		return nil, nil, err
 	}
+	report.Merge(skillReport)
 
-	// Only track successfully deployed agents
-	var deployedAgents []manifest.DeployedAgent
-	now := time.Now()
+	return append(append(agents, commands...), skills...), report, nil
+}
 
-	for _, result := range results {
-		if !result.Success {
+// bundleRequests returns a bundle's agents followed by the named agents it
+// doesn't already list
+func bundleRequests(b *bundle.Bundle, names []string) []string {
+	requests := append([]string(nil), b.Agents...)
+	for _, name := range names {
+		if req, err := spec.ParseRequirement(name); err == nil && b.Contains(req.Name) {
 			continue
 		}
+		requests = append(requests, name)
+	}
+	return requests
+}
 
-		// Calculate content and metadata hashes
-		deployedPath := filepath.Join(projectPath, ".claude", "agents", result.Agent.Name+".md")
-		contentHash, err := manifest.CalculateContentHash(deployedPath)
-		if err != nil {
-			log.Printf("Warning: failed to calculate content hash for %s: %v", result.Agent.Name, err)
-			contentHash = ""
+// loadAllBundles loads bundles from all configured sources with priority
+func loadAllBundles() ([]*bundle.Bundle, *agent.LoadReport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return bundle.LoadFromSources(configAgentSources(cfg))
+}
+
+// findBundle returns the bundle with a name from the configured sources
+func findBundle(name string) (*bundle.Bundle, error) {
+	bundles, _, err := loadAllBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	b := bundle.Find(bundles, name)
+	if b == nil {
+		return nil, fmt.Errorf("bundle not found: %s", name)
+	}
+	return b, nil
+}
+
+// artifactSection describes commands or skills for list_agents, named by ID.
+// A skill's file name includes its directory.
+func artifactSection(title string, artifacts []*agent.Agent) (string, []AgentInfo) {
+	if len(artifacts) == 0 {
+		return "", nil
+	}
+
+	text := fmt.Sprintf("## %s (%d)\n\n", title, len(artifacts))
+	var infos []AgentInfo
+	for _, ag := range artifacts {
+		fileName := ag.FileName()
+		if ag.ArtifactKind() == agent.KindSkill {
+			fileName = filepath.Join(filepath.Base(filepath.Dir(ag.FilePath)), fileName)
 		}
 
-		metadataHash, err := manifest.CalculateMetadataHash(deployedPath)
-		if err != nil {
-			log.Printf("Warning: failed to calculate metadata hash for %s: %v", result.Agent.Name, err)
-			metadataHash = ""
+		infos = append(infos, AgentInfo{
+			Name:        ag.ID(),
+			Version:     ag.Version,
+			Description: ag.Description,
+			Category:    ag.Category,
+			FileName:    fileName,
+		})
+		text += fmt.Sprintf("• %s\n  %s\n\n", ag.ID(), ag.Description)
+	}
+	return text, infos
+}
+
+// loadReportText describes what loading skipped for list_agents: problems
+// first, then ignored and shadowed files
+func loadReportText(report *agent.LoadReport) string {
+	text := ""
+	if problems := report.Problems(); len(problems) > 0 {
+		text += fmt.Sprintf("## Load Problems (%d)\n\n", len(problems))
+		for _, problem := range problems {
+			text += fmt.Sprintf("• %s\n", problem)
+		}
+		text += "\n"
+	}
+
+	if len(report.Ignored) > 0 {
+		text += fmt.Sprintf("## Ignored by .camiignore (%d)\n\n", len(report.Ignored))
+		for _, ignored := range report.Ignored {
+			text += fmt.Sprintf("• %s\n", ignored.File)
 		}
+		text += "\n"
+	}
 
-		// Find source information from agent's file path
-		sourceName := ""
-		sourcePath := result.Agent.FilePath
-		priority := 999 // Default priority if not found
+	if len(report.Shadowed) > 0 {
+		text += fmt.Sprintf("## Shadowed (%d)\n\n", len(report.Shadowed))
+		for _, s := range report.Shadowed {
+			text += fmt.Sprintf("• %s from %s (priority %d), overridden by %s (priority %d)\n", s.ID, s.Source, s.Priority, s.By, s.ByPriority)
+		}
+		text += "\n"
+	}
+	return text
+}
 
-		for srcPath, src := range sourceMap {
-			if strings.HasPrefix(result.Agent.FilePath, srcPath) {
-				sourceName = src.Name
-				priority = src.Priority
-				break
-			}
+// configAgentSources converts config sources to agent sources
+func configAgentSources(cfg *config.Config) []agent.AgentSource {
+	agentSources := make([]agent.AgentSource, len(cfg.AgentSources))
+	for i, src := range cfg.AgentSources {
+		agentSources[i] = agent.AgentSource{
+			Name:     src.Name,
+			Path:     src.Path,
+			Priority: src.Priority,
 		}
+	}
+	return agentSources
+}
 
-		deployedAgents = append(deployedAgents, manifest.DeployedAgent{
-			Name:         result.Agent.Name,
-			Version:      result.Agent.Version,
-			Source:       sourceName,
-			SourcePath:   sourcePath,
-			Priority:     priority,
-			DeployedAt:   now,
-			ContentHash:  contentHash,
-			MetadataHash: metadataHash,
-			Origin:       "cami", // Deployed via CAMI
-		})
+// newResolver returns a version resolver over all configured sources
+func newResolver() (*resolve.Resolver, error) {
+	available, _, err := loadAllArtifacts()
+	if err != nil {
+		return nil, err
 	}
 
-	// Create/update project manifest
-	projectManifest := &manifest.ProjectManifest{
-		Version:      "1",
-		State:        manifest.StateCAMINative,
-		NormalizedAt: now,
-		Agents:       deployedAgents,
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := manifest.WriteProjectManifest(projectPath, projectManifest); err != nil {
-		return fmt.Errorf("failed to write project manifest: %w", err)
+	return resolve.New(configAgentSources(cfg), available), nil
+}
+
+// applyProjectSpec plans converging a project to its spec and, unless dryRun
+// is set, carries the plan out. Deployments and removals are both reported
+// as results. Agents a non-nil bundle lists are recorded as deployed from it.
+func applyProjectSpec(projectPath string, dryRun bool, b *bundle.Bundle) (*deploy.ApplyPlan, []DeployResult, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Update central manifest
-	centralManifest, err := manifest.ReadCentralManifest()
+	resolver, err := newResolver()
 	if err != nil {
-		return fmt.Errorf("failed to read central manifest: %w", err)
+		return nil, nil, fmt.Errorf("failed to load agents: %w", err)
 	}
 
-	absPath, err := filepath.Abs(projectPath)
+	plan, err := deploy.PlanApply(projectPath, resolver)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, nil, err
+	}
+	if b != nil {
+		for _, item := range plan.Items {
+			if item.Agent != nil && b.Contains(item.Name) {
+				item.Agent = b.Tag([]*agent.Agent{item.Agent})[0]
+			}
+		}
 	}
 
-	centralManifest.Deployments[absPath] = manifest.ProjectDeployment{
-		State:        manifest.StateCAMINative,
-		NormalizedAt: now,
-		LastScanned:  now,
-		Agents:       deployedAgents,
+	if dryRun || len(plan.Changes()) == 0 {
+		return plan, nil, nil
 	}
 
-	if err := manifest.WriteCentralManifest(centralManifest); err != nil {
-		return fmt.Errorf("failed to write central manifest: %w", err)
+	deployed, removed, err := deploy.Apply(plan, cfg.AgentSources)
+	var results []DeployResult
+	for _, result := range deployed {
+		results = append(results, DeployResult{
+			AgentName: result.Agent.ID(),
+			Success:   result.Success,
+			Message:   result.Message,
+		})
+	}
+	for _, result := range removed {
+		results = append(results, DeployResult{
+			AgentName: result.Name,
+			Success:   result.Success,
+			Message:   result.Message,
+		})
+	}
+	if err != nil {
+		return plan, results, fmt.Errorf("apply failed: %w", err)
 	}
 
-	return nil
+	return plan, results, nil
+}
+
+// planCreateProject plans what create_project writes for a new project: its
+// spec, the resolved agents and their manifests, CLAUDE.md and the location
+// registered in the config. Args.AgentNames already include the bundle's.
+func planCreateProject(projectPath string, args CreateProjectArgs, b *bundle.Bundle) (*plan.Plan, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	resolver, err := newResolver()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agents: %w", err)
+	}
+
+	var agents []*agent.Agent
+	for _, name := range args.AgentNames {
+		requirement, err := spec.ParseRequirement(name)
+		if err != nil {
+			return nil, err
+		}
+		ag, err := resolver.Resolve(requirement)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", requirement, err)
+		}
+		if b != nil && b.Contains(requirement.Name) {
+			ag = b.Tag([]*agent.Agent{ag})[0]
+		}
+		agents = append(agents, ag)
+	}
+
+	createPlan := plan.New("create project", projectPath)
+	createPlan.Write(filepath.Join(projectPath, spec.Filename), "project spec")
+
+	if len(agents) > 0 {
+		deployPlan, err := deploy.PlanDeploy(agents, projectPath, false, cfg.AgentSources)
+		if err != nil {
+			return nil, err
+		}
+		createPlan.Files = append(createPlan.Files, deployPlan.Files...)
+		createPlan.Manifests = append(createPlan.Manifests, deployPlan.Manifests...)
+	}
+
+	createPlan.Write(filepath.Join(projectPath, "CLAUDE.md"), "project vision and deployed agents")
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	createPlan.Write(configPath, "register location "+args.Name)
+
+	return createPlan, nil
+}
+
+// dryRunText renders a plan for a dry-run response
+func dryRunText(p *plan.Plan) string {
+	return p.Text() + "\n**This is a dry run - no changes were made.**\n"
 }
 
 func runTUI() error {
 	// Load agents from all configured sources
-	agents, err := loadAllAgents()
+	agents, report, err := loadAllAgents()
 	if err != nil {
 		return fmt.Errorf("error loading agents: %v", err)
 	}
@@ -222,7 +412,7 @@ func runTUI() error {
 	}
 
 	// Create and run TUI
-	model := tui.NewModel(agents, cfg)
+	model := tui.NewModel(agents, report, cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -266,9 +456,12 @@ func runCLI() {
 // MCP type definitions
 
 type DeployAgentsArgs struct {
-	AgentNames []string `json:"agent_names" jsonschema_description:"Array of agent names to deploy (e.g. ['architect', 'backend'])"`
-	TargetPath string   `json:"target_path" jsonschema_description:"Absolute path to target project directory"`
+	AgentNames []string `json:"agent_names,omitempty" jsonschema_description:"Array of agent names to deploy, optionally with a version constraint; slash commands as /name and skills as skill:name (e.g. ['architect', 'frontend@^2.1', 'backend@2.x', '/review', 'skill:pdf'])"`
+	Bundle     string   `json:"bundle,omitempty" jsonschema_description:"Name of a bundle defined by a source to deploy instead of agent_names (e.g. 'web-stack')"`
+	TargetPath string   `json:"target_path" jsonschema_description:"Absolute path to target project directory, or 'user' for the user scope (~/.claude/agents)"`
 	Overwrite  bool     `json:"overwrite,omitempty" jsonschema_description:"Whether to overwrite existing agent files (default: false)"`
+	Merge      bool     `json:"merge,omitempty" jsonschema_description:"Three-way merge source updates into locally modified agent files, writing conflict markers where both changed (default: false)"`
+	DryRun     bool     `json:"dry_run,omitempty" jsonschema_description:"Return the files and manifest entries the deployment would change without touching disk (default: false)"`
 }
 
 type UpdateClaudeMdArgs struct {
@@ -276,7 +469,7 @@ type UpdateClaudeMdArgs struct {
 }
 
 type ScanDeployedAgentsArgs struct {
-	TargetPath string `json:"target_path" jsonschema_description:"Absolute path to target project directory"`
+	TargetPath string `json:"target_path" jsonschema_description:"Absolute path to target project directory, or 'user' for the user scope (~/.claude/agents)"`
 }
 
 type DeployResult struct {
@@ -284,10 +477,70 @@ type DeployResult struct {
 	Success   bool   `json:"success"`
 	Message   string `json:"message"`
 	Conflict  bool   `json:"conflict,omitempty"`
+	Merged    bool   `json:"merged,omitempty"`
 }
 
 type DeployAgentsResponse struct {
-	Results []DeployResult `json:"results"`
+	Results      []DeployResult `json:"results"`
+	Dependencies []string       `json:"dependencies,omitempty"` // Agents deployed because a requested agent requires them
+	Plan         *plan.Plan     `json:"plan,omitempty"`
+}
+
+type UndeployAgentsArgs struct {
+	TargetPath string   `json:"target_path" jsonschema_description:"Absolute path to target project directory, or 'user' for the user scope (~/.claude/agents)"`
+	AgentNames []string `json:"agent_names,omitempty" jsonschema_description:"Array of agent names to remove; slash commands as /name, skills as skill:name"`
+	Orphaned   bool     `json:"orphaned,omitempty" jsonschema_description:"Remove every tracked agent that no configured source provides anymore (default: false)"`
+}
+
+type UndeployResult struct {
+	AgentName  string   `json:"agent_name"`
+	Success    bool     `json:"success"`
+	Message    string   `json:"message"`
+	Dependents []string `json:"dependents,omitempty"` // Deployed agents that still require the removed one
+}
+
+type UndeployAgentsResponse struct {
+	Results []UndeployResult `json:"results"`
+}
+
+type SyncProjectsArgs struct {
+	Locations []string `json:"locations,omitempty" jsonschema_description:"Location names or absolute project paths to sync (default: all tracked projects)"`
+	DryRun    bool     `json:"dry_run,omitempty" jsonschema_description:"Show what would change without deploying (default: false)"`
+}
+
+type SyncProjectResult struct {
+	Path    string             `json:"path"`
+	Items   []*deploy.SyncItem `json:"items"`
+	Updated []string           `json:"updated"`
+	Failed  []string           `json:"failed"`
+	Error   string             `json:"error,omitempty"`
+}
+
+type SyncProjectsResponse struct {
+	DryRun   bool                `json:"dry_run"`
+	Projects []SyncProjectResult `json:"projects"`
+}
+
+type ApplyProjectArgs struct {
+	TargetPath string `json:"target_path" jsonschema_description:"Absolute path to the project directory, or 'user' for the user scope (~/.claude/agents)"`
+	DryRun     bool   `json:"dry_run,omitempty" jsonschema_description:"Show the planned adds, updates and removals without applying them (default: false)"`
+}
+
+type ApplyProjectResponse struct {
+	Path    string              `json:"path"`
+	DryRun  bool                `json:"dry_run"`
+	Items   []*deploy.ApplyItem `json:"items"`
+	Results []DeployResult      `json:"results"`
+}
+
+type DiffAgentArgs struct {
+	TargetPath string   `json:"target_path" jsonschema_description:"Absolute path to the project directory"`
+	AgentNames []string `json:"agent_names,omitempty" jsonschema_description:"Agents to diff (default: every deployed agent)"`
+}
+
+type DiffAgentResponse struct {
+	Path   string              `json:"path"`
+	Agents []*deploy.AgentDiff `json:"agents"`
 }
 
 type AgentInfo struct {
@@ -299,14 +552,33 @@ type AgentInfo struct {
 }
 
 type ListAgentsResponse struct {
-	Agents []AgentInfo `json:"agents"`
+	Agents     []AgentInfo       `json:"agents"`
+	Commands   []AgentInfo       `json:"commands,omitempty"`
+	Skills     []AgentInfo       `json:"skills,omitempty"`
+	Bundles    []*bundle.Bundle  `json:"bundles,omitempty"`
+	LoadReport *agent.LoadReport `json:"load_report,omitempty"` // What loading skipped and why
+}
+
+type ExplainAgentArgs struct {
+	AgentName string `json:"agent_name" jsonschema_description:"Agent to explain; slash commands as /name, skills as skill:name"`
+}
+
+type SearchAgentsArgs struct {
+	Query    string `json:"query,omitempty" jsonschema_description:"Words to search for in agent names, specialties, descriptions and bodies; every word must match"`
+	Class    string `json:"class,omitempty" jsonschema_description:"Only agents of this class (e.g. technology-implementer)"`
+	Category string `json:"category,omitempty" jsonschema_description:"Only agents in this category"`
+	Source   string `json:"source,omitempty" jsonschema_description:"Only agents from this source"`
+	Version  string `json:"version,omitempty" jsonschema_description:"Only agents matching this version constraint (e.g. ^2 or >=1.5.0)"`
+	Limit    int    `json:"limit,omitempty" jsonschema_description:"Maximum number of results (default 10)"`
 }
 
 type AgentStatusInfo struct {
-	Name             string `json:"name"`
-	DeployedVersion  string `json:"deployed_version"`
-	AvailableVersion string `json:"available_version"`
-	Status           string `json:"status"`
+	Name             string   `json:"name"`
+	DeployedVersion  string   `json:"deployed_version"`
+	AvailableVersion string   `json:"available_version"`
+	Status           string   `json:"status"`
+	OverridesUser    bool     `json:"overrides_user,omitempty"`
+	ShadowedBy       []string `json:"shadowed_by,omitempty"`
 }
 
 type ScanDeployedAgentsResponse struct {
@@ -323,8 +595,9 @@ type RemoveLocationArgs struct {
 }
 
 type LocationInfo struct {
-	Name string `json:"name"`
-	Path string `json:"path"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Scope string `json:"scope"` // "user" or "project"
 }
 
 type ListLocationsResponse struct {
@@ -335,6 +608,7 @@ type AddSourceArgs struct {
 	URL      string `json:"url" jsonschema_description:"Git URL to clone (e.g. 'git@github.com:yourorg/your-agents.git')"`
 	Name     string `json:"name,omitempty" jsonschema_description:"Name for the source (derived from URL if not specified)"`
 	Priority int    `json:"priority,omitempty" jsonschema_description:"Priority (lower = higher precedence, 1 = highest, default: 50)"`
+	Ref      string `json:"ref,omitempty" jsonschema_description:"Branch, tag or commit SHA to pin the source to (default: the remote's default branch)"`
 }
 
 type UpdateSourceArgs struct {
@@ -347,6 +621,7 @@ type SourceInfo struct {
 	Priority      int    `json:"priority"`
 	AgentCount    int    `json:"agent_count"`
 	GitRemote     string `json:"git_remote,omitempty"`
+	GitRef        string `json:"git_ref,omitempty"`
 	GitEnabled    bool   `json:"git_enabled"`
 	IsCompliant   bool   `json:"is_compliant"`
 	IssueCount    int    `json:"issue_count,omitempty"`
@@ -361,14 +636,17 @@ type CreateProjectArgs struct {
 	Name        string   `json:"name" jsonschema_description:"Project name (kebab-case for directory)"`
 	Path        string   `json:"path,omitempty" jsonschema_description:"Project directory path (defaults to ~/projects/{name})"`
 	Description string   `json:"description" jsonschema_description:"High-level project description (2-3 paragraphs)"`
-	AgentNames  []string `json:"agent_names" jsonschema_description:"List of agent names to deploy to the project"`
+	AgentNames  []string `json:"agent_names,omitempty" jsonschema_description:"List of agent names to deploy to the project, optionally with a version constraint (e.g. 'frontend@^2.1')"`
+	Bundle      string   `json:"bundle,omitempty" jsonschema_description:"Name of a bundle defined by a source whose agents are deployed along with agent_names (e.g. 'web-stack')"`
 	VisionDoc   string   `json:"vision_doc,omitempty" jsonschema_description:"Focused CLAUDE.md content (vision, not implementation details)"`
+	DryRun      bool     `json:"dry_run,omitempty" jsonschema_description:"Return the files and manifest entries the project would be created with without touching disk (default: false)"`
 }
 
 type CreateProjectResponse struct {
-	ProjectPath    string   `json:"project_path"`
-	AgentsDeployed []string `json:"agents_deployed"`
-	Success        bool     `json:"success"`
+	ProjectPath    string     `json:"project_path"`
+	AgentsDeployed []string   `json:"agents_deployed"`
+	Success        bool       `json:"success"`
+	Plan           *plan.Plan `json:"plan,omitempty"`
 }
 
 type OnboardingState struct {
@@ -408,6 +686,7 @@ type ImportAgentsResponse struct {
 	AgentsImported int             `json:"agents_imported"`
 	Agents         []ImportedAgent `json:"agents"`
 	DryRun         bool            `json:"dry_run"`
+	Plan           *plan.Plan      `json:"plan,omitempty"`
 }
 
 func runMCPServer() {
@@ -449,68 +728,227 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "deploy_agents",
 		Description: "Deploy selected agents to a target project's .claude/agents/ directory. " +
 			"Use this when the user wants to add specific agents to a project. " +
-			"Handles conflict detection and creates necessary directories.",
+			"Handles conflict detection and creates necessary directories. " +
+			"Slash commands are deployed to .claude/commands/ by naming them /name (e.g. '/review'), " +
+			"and skills, with all their files, to .claude/skills/<name>/ by naming them skill:name (e.g. 'skill:pdf'). " +
+			"MCP servers an agent declares (mcpServers frontmatter or a sibling <name>.mcp.json) are merged into the project's .mcp.json without touching servers the user configured. " +
+			"Agent files and manifests are written in one transaction: if any write fails, the whole deployment is rolled back. " +
+			"Agent names accept a semver constraint (name@^2.1) resolved across all sources and git tags; " +
+			"constraints in the project's .claude/cami.yaml are enforced. " +
+			"Agents listing others under requires: in their frontmatter are deployed with everything they require, transitively; " +
+			"missing dependencies and dependency cycles fail the deployment. " +
+			"Set bundle instead of agent_names to deploy a named group of agents a source defines in bundles/*.yaml (see list_agents); " +
+			"the project manifest records the bundle. " +
+			"Set merge to keep local edits to deployed agents while applying source updates. " +
+			"Set dry_run to get the planned file creates, overwrites and manifest changes without deploying. " +
+			"Use target_path 'user' to deploy to ~/.claude/agents, which Claude Code loads in every project; " +
+			"a project agent of the same name takes precedence.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args DeployAgentsArgs) (*mcp.CallToolResult, any, error) {
+		if args.Merge && args.Overwrite {
+			return nil, nil, fmt.Errorf("merge and overwrite cannot be used together")
+		}
+		if args.Merge && args.DryRun {
+			return nil, nil, fmt.Errorf("merge and dry_run cannot be used together")
+		}
+		if args.Bundle != "" && len(args.AgentNames) > 0 {
+			return nil, nil, fmt.Errorf("bundle and agent_names cannot be used together")
+		}
+		if args.Bundle == "" && len(args.AgentNames) == 0 {
+			return nil, nil, fmt.Errorf("agent_names or bundle is required")
+		}
+
+		targetPath, err := config.ExpandUserScope(args.TargetPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		args.TargetPath = targetPath
+
 		// Validate target path
 		if err := deploy.ValidateTargetPath(args.TargetPath); err != nil {
 			return nil, nil, fmt.Errorf("invalid target path: %w", err)
 		}
 
-		// Load all available agents from configured sources
-		allAgents, err := loadAllAgents()
+		resolver, err := newResolver()
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
 		}
 
-		// Filter requested agents
-		agentMap := make(map[string]*agent.Agent)
-		for _, ag := range allAgents {
-			agentMap[ag.Name] = ag
+		var b *bundle.Bundle
+		if args.Bundle != "" {
+			if b, err = findBundle(args.Bundle); err != nil {
+				return nil, nil, err
+			}
+			args.AgentNames = b.Agents
 		}
 
-		var agentsToDeploy []*agent.Agent
-		var notFound []string
-		for _, name := range args.AgentNames {
-			if ag, exists := agentMap[name]; exists {
-				agentsToDeploy = append(agentsToDeploy, ag)
-			} else {
-				notFound = append(notFound, name)
-			}
+		// Resolve each request to a version, honoring the project's spec,
+		// and pull in the agents they require
+		agentsToDeploy, err := resolver.ResolveProject(args.TargetPath, args.AgentNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		var dependencies []string
+		for _, ag := range agentsToDeploy[len(args.AgentNames):] {
+			dependencies = append(dependencies, ag.ID())
+		}
+		if b != nil {
+			agentsToDeploy = b.Tag(agentsToDeploy)
 		}
 
-		if len(notFound) > 0 {
-			return nil, nil, fmt.Errorf("agents not found: %v", notFound)
+		if args.DryRun {
+			cfg, err := config.Load()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load config: %w", err)
+			}
+
+			deployPlan, err := deploy.PlanDeploy(agentsToDeploy, args.TargetPath, args.Overwrite, cfg.AgentSources)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to plan deployment: %w", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: dryRunText(deployPlan)}},
+			}, &DeployAgentsResponse{Results: []DeployResult{}, Dependencies: dependencies, Plan: deployPlan}, nil
 		}
 
 		// Deploy agents
-		results, err := deploy.DeployAgents(agentsToDeploy, args.TargetPath, args.Overwrite)
-		if err != nil {
-			return nil, nil, fmt.Errorf("deployment failed: %w", err)
-		}
+		var results []*deploy.Result
+		if args.Merge {
+			// Merging records manifests itself so merged agents keep their base
+			results, err = deploy.MergeAgents(agentsToDeploy, args.TargetPath)
+			if err != nil {
+				return nil, nil, fmt.Errorf("merge failed: %w", err)
+			}
+		} else {
+			cfg, err := config.Load()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load config: %w", err)
+			}
 
-		// Update manifests to track deployment
-		if err := updateDeploymentManifests(args.TargetPath, agentsToDeploy, results); err != nil {
-			log.Printf("Warning: failed to update deployment manifests: %v", err)
-			// Don't fail the deployment if manifest update fails
+			// Deploy and track in the project and central manifests as one
+			// transaction; any failure rolls the whole deployment back
+			results, err = deploy.DeployAndRecord(agentsToDeploy, args.TargetPath, args.Overwrite, cfg.AgentSources)
+			if err != nil {
+				return nil, nil, fmt.Errorf("deployment failed: %w", err)
+			}
 		}
 
 		// Convert results to response format
 		var deployResults []DeployResult
 		for _, result := range results {
 			deployResults = append(deployResults, DeployResult{
-				AgentName: result.Agent.Name,
+				AgentName: result.Agent.ID(),
 				Success:   result.Success,
 				Message:   result.Message,
 				Conflict:  result.Conflict,
+				Merged:    result.Merged,
 			})
 		}
 
 		// Format response
 		responseText := fmt.Sprintf("Deployed %d agents to %s\n\n", len(agentsToDeploy), args.TargetPath)
+		if len(dependencies) > 0 {
+			responseText += fmt.Sprintf("Including required agents: %s\n\n", strings.Join(dependencies, ", "))
+		}
 		for _, result := range deployResults {
+			status := "✓"
+			if result.Merged && result.Conflict {
+				status = "⚠"
+			} else if !result.Success {
+				status = "✗"
+			}
+			responseText += fmt.Sprintf("%s %s: %s\n", status, result.AgentName, result.Message)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+		}, &DeployAgentsResponse{Results: deployResults, Dependencies: dependencies}, nil
+	})
+
+	// Register undeploy_agents tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "undeploy_agents",
+		Description: "Remove agents from a target project's .claude/agents/ directory. " +
+			"Deletes the agent files, drops them from the project and central manifests, and refreshes the CLAUDE.md agent section. " +
+			"MCP servers CAMI added to .mcp.json for them are removed unless another deployed agent still requires them. " +
+			"Removing an agent that another deployed agent lists under requires: still succeeds, with a warning naming the dependents. " +
+			"Slash commands are removed from .claude/commands/ by naming them /name, and skill directories from .claude/skills/ as skill:name. " +
+			"Set orphaned to remove every tracked agent that no configured source provides anymore instead of naming agents.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args UndeployAgentsArgs) (*mcp.CallToolResult, any, error) {
+		if args.Orphaned == (len(args.AgentNames) > 0) {
+			return nil, nil, fmt.Errorf("specify either agent_names or orphaned")
+		}
+
+		targetPath, err := config.ExpandUserScope(args.TargetPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		args.TargetPath = targetPath
+
+		// Validate target path
+		if err := deploy.ValidateTargetPath(args.TargetPath); err != nil {
+			return nil, nil, fmt.Errorf("invalid target path: %w", err)
+		}
+
+		names := args.AgentNames
+		if args.Orphaned {
+			available, report, err := loadAllArtifacts()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+			}
+			// An agent that failed to load would look orphaned and be removed
+			if len(report.SourceErrors) > 0 || len(report.Errors) > 0 {
+				return nil, nil, fmt.Errorf("some agents failed to load, so orphans can't be told apart - list_agents reports the files that failed")
+			}
+			names, err = deploy.OrphanedAgents(args.TargetPath, available)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if len(names) == 0 {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("No orphaned agents in %s\n", args.TargetPath)},
+				},
+			}, &UndeployAgentsResponse{Results: []UndeployResult{}}, nil
+		}
+
+		results, err := deploy.UndeployAgents(args.TargetPath, names)
+		if err != nil {
+			return nil, nil, fmt.Errorf("remove failed: %w", err)
+		}
+
+		removed := 0
+		var undeployResults []UndeployResult
+		for _, result := range results {
+			if result.Success {
+				removed++
+			}
+			undeployResults = append(undeployResults, UndeployResult{
+				AgentName:  result.Name,
+				Success:    result.Success,
+				Message:    result.Message,
+				Dependents: result.Dependents,
+			})
+		}
+
+		if removed > 0 {
+			if err := docs.RefreshCLAUDEmd(args.TargetPath, "Deployed Agents"); err != nil {
+				log.Printf("Warning: failed to update CLAUDE.md: %v", err)
+			}
+		}
+
+		// Format response
+		responseText := fmt.Sprintf("Removed %d agents from %s\n\n", removed, args.TargetPath)
+		for _, result := range undeployResults {
 			status := "✓"
 			if !result.Success {
 				status = "✗"
+			} else if len(result.Dependents) > 0 {
+				status = "⚠"
 			}
 			responseText += fmt.Sprintf("%s %s: %s\n", status, result.AgentName, result.Message)
 		}
@@ -519,7 +957,251 @@ func registerMCPTools(server *mcp.Server) {
 			Content: []mcp.Content{
 				&mcp.TextContent{Text: responseText},
 			},
-		}, &DeployAgentsResponse{Results: deployResults}, nil
+		}, &UndeployAgentsResponse{Results: undeployResults}, nil
+	})
+
+	// Register sync_projects tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "sync_projects",
+		Description: "Bring tracked projects up to date with their agent sources. " +
+			"Compares every agent in each project's manifest against the configured sources and redeploys changed ones. " +
+			"Honors version constraints in each project's .claude/cami.yaml. " +
+			"Skips custom overrides, locally modified files, and agents no source provides anymore. " +
+			"Syncs all configured locations and manifest-tracked projects unless specific locations are given.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SyncProjectsArgs) (*mcp.CallToolResult, any, error) {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Resolve projects to sync
+		var projects []string
+		if len(args.Locations) == 0 {
+			projects = deploy.TrackedProjects(cfg)
+		} else {
+			for _, loc := range args.Locations {
+				path, err := deploy.ResolveProjectPath(cfg, loc)
+				if err != nil {
+					return nil, nil, err
+				}
+				projects = append(projects, path)
+			}
+		}
+
+		resolver, err := newResolver()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+
+		response := &SyncProjectsResponse{
+			DryRun:   args.DryRun,
+			Projects: []SyncProjectResult{},
+		}
+
+		var responseText string
+		if args.DryRun {
+			responseText = "Sync plan (dry run):\n\n"
+		} else {
+			responseText = "Sync results:\n\n"
+		}
+
+		for _, projectPath := range projects {
+			project := SyncProjectResult{
+				Path:    projectPath,
+				Updated: []string{},
+				Failed:  []string{},
+			}
+			responseText += projectPath + "\n"
+
+			plan, err := deploy.PlanSync(projectPath, resolver)
+			if err != nil {
+				project.Error = err.Error()
+				response.Projects = append(response.Projects, project)
+				responseText += fmt.Sprintf("  ✗ %s\n\n", err)
+				continue
+			}
+			project.Items = plan.Items
+
+			if !args.DryRun {
+				results, err := deploy.ApplySync(plan)
+				for _, result := range results {
+					if result.Success {
+						project.Updated = append(project.Updated, result.Agent.ID())
+					} else {
+						project.Failed = append(project.Failed, result.Agent.ID())
+					}
+				}
+				if err != nil {
+					project.Error = err.Error()
+				}
+			}
+
+			if len(plan.Items) == 0 {
+				responseText += "  No agents tracked in manifest\n"
+			}
+			for _, item := range plan.Items {
+				switch item.Action {
+				case deploy.SyncUpdate:
+					responseText += fmt.Sprintf("  ↑ %s: %s → %s\n", item.Name, item.FromVersion, item.ToVersion)
+				case deploy.SyncUpToDate:
+					responseText += fmt.Sprintf("  ✓ %s: up to date\n", item.Name)
+				default:
+					responseText += fmt.Sprintf("  ⚠ %s: skipped (%s)\n", item.Name, item.Reason)
+				}
+			}
+			for _, name := range project.Failed {
+				responseText += fmt.Sprintf("  ✗ %s: deployment failed\n", name)
+			}
+			if project.Error != "" {
+				responseText += fmt.Sprintf("  ✗ %s\n", project.Error)
+			}
+			responseText += "\n"
+
+			response.Projects = append(response.Projects, project)
+		}
+
+		if len(projects) == 0 {
+			responseText += "No tracked projects found\n"
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+		}, response, nil
+	})
+
+	// Register apply_project tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "apply_project",
+		Description: "Converge a project's .claude/agents/ to the agents declared in its .claude/cami.yaml spec. " +
+			"Adds and updates spec agents, and the agents they require, to the versions the constraints resolve to, and removes tracked agents that are neither listed nor required. " +
+			"Leaves custom overrides, locally edited files and agents CAMI didn't deploy alone. " +
+			"Use dry_run to review the planned adds, updates and removals first.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ApplyProjectArgs) (*mcp.CallToolResult, any, error) {
+		targetPath, err := config.ExpandUserScope(args.TargetPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		args.TargetPath = targetPath
+
+		if err := deploy.ValidateTargetPath(args.TargetPath); err != nil {
+			return nil, nil, fmt.Errorf("invalid target path: %w", err)
+		}
+
+		plan, results, err := applyProjectSpec(args.TargetPath, args.DryRun, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(results) > 0 {
+			if err := docs.RefreshCLAUDEmd(args.TargetPath, "Deployed Agents"); err != nil {
+				log.Printf("Warning: failed to update CLAUDE.md: %v", err)
+			}
+		}
+
+		response := &ApplyProjectResponse{
+			Path:    args.TargetPath,
+			DryRun:  args.DryRun,
+			Items:   plan.Items,
+			Results: results,
+		}
+		if response.Items == nil {
+			response.Items = []*deploy.ApplyItem{}
+		}
+		if response.Results == nil {
+			response.Results = []DeployResult{}
+		}
+
+		responseText := fmt.Sprintf("Apply plan for %s", args.TargetPath)
+		if args.DryRun {
+			responseText += " (dry run)"
+		}
+		responseText += ":\n\n"
+		for _, item := range plan.Items {
+			switch item.Action {
+			case deploy.ApplyAdd:
+				responseText += fmt.Sprintf("+ %s: add v%s\n", item.Name, item.ToVersion)
+			case deploy.ApplyUpdate:
+				responseText += fmt.Sprintf("~ %s: v%s → v%s\n", item.Name, item.FromVersion, item.ToVersion)
+			case deploy.ApplyRemove:
+				responseText += fmt.Sprintf("- %s: remove\n", item.Name)
+			case deploy.ApplyUnchanged:
+				responseText += fmt.Sprintf("✓ %s: up to date\n", item.Name)
+			default:
+				responseText += fmt.Sprintf("⚠ %s: skipped (%s)\n", item.Name, item.Reason)
+			}
+		}
+		if len(plan.Changes()) == 0 {
+			responseText += "\nNothing to apply\n"
+		}
+
+		for _, result := range results {
+			if !result.Success {
+				responseText += fmt.Sprintf("\n✗ %s: %s", result.AgentName, result.Message)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+		}, response, nil
+	})
+
+	// Register diff_agent tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "diff_agent",
+		Description: "Show a unified diff between deployed agent files and their source. " +
+			"Compares each agent with the source version a sync would deploy, honoring .claude/cami.yaml constraints. " +
+			"Frontmatter and body changes are reported separately, so edits made without a version bump are caught. " +
+			"Diffs every deployed agent, slash command and skill unless specific names are given (commands as /name, skills as skill:name; a skill's SKILL.md is diffed).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args DiffAgentArgs) (*mcp.CallToolResult, any, error) {
+		if err := deploy.ValidateTargetPath(args.TargetPath); err != nil {
+			return nil, nil, fmt.Errorf("invalid target path: %w", err)
+		}
+
+		resolver, err := newResolver()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+
+		diffs, err := deploy.DiffProject(args.TargetPath, args.AgentNames, resolver)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		response := &DiffAgentResponse{Path: args.TargetPath, Agents: diffs}
+		if response.Agents == nil {
+			response.Agents = []*deploy.AgentDiff{}
+		}
+
+		responseText := fmt.Sprintf("Agent diff for %s:\n\n", args.TargetPath)
+		if len(diffs) == 0 {
+			responseText += "No agents deployed\n"
+		}
+		for _, d := range diffs {
+			switch {
+			case d.Error != "":
+				responseText += fmt.Sprintf("⚠ %s: %s\n\n", d.Name, d.Error)
+			case !d.Changed():
+				responseText += fmt.Sprintf("✓ %s: matches source\n\n", d.Name)
+			default:
+				responseText += fmt.Sprintf("✗ %s: v%s deployed, v%s in source\n\n", d.Name, d.DeployedVersion, d.SourceVersion)
+				if d.FrontmatterChanged {
+					responseText += fmt.Sprintf("**Frontmatter changes:**\n```diff\n%s```\n\n", d.FrontmatterDiff)
+				}
+				if d.BodyChanged {
+					responseText += fmt.Sprintf("**Body changes:**\n```diff\n%s```\n\n", d.BodyDiff)
+				}
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+		}, response, nil
 	})
 
 	// Register update_claude_md tool
@@ -564,13 +1246,18 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "list_agents",
 		Description: "List all available agents from CAMI's version-controlled agent repository. " +
 			"Returns agent names, versions, descriptions, and categories. " +
+			"Slash commands from the sources' commands/ folders are listed separately as /name, and skills from their skills/ folders as skill:name. " +
+			"Bundles, named groups of agents deployable as one unit with the bundle parameter of deploy_agents and create_project, are listed last. " +
+			"A load report explains any agent that is missing: files that failed to load, schema problems, sources that couldn't be read, files excluded by .camiignore and copies overridden by a higher priority source. " +
 			"Use this to discover what agents are available for deployment.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
 		// Load all available agents from configured sources
-		agents, err := loadAllAgents()
+		report := &agent.LoadReport{}
+		agents, agentReport, err := loadAllAgents()
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
 		}
+		report.Merge(agentReport)
 
 		// Group agents by category
 		categoryMap := make(map[string][]*agent.Agent)
@@ -617,25 +1304,168 @@ func registerMCPTools(server *mcp.Server) {
 			}
 		}
 
+		commands, commandReport, err := loadAllCommands()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load commands: %w", err)
+		}
+		report.Merge(commandReport)
+
+		skills, skillReport, err := loadAllSkills()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load skills: %w", err)
+		}
+		report.Merge(skillReport)
+
+		bundles, bundleReport, err := loadAllBundles()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load bundles: %w", err)
+		}
+		report.Merge(bundleReport)
+
+		commandText, commandInfos := artifactSection("Slash Commands", commands)
+		skillText, skillInfos := artifactSection("Skills", skills)
+		responseText += commandText + skillText
+
+		if len(bundles) > 0 {
+			responseText += fmt.Sprintf("## Bundles (%d)\n\n", len(bundles))
+			for _, b := range bundles {
+				responseText += fmt.Sprintf("• %s: %s\n", b.Name, strings.Join(b.Agents, ", "))
+				if b.Description != "" {
+					responseText += fmt.Sprintf("  %s\n", b.Description)
+				}
+				responseText += "\n"
+			}
+		}
+
+		responseText += loadReportText(report)
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				&mcp.TextContent{Text: responseText},
 			},
-		}, &ListAgentsResponse{Agents: agentInfos}, nil
+		}, &ListAgentsResponse{Agents: agentInfos, Commands: commandInfos, Skills: skillInfos, Bundles: bundles, LoadReport: report}, nil
+	})
+
+	// Register explain_agent tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "explain_agent",
+		Description: "Explain which source an agent is deployed from. " +
+			"Lists every configured source that provides the agent with its version, file path and priority, marks the copy that is used, and says why it wins. " +
+			"The source with the lowest priority number wins; the other copies are shadowed and never deployed. " +
+			"Use this when a deployed agent isn't the version or content you expect.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ExplainAgentArgs) (*mcp.CallToolResult, any, error) {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+
+		available, report, err := loadAllArtifacts()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+
+		resolution := agent.Explain(args.AgentName, available, report, configAgentSources(cfg))
+		if resolution == nil {
+			return nil, nil, fmt.Errorf("no source provides %s - list_agents reports files that failed to load", args.AgentName)
+		}
+
+		responseText := fmt.Sprintf("Sources providing %s:\n\n", resolution.ID)
+		for _, p := range resolution.Providers {
+			status := "shadowed"
+			if p.Wins {
+				status = "used"
+			}
+			version := "unversioned"
+			if p.Version != "" {
+				version = "v" + p.Version
+			}
+			responseText += fmt.Sprintf("• %s (priority %d, %s) - %s\n  %s\n", p.Source, p.Priority, version, status, p.File)
+		}
+		responseText += fmt.Sprintf("\n%s wins: %s\n", resolution.Providers[0].Source, resolution.Reason)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: responseText}},
+		}, resolution, nil
+	})
+
+	// Register search_agents tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "search_agents",
+		Description: "Search available agents by relevance over their name, specialty, description and body text. " +
+			"Every word of the query must match; matches in the name rank highest. " +
+			"Filter by class, category, source or a version constraint, alone or with a query. " +
+			"Returns the top results with a snippet of where each matched, and counts of class, category and source over all matches to narrow the search. " +
+			"Use this instead of list_agents to find agents for a task.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchAgentsArgs) (*mcp.CallToolResult, any, error) {
+		filter := search.Filter{Class: args.Class, Category: args.Category, Source: args.Source, Version: args.Version}
+		if args.Query == "" && filter == (search.Filter{}) {
+			return nil, nil, fmt.Errorf("give a query or a filter - use list_agents to see every agent")
+		}
+
+		agents, report, err := loadAllAgents()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+
+		results, err := search.New(agents).Search(args.Query, filter, args.Limit)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var responseText string
+		if results.Total == 0 {
+			responseText = "No agents found.\n"
+		} else {
+			responseText = fmt.Sprintf("Found %d agents, showing %d:\n\n", results.Total, len(results.Results))
+			for _, r := range results.Results {
+				version := "unversioned"
+				if r.Version != "" {
+					version = "v" + r.Version
+				}
+				responseText += fmt.Sprintf("• %s (%s, %s, score %.2f)\n", r.ID, version, r.Source, r.Score)
+				if r.Snippet != "" {
+					responseText += fmt.Sprintf("  %s\n", r.Snippet)
+				}
+			}
+			for _, facet := range []string{"class", "category", "source"} {
+				if line := search.FacetLine(results.Facets[facet]); line != "" {
+					responseText += fmt.Sprintf("\n%s: %s", facet, line)
+				}
+			}
+			responseText += "\n"
+		}
+		if report.HasProblems() {
+			responseText += fmt.Sprintf("\nWarning: %d load problem(s) - list_agents reports them\n", len(report.Problems()))
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: responseText}},
+		}, results, nil
 	})
 
 	// Register scan_deployed_agents tool
 	mcp.AddTool(server, &mcp.Tool{
 		Name: "scan_deployed_agents",
 		Description: "Scan a project directory to find deployed agents and compare with available versions. " +
-			"Returns agent status (current, outdated, unknown). " +
+			"Returns agent status: up-to-date, update-available, not-deployed or unknown. " +
+			"Projects with a CAMI manifest are compared by content hash and can also report " +
+			"locally-modified (deployed file edited), source-modified (source changed), both-modified, " +
+			"orphaned (no source provides it) and untracked (not recorded in the manifest). " +
+			"Project agents that override a user-scope agent of the same name are flagged with overrides_user; " +
+			"scanning target_path 'user' lists the locations shadowing each user-scope agent. " +
 			"Use this to audit what agents are deployed.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args ScanDeployedAgentsArgs) (*mcp.CallToolResult, any, error) {
+		targetPath, err := config.ExpandUserScope(args.TargetPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		args.TargetPath = targetPath
+
 		if err := deploy.ValidateTargetPath(args.TargetPath); err != nil {
 			return nil, nil, fmt.Errorf("invalid target path: %w", err)
 		}
 
-		availableAgents, err := loadAllAgents()
+		availableAgents, _, err := loadAllAgents()
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
 		}
@@ -651,59 +1481,65 @@ func registerMCPTools(server *mcp.Server) {
 			}, &ScanDeployedAgentsResponse{Statuses: statusInfos}, nil
 		}
 
-		deployedAgents := make(map[string]*agent.Agent)
-		files, err := os.ReadDir(agentsDir)
+		location := &config.DeployLocation{
+			Name: filepath.Base(args.TargetPath),
+			Path: args.TargetPath,
+		}
+		locationStatus, err := discovery.ScanLocation(location, availableAgents)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read agents directory: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan agents: %w", err)
 		}
 
-		for _, file := range files {
-			if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
-				continue
+		// The user scope is scanned with every location to find what shadows it
+		if config.IsUserScope(args.TargetPath) {
+			cfg, err := config.Load()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load config: %w", err)
 			}
-			agentPath := filepath.Join(agentsDir, file.Name())
-			deployedAgent, err := agent.LoadAgent(agentPath)
+			result, err := discovery.ScanAllLocations(cfg.DeployTargets(), availableAgents)
 			if err != nil {
-				continue
+				return nil, nil, fmt.Errorf("failed to scan agents: %w", err)
+			}
+			for _, locStatus := range result.LocationStatuses {
+				if config.IsUserScope(locStatus.Location.Path) {
+					locationStatus = locStatus
+				}
 			}
-			deployedAgents[deployedAgent.Name] = deployedAgent
 		}
 
-		responseText += fmt.Sprintf("Found %d deployed agents\n\n", len(deployedAgents))
-
-		for _, availableAgent := range availableAgents {
-			status := "not-deployed"
-			deployedVersion := ""
-
-			if deployedAgent, exists := deployedAgents[availableAgent.Name]; exists {
-				deployedVersion = deployedAgent.Version
-				if deployedAgent.Version == availableAgent.Version {
-					status = "up-to-date"
-				} else {
-					status = "update-available"
-				}
+		deployedCount := 0
+		for _, status := range locationStatus.AgentStatuses {
+			if status.Status != discovery.StatusNotDeployed {
+				deployedCount++
 			}
+		}
+		responseText += fmt.Sprintf("Found %d deployed agents\n\n", deployedCount)
 
+		for _, status := range locationStatus.AgentStatuses {
 			statusInfos = append(statusInfos, AgentStatusInfo{
-				Name:             availableAgent.Name,
-				DeployedVersion:  deployedVersion,
-				AvailableVersion: availableAgent.Version,
-				Status:           status,
+				Name:             status.Agent.Name,
+				DeployedVersion:  status.DeployedVersion,
+				AvailableVersion: status.AvailableVersion,
+				Status:           string(status.Status),
+				OverridesUser:    status.OverridesUser,
+				ShadowedBy:       status.ShadowedBy,
 			})
 
-			statusSymbol := "○"
-			if status == "up-to-date" {
-				statusSymbol = "✓"
-			} else if status == "update-available" {
-				statusSymbol = "⚠"
+			versionInfo := ""
+			if status.DeployedVersion != "" && status.AvailableVersion != "" {
+				versionInfo = fmt.Sprintf(" (deployed: v%s, available: v%s)", status.DeployedVersion, status.AvailableVersion)
+			} else if status.DeployedVersion != "" {
+				versionInfo = fmt.Sprintf(" (deployed: v%s)", status.DeployedVersion)
 			}
 
-			versionInfo := ""
-			if deployedVersion != "" {
-				versionInfo = fmt.Sprintf(" (deployed: v%s, available: v%s)", deployedVersion, availableAgent.Version)
+			if status.OverridesUser {
+				versionInfo += ", overrides user-scope agent"
+			}
+			if len(status.ShadowedBy) > 0 {
+				versionInfo += fmt.Sprintf(", shadowed in %s", strings.Join(status.ShadowedBy, ", "))
 			}
 
-			responseText += fmt.Sprintf("%s %s: %s%s\n", statusSymbol, availableAgent.Name, status, versionInfo)
+			responseText += fmt.Sprintf("%s %s: %s%s\n", discovery.GetStatusSymbol(status.Status), status.Agent.Name, status.Status, versionInfo)
 		}
 
 		return &mcp.CallToolResult{
@@ -751,8 +1587,9 @@ func registerMCPTools(server *mcp.Server) {
 	})
 
 	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_locations",
-		Description: "List all configured deployment locations in CAMI. Use this to see what project directories are registered for agent deployment.",
+		Name: "list_locations",
+		Description: "List all configured deployment locations in CAMI. Use this to see what project directories are registered for agent deployment. " +
+			"The user scope ('user', ~/.claude/agents, loaded in every project) is always listed first.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
 		cfg, err := config.Load()
 		if err != nil {
@@ -760,15 +1597,21 @@ func registerMCPTools(server *mcp.Server) {
 		}
 
 		var locationInfos []LocationInfo
-		responseText := fmt.Sprintf("Configured locations (%d total):\n\n", len(cfg.Locations))
+		targets := cfg.DeployTargets()
+		responseText := fmt.Sprintf("Deployment locations (%d total):\n\n", len(targets))
 
-		if len(cfg.Locations) == 0 {
-			responseText += "No locations configured yet. Use add_location to register a project directory.\n"
-		} else {
-			for _, loc := range cfg.Locations {
-				locationInfos = append(locationInfos, LocationInfo{Name: loc.Name, Path: loc.Path})
-				responseText += fmt.Sprintf("• %s\n  %s\n\n", loc.Name, loc.Path)
+		for _, loc := range targets {
+			if loc.Name == config.UserScopeName {
+				locationInfos = append(locationInfos, LocationInfo{Name: loc.Name, Path: loc.Path, Scope: config.UserScopeName})
+				responseText += fmt.Sprintf("• %s (user scope)\n  %s\n\n", loc.Name, filepath.Join(loc.Path, ".claude", "agents"))
+				continue
 			}
+			locationInfos = append(locationInfos, LocationInfo{Name: loc.Name, Path: loc.Path, Scope: "project"})
+			responseText += fmt.Sprintf("• %s\n  %s\n\n", loc.Name, loc.Path)
+		}
+
+		if len(cfg.Locations) == 0 {
+			responseText += "No project locations configured yet. Use add_location to register a project directory.\n"
 		}
 
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: responseText}}}, &ListLocationsResponse{Locations: locationInfos}, nil
@@ -861,6 +1704,7 @@ func registerMCPTools(server *mcp.Server) {
 
 				if source.Git != nil && source.Git.Enabled {
 					sourceInfo.GitRemote = source.Git.Remote
+					sourceInfo.GitRef = source.Git.Ref
 				}
 
 				sourceInfos = append(sourceInfos, sourceInfo)
@@ -913,7 +1757,8 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "add_source",
 		Description: "Add a new agent source by cloning a Git repository. " +
 			"The repository will be cloned to your CAMI workspace sources/ directory and added to configuration. " +
-			"Use this to add official agent libraries or team/company agent sources.",
+			"Use this to add official agent libraries or team/company agent sources. " +
+			"Set ref to pin the source to a branch, tag or commit SHA.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args AddSourceArgs) (*mcp.CallToolResult, any, error) {
 		name := args.Name
 		if name == "" {
@@ -962,6 +1807,13 @@ func registerMCPTools(server *mcp.Server) {
 			return nil, nil, fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
 		}
 
+		if args.Ref != "" {
+			if err := git.Checkout(targetPath, args.Ref); err != nil {
+				_ = os.RemoveAll(targetPath)
+				return nil, nil, fmt.Errorf("failed to check out %s: %w", args.Ref, err)
+			}
+		}
+
 		agents, err := agent.LoadAgentsFromPath(targetPath)
 		agentCount := 0
 		if err == nil {
@@ -976,6 +1828,7 @@ func registerMCPTools(server *mcp.Server) {
 			Git: &config.GitConfig{
 				Enabled: true,
 				Remote:  args.URL,
+				Ref:     args.Ref,
 			},
 		}
 
@@ -988,6 +1841,9 @@ func registerMCPTools(server *mcp.Server) {
 		}
 
 		responseText := fmt.Sprintf("✓ Cloned %s to %s/sources/%s\n", name, configDir, name)
+		if args.Ref != "" {
+			responseText += fmt.Sprintf("✓ Pinned to %s\n", args.Ref)
+		}
 		responseText += fmt.Sprintf("✓ Added source with priority %d\n", priority)
 		responseText += fmt.Sprintf("✓ Found %d agents\n\n", agentCount)
 
@@ -1025,6 +1881,7 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "update_source",
 		Description: "Update (git pull) agent sources. " +
 			"If no name is specified, updates all sources with git remotes. " +
+			"Sources pinned to a branch follow it; sources pinned to a tag or commit stay put. " +
 			"Use this to get the latest agents from configured sources.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args UpdateSourceArgs) (*mcp.CallToolResult, any, error) {
 		cfg, err := config.Load()
@@ -1045,21 +1902,22 @@ func registerMCPTools(server *mcp.Server) {
 				continue
 			}
 
-			responseText += fmt.Sprintf("Updating %s...\n", source.Name)
-
-			cmd := exec.Command("git", "-C", source.Path, "pull")
-			output, err := cmd.CombinedOutput()
+			if source.Git.Ref != "" {
+				responseText += fmt.Sprintf("Updating %s (%s)...\n", source.Name, source.Git.Ref)
+			} else {
+				responseText += fmt.Sprintf("Updating %s...\n", source.Name)
+			}
 
+			moved, err := git.Update(source.Path, source.Git.Ref)
 			if err != nil {
 				responseText += fmt.Sprintf("  ✗ Failed: %v\n", err)
 				continue
 			}
 
-			outputStr := string(output)
-			if strings.Contains(outputStr, "Already up to date") {
-				responseText += "  ✓ Up to date\n"
-			} else {
+			if moved {
 				responseText += "  ✓ Updated\n"
+			} else {
+				responseText += "  ✓ Up to date\n"
 			}
 
 			updated = append(updated, source.Name)
@@ -1246,6 +2104,10 @@ func registerMCPTools(server *mcp.Server) {
 			"5) Write a focused vision_doc (200-300 words, vision NOT implementation) " +
 			"6) Invoke this tool with name, description, agent_names, and vision_doc " +
 			"7) Confirm success and guide user to next steps. " +
+			"The agents are declared in the project's .claude/cami.yaml spec (names accept name@constraint) and deployed by applying it, " +
+			"so mcp__cami__apply_project keeps the project in line later. " +
+			"Set bundle to also deploy every agent of a bundle a source defines (see list_agents); the project manifest records the bundle. " +
+			"Set dry_run to get the planned files and manifest entries without creating anything. " +
 			"IMPORTANT: NEVER skip steps 1-3. Always gather requirements and confirm agents BEFORE using this tool.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args CreateProjectArgs) (*mcp.CallToolResult, any, error) {
 		// Validate project name
@@ -1277,6 +2139,32 @@ func registerMCPTools(server *mcp.Server) {
 			return nil, nil, fmt.Errorf("project directory already exists: %s", projectPath)
 		}
 
+		// Deploy the bundle's agents along with any named ones
+		var b *bundle.Bundle
+		if args.Bundle != "" {
+			var err error
+			if b, err = findBundle(args.Bundle); err != nil {
+				return nil, nil, err
+			}
+			args.AgentNames = bundleRequests(b, args.AgentNames)
+		}
+
+		if args.DryRun {
+			createPlan, err := planCreateProject(projectPath, args, b)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: dryRunText(createPlan)}},
+			}, &CreateProjectResponse{
+				ProjectPath:    projectPath,
+				AgentsDeployed: []string{},
+				Success:        true,
+				Plan:           createPlan,
+			}, nil
+		}
+
 		// Create project directory
 		if err := os.MkdirAll(projectPath, 0755); err != nil {
 			return nil, nil, fmt.Errorf("failed to create project directory: %w", err)
@@ -1293,60 +2181,29 @@ func registerMCPTools(server *mcp.Server) {
 			return nil, nil, fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Convert config sources to agent sources for loading
-		agentSources := make([]agent.AgentSource, len(cfg.AgentSources))
-		for i, src := range cfg.AgentSources {
-			agentSources[i] = agent.AgentSource{
-				Path:     src.Path,
-				Priority: src.Priority,
-			}
-		}
-
-		allAgents, err := agent.LoadAgentsFromSources(agentSources)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
-		}
-
-		// Find agents to deploy
-		var agentsToDeploy []*agent.Agent
-		var notFound []string
-		for _, agentName := range args.AgentNames {
-			var found *agent.Agent
-			for _, a := range allAgents {
-				if a.Name == agentName {
-					found = a
-					break
-				}
-			}
-
-			if found == nil {
-				notFound = append(notFound, agentName)
-			} else {
-				agentsToDeploy = append(agentsToDeploy, found)
+		// Declare the agents in the project spec and converge to it
+		projectSpec := &spec.Spec{}
+		for _, name := range args.AgentNames {
+			requirement, err := spec.ParseRequirement(name)
+			if err != nil {
+				return nil, nil, err
 			}
+			projectSpec.Set(requirement)
 		}
-
-		if len(notFound) > 0 {
-			return nil, nil, fmt.Errorf("agents not found: %v", notFound)
+		if err := spec.Write(projectPath, projectSpec); err != nil {
+			return nil, nil, err
 		}
 
-		// Deploy agents using the proper deployment function
-		results, err := deploy.DeployAgents(agentsToDeploy, projectPath, false)
+		_, results, err := applyProjectSpec(projectPath, false, b)
 		if err != nil {
-			return nil, nil, fmt.Errorf("deployment failed: %w", err)
-		}
-
-		// Update manifests to track deployment
-		if err := updateDeploymentManifests(projectPath, agentsToDeploy, results); err != nil {
-			log.Printf("Warning: failed to update deployment manifests: %v", err)
-			// Don't fail the deployment if manifest update fails
+			return nil, nil, err
 		}
 
 		// Collect successfully deployed agent names
 		deployedAgents := []string{}
 		for _, result := range results {
 			if result.Success {
-				deployedAgents = append(deployedAgents, result.Agent.Name)
+				deployedAgents = append(deployedAgents, result.AgentName)
 			}
 		}
 
@@ -1453,7 +2310,7 @@ func registerMCPTools(server *mcp.Server) {
 				Priority: src.Priority,
 			}
 		}
-		allAgents, err := agent.LoadAgentsFromSources(agentSources)
+		allAgents, _, err := agent.LoadAgentsFromSources(agentSources)
 		if err == nil {
 			state.TotalAgents = len(allAgents)
 		}
@@ -1627,7 +2484,7 @@ func registerMCPTools(server *mcp.Server) {
 			"1) Scan the specified project's .claude/agents/ directory " +
 			"2) Parse agent frontmatter (name, version, description) " +
 			"3) Try to match agents to known sources (by name + version + content hash) " +
-			"4) Show preview of what will be imported (use dry_run=true) " +
+			"4) Show preview of what will be imported and the manifest changes planned (use dry_run=true) " +
 			"5) Ask user to confirm import " +
 			"6) Create manifest entries with origin='external' or origin='cami' if matched " +
 			"7) Update local and central manifests " +
@@ -1657,7 +2514,7 @@ func registerMCPTools(server *mcp.Server) {
 					Priority: src.Priority,
 				}
 			}
-			availableAgents, _ = agent.LoadAgentsFromSources(agentSources)
+			availableAgents, _, _ = agent.LoadAgentsFromSources(agentSources)
 		}
 
 		// Create source map for lookups
@@ -1802,8 +2659,27 @@ func registerMCPTools(server *mcp.Server) {
 			responseText += "\n"
 		}
 
+		projectManifest, err := deploy.LoadProjectManifest(args.ProjectPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read project manifest: %w", err)
+		}
+		deploy.RecordFound(projectManifest, deployedAgents...)
+
 		if args.DryRun {
+			importPlan := plan.New("import agents", args.ProjectPath)
+			var existing *manifest.ProjectManifest
+			if _, err := os.Stat(filepath.Join(args.ProjectPath, manifest.ProjectManifestFilename)); err == nil {
+				if existing, err = manifest.ReadProjectManifest(args.ProjectPath); err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := importPlan.WriteManifests(args.ProjectPath, existing, projectManifest); err != nil {
+				return nil, nil, err
+			}
+			response.Plan = importPlan
+
 			responseText += "---\n\n"
+			responseText += importPlan.Text() + "\n"
 			responseText += "**This is a dry run - no changes were made.**\n\n"
 			responseText += "To import these agents, call this tool again with `dry_run=false`.\n"
 
@@ -1812,38 +2688,10 @@ func registerMCPTools(server *mcp.Server) {
 			}, &response, nil
 		}
 
-		// Actually import - create manifests
-		projectManifest := &manifest.ProjectManifest{
-			Version:      "1",
-			State:        manifest.StateCAMINative,
-			NormalizedAt: now,
-			Agents:       deployedAgents,
-		}
-
-		if err := manifest.WriteProjectManifest(args.ProjectPath, projectManifest); err != nil {
-			return nil, nil, fmt.Errorf("failed to write project manifest: %w", err)
-		}
-
-		// Update central manifest
-		centralManifest, err := manifest.ReadCentralManifest()
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read central manifest: %w", err)
-		}
-
-		absPath, err := filepath.Abs(args.ProjectPath)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get absolute path: %w", err)
-		}
-
-		centralManifest.Deployments[absPath] = manifest.ProjectDeployment{
-			State:        manifest.StateCAMINative,
-			NormalizedAt: now,
-			LastScanned:  now,
-			Agents:       deployedAgents,
-		}
+		// Actually import - record in the manifests
 
-		if err := manifest.WriteCentralManifest(centralManifest); err != nil {
-			return nil, nil, fmt.Errorf("failed to write central manifest: %w", err)
+		if err := deploy.WriteManifests(args.ProjectPath, projectManifest); err != nil {
+			return nil, nil, fmt.Errorf("failed to write manifests: %w", err)
 		}
 
 		response.AgentsImported = len(importedAgents)
@@ -1925,12 +2773,14 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "normalize_source",
 		Description: "Fix source agents to meet CAMI standards. " +
 			"Can add missing versions (v1.0.0), description placeholders, and create .camiignore. " +
-			"Creates backup before making changes.",
+			"Creates backup before making changes. " +
+			"Set dry_run to get the planned file changes without touching disk.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args struct {
 		SourceName       string `json:"source_name"`
 		AddVersions      bool   `json:"add_versions"`
 		AddDescriptions  bool   `json:"add_descriptions"`
 		CreateCAMIIgnore bool   `json:"create_camiignore"`
+		DryRun           bool   `json:"dry_run,omitempty"`
 	}) (*mcp.CallToolResult, any, error) {
 		// Load config to get source path
 		cfg, err := config.Load()
@@ -1951,6 +2801,17 @@ func registerMCPTools(server *mcp.Server) {
 			CreateCAMIIgnore: args.CreateCAMIIgnore,
 		}
 
+		if args.DryRun {
+			normalizePlan, err := normalize.PlanNormalizeSource(args.SourceName, source.Path, options)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to plan normalization: %w", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: dryRunText(normalizePlan)}},
+			}, normalizePlan, nil
+		}
+
 		result, err := normalize.NormalizeSource(args.SourceName, source.Path, options)
 		if err != nil {
 			return nil, nil, fmt.Errorf("normalization failed: %w", err)
@@ -2058,10 +2919,12 @@ func registerMCPTools(server *mcp.Server) {
 		Name: "normalize_project",
 		Description: "Normalize a project by creating manifests and linking agents to sources. " +
 			"Supports minimal (just manifests) and standard (manifests + source links) levels. " +
-			"Creates backup before making changes.",
+			"Creates backup before making changes. " +
+			"Set dry_run to get the planned file and manifest changes without touching disk.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args struct {
 		ProjectPath string `json:"project_path"`
 		Level       string `json:"level"` // "minimal" or "standard"
+		DryRun      bool   `json:"dry_run,omitempty"`
 	}) (*mcp.CallToolResult, any, error) {
 		// Load config to get available sources
 		cfg, err := config.Load()
@@ -2087,6 +2950,17 @@ func registerMCPTools(server *mcp.Server) {
 			Level: level,
 		}
 
+		if args.DryRun {
+			normalizePlan, err := normalize.PlanNormalizeProject(args.ProjectPath, options, cfg.AgentSources)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to plan normalization: %w", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: dryRunText(normalizePlan)}},
+			}, normalizePlan, nil
+		}
+
 		result, err := normalize.NormalizeProject(args.ProjectPath, options, cfg.AgentSources)
 		if err != nil {
 			return nil, nil, fmt.Errorf("normalization failed: %w", err)
@@ -2,9 +2,12 @@ package agent
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -18,8 +21,27 @@ type Agent struct {
 	Class       string `yaml:"class,omitempty"`     // workflow-specialist, technology-implementer, strategic-planner
 	Specialty   string `yaml:"specialty,omitempty"` // Domain/specialty (e.g., "kubernetes-operations", "react-development")
 	Category    string `yaml:"-"`                   // Folder name (e.g., "core", "specialized")
+	Kind        Kind   `yaml:"-"`                   // KindCommand or KindSkill; empty or KindAgent for agents
+	Source      string `yaml:"-"`                   // Name of the source the agent was loaded from
+	Ref         string `yaml:"-"`                   // Git tag the agent was read from; empty for the working tree
+	Bundle      string `yaml:"-"`                   // Bundle the agent is being deployed from, if any
 	FilePath    string `yaml:"-"`
 	Content     string `yaml:"-"`
+
+	// Files are a skill's supporting files, keyed by slash-separated path
+	// relative to its directory
+	Files map[string][]byte `yaml:"-" json:"-"`
+
+	// MCPConfig is the content of the agent's sibling .mcp.json file in its
+	// source, if it has one; see MCPServers
+	MCPConfig []byte `yaml:"-" json:"-"`
+
+	// Frontmatter is the full ordered frontmatter, including keys CAMI doesn't model
+	Frontmatter *Frontmatter `yaml:"-" json:"-"`
+
+	// Diagnostics are the problems Validate found in the frontmatter when the
+	// agent was loaded from a source
+	Diagnostics []Diagnostic `yaml:"-" json:"diagnostics,omitempty"`
 }
 
 // Metadata contains the YAML frontmatter data
@@ -31,35 +53,59 @@ type Metadata struct {
 	Specialty   string `yaml:"specialty,omitempty"`
 }
 
-// LoadAgentsFromPath reads all agents from a directory (supports nested folders)
+// LoadAgentsFromPath reads all agents from a directory (supports nested
+// folders). Files that fail to load are skipped; use LoadAgents to learn why.
 func LoadAgentsFromPath(dir string) ([]*Agent, error) {
-	return LoadAgents(dir)
+	agents, _, err := LoadAgents(dir)
+	return agents, err
 }
 
 // AgentSource represents a source with its priority
 type AgentSource struct {
+	Name     string
 	Path     string
 	Priority int
 }
 
 // LoadAgentsFromSources loads agents from multiple sources with priority-based deduplication
 // Lower priority numbers override higher priority numbers when agent names conflict (1 = highest priority)
-func LoadAgentsFromSources(sources []AgentSource) ([]*Agent, error) {
+// When sources with equal priority provide the same name, the source listed first wins and the
+// tie is recorded in the report. Agents are returned sorted by category, then name.
+func LoadAgentsFromSources(sources []AgentSource) ([]*Agent, *LoadReport, error) {
+	return loadFromSources(sources, KindAgent)
+}
+
+// loadFromSources loads artifacts of one kind from multiple sources, keeping
+// the highest priority copy of each name. Sources that fail to load are
+// recorded in the report and skipped.
+func loadFromSources(sources []AgentSource, kind Kind) ([]*Agent, *LoadReport, error) {
+	report := &LoadReport{}
+
+	// Every copy of each name, and its source's priority
+	type candidate struct {
+		agent    *Agent
+		priority int
+	}
+	copies := make(map[string][]candidate)
+
 	// Map to track highest priority (lowest number) agent for each name
 	agentMap := make(map[string]*Agent)
 	priorityMap := make(map[string]int)
 
 	// Load agents from all sources
 	for _, source := range sources {
-		agents, err := LoadAgentsFromPath(source.Path)
+		agents, sourceReport, err := loadArtifacts(source.Path, kind)
 		if err != nil {
-			// Log error but continue with other sources
-			fmt.Fprintf(os.Stderr, "Warning: failed to load agents from %s: %v\n", source.Path, err)
+			report.SourceErrors = append(report.SourceErrors, SourceError{Source: source.Name, Path: source.Path, Message: err.Error()})
 			continue
 		}
+		sourceReport.setSource(source.Name)
+		report.Merge(sourceReport)
 
 		// Process each agent
 		for _, agent := range agents {
+			agent.Source = source.Name
+			copies[agent.Name] = append(copies[agent.Name], candidate{agent: agent, priority: source.Priority})
 			existingPriority, exists := priorityMap[agent.Name]
 
 			// Add or replace agent based on priority (lower number = higher priority)
@@ -72,11 +118,58 @@ func LoadAgentsFromSources(sources []AgentSource) ([]*Agent, error) {
 
 	// Convert map to slice
 	var result []*Agent
-	for _, agent := range agentMap {
+	for name, agent := range agentMap {
 		result = append(result, agent)
+
+		var tied []string
+		for _, c := range copies[name] {
+			if c.agent == agent {
+				continue
+			}
+			if c.priority == priorityMap[name] {
+				tied = append(tied, c.agent.Source)
+			}
+			report.Shadowed = append(report.Shadowed, Shadowed{
+				ID:         c.agent.ID(),
+				Version:    c.agent.Version,
+				Source:     c.agent.Source,
+				Priority:   c.priority,
+				File:       c.agent.FilePath,
+				By:         agent.Source,
+				ByPriority: priorityMap[name],
+			})
+		}
+
+		if len(tied) > 0 {
+			report.Ties = append(report.Ties, Tie{ID: agent.ID(), Priority: priorityMap[name], Winner: agent.Source, Others: tied})
+		}
 	}
+	sortShadowed(report.Shadowed)
+	sort.Slice(report.Ties, func(i, j int) bool {
+		return report.Ties[i].ID < report.Ties[j].ID
+	})
+	Sort(result)
 
-	return result, nil
+	return result, report, nil
+}
+
+// Sort orders artifacts by category, then name. Artifacts of different
+// kinds sharing a name are ordered by ID, and copies from different files
+// by path, so the order never depends on how they were loaded.
+func Sort(agents []*Agent) {
+	sort.SliceStable(agents, func(i, j int) bool {
+		a, b := agents[i], agents[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.ID() != b.ID() {
+			return a.ID() < b.ID()
+		}
+		return a.FilePath < b.FilePath
+	})
 }
 
 // loadCamiIgnore reads and parses a .camiignore file, returning a list of patterns to ignore
@@ -93,8 +186,13 @@ func loadCamiIgnore(dir string) ([]string, error) {
 	}
 	defer func() { _ = file.Close() }()
 
+	return parseCamiIgnore(file)
+}
+
+// parseCamiIgnore parses .camiignore content into a list of patterns
+func parseCamiIgnore(r io.Reader) ([]string, error) {
 	var patterns []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		// Skip empty lines and comments
@@ -140,15 +238,24 @@ func shouldIgnore(relPath string, patterns []string) bool {
 	return false
 }
 
-// LoadAgents reads all agents from the sources directory (supports nested folders)
-func LoadAgents(vcAgentsDir string) ([]*Agent, error) {
+// LoadAgents reads all agents from the sources directory (supports nested folders).
+// Slash commands under commands/ or .claude/commands/ are left to LoadCommands,
+// and skills under skills/ or .claude/skills/ to LoadSkills. Files that fail to
+// load are skipped and recorded in the report.
+func LoadAgents(vcAgentsDir string) ([]*Agent, *LoadReport, error) {
+	return loadArtifacts(vcAgentsDir, KindAgent)
+}
+
+// loadArtifacts reads all artifacts of one kind from a source directory
+func loadArtifacts(vcAgentsDir string, kind Kind) ([]*Agent, *LoadReport, error) {
 	var agents []*Agent
+	report := &LoadReport{}
 
 	// Load .camiignore patterns if they exist
 	ignorePatterns, err := loadCamiIgnore(vcAgentsDir)
 	if err != nil {
-		// Log warning but continue
-		fmt.Fprintf(os.Stderr, "Warning: failed to load .camiignore: %v\n", err)
+		// Record the problem but continue
+		report.Errors = append(report.Errors, fileError(filepath.Join(vcAgentsDir, ".camiignore"), err))
 		ignorePatterns = []string{}
 	}
 
@@ -169,31 +276,37 @@ func LoadAgents(vcAgentsDir string) ([]*Agent, error) {
 			return err
 		}
 
+		fileKind, kindPath := kindOf(relPath)
+		if fileKind != kind || !isArtifactFile(kind, kindPath) {
+			return nil
+		}
+
 		// Check if file should be ignored
 		if shouldIgnore(relPath, ignorePatterns) {
+			report.Ignored = append(report.Ignored, IgnoredFile{File: path})
 			return nil
 		}
 
 		// Load the agent
-		agent, err := LoadAgent(path)
+		agent, err := Load(kind, path)
 		if err != nil {
-			// Log error but continue loading other agents
-			fmt.Fprintf(os.Stderr, "Warning: failed to load agent %s: %v\n", info.Name(), err)
+			// Record the error but continue loading other agents
+			report.Errors = append(report.Errors, fileError(path, err))
 			return nil
 		}
 
 		// Extract category from the folder structure
 		// If agent is in vcAgentsDir/category/agent.md, category is extracted
 		// If agent is in vcAgentsDir/agent.md, category is empty (uncategorized)
-		relPath, err = filepath.Rel(vcAgentsDir, filepath.Dir(path))
-		if err != nil {
-			return err
-		}
-
-		if relPath != "." {
-			// Extract the first directory level as the category
-			parts := strings.Split(relPath, string(filepath.Separator))
-			agent.Category = parts[0]
+		// Commands and skills take theirs from the folders below commands/ and skills/
+		agent.Category = artifactCategory(kind, kindPath)
+
+		if kind == KindAgent {
+			agent.MCPConfig = readMCPSibling(path)
+			agent.Diagnostics = agent.Validate()
+			for _, d := range agent.Diagnostics {
+				report.Diagnostics = append(report.Diagnostics, FileError{Diagnostic: d})
+			}
 		}
 
 		agents = append(agents, agent)
@@ -201,59 +314,97 @@ func LoadAgents(vcAgentsDir string) ([]*Agent, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to walk agent source directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to walk agent source directory: %w", err)
 	}
 
-	return agents, nil
+	Sort(agents)
+	return agents, report, nil
+}
+
+// LoadAgentsFromFiles parses agents from in-memory source files, such as a
+// source read at a git tag. Files are keyed by slash-separated path relative
+// to the source root; .camiignore and categories apply as in LoadAgents.
+// Files that fail to parse are skipped.
+func LoadAgentsFromFiles(root string, files map[string][]byte) []*Agent {
+	return loadFromFiles(root, files, KindAgent)
+}
+
+// loadFromFiles parses the artifacts of one kind among in-memory source files
+func loadFromFiles(root string, files map[string][]byte, kind Kind) []*Agent {
+	var ignorePatterns []string
+	if data, ok := files[".camiignore"]; ok {
+		ignorePatterns, _ = parseCamiIgnore(bytes.NewReader(data))
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var agents []*Agent
+	for _, path := range paths {
+		relPath := filepath.FromSlash(path)
+		if !strings.HasSuffix(relPath, ".md") || shouldIgnore(relPath, ignorePatterns) {
+			continue
+		}
+
+		fileKind, kindPath := kindOf(relPath)
+		if fileKind != kind || !isArtifactFile(kind, kindPath) {
+			continue
+		}
+
+		agent, err := Parse(kind, files[path], filepath.Join(root, relPath))
+		if err != nil {
+			continue
+		}
+		switch kind {
+		case KindSkill:
+			agent.Files = SkillFiles(files, path)
+		case KindAgent:
+			agent.MCPConfig = files[MCPSiblingPath(path)]
+			agent.Diagnostics = agent.Validate()
+		}
+
+		agent.Category = artifactCategory(kind, kindPath)
+
+		agents = append(agents, agent)
+	}
+
+	return agents
 }
 
 // LoadAgent parses a single agent file
 func LoadAgent(filePath string) (*Agent, error) {
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	defer func() { _ = file.Close() }()
 
-	scanner := bufio.NewScanner(file)
+	return ParseAgent(data, filePath)
+}
 
-	// Read first line, should be "---"
-	if !scanner.Scan() {
-		return nil, fmt.Errorf("empty file")
-	}
-	if strings.TrimSpace(scanner.Text()) != "---" {
-		return nil, fmt.Errorf("missing frontmatter delimiter")
+// ParseAgent parses agent file content. The frontmatter and body are kept
+// exactly as given so FullContent reproduces the original bytes.
+func ParseAgent(data []byte, filePath string) (*Agent, error) {
+	open, raw, closing, body, err := splitFrontmatter(string(data))
+	if err != nil {
+		return nil, err
 	}
 
-	// Read frontmatter
-	var frontmatterLines []string
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "---" {
-			break
-		}
-		frontmatterLines = append(frontmatterLines, line)
+	// Parse YAML frontmatter
+	frontmatter, err := ParseFrontmatter(raw)
+	if err != nil {
+		return nil, frontmatterError(filePath, err)
 	}
+	frontmatter.open = open
+	frontmatter.close = closing
 
-	// Parse YAML frontmatter
 	var metadata Metadata
-	frontmatterYAML := strings.Join(frontmatterLines, "\n")
-	if err := yaml.Unmarshal([]byte(frontmatterYAML), &metadata); err != nil {
+	if err := frontmatter.doc.Decode(&metadata); err != nil && len(frontmatter.Node().Content) > 0 {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
-	// Read the rest of the file (agent content)
-	var contentLines []string
-	for scanner.Scan() {
-		contentLines = append(contentLines, scanner.Text())
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
-	}
-
-	content := strings.Join(contentLines, "\n")
-
 	return &Agent{
 		Name:        metadata.Name,
 		Version:     metadata.Version,
@@ -261,26 +412,78 @@ func LoadAgent(filePath string) (*Agent, error) {
 		Class:       metadata.Class,
 		Specialty:   metadata.Specialty,
 		FilePath:    filePath,
-		Content:     content,
+		Content:     body,
+		Frontmatter: frontmatter,
 	}, nil
 }
 
-// FullContent returns the complete agent file content including frontmatter
+// FullContent returns the complete agent file content including frontmatter.
+// Frontmatter keys other than the CAMI fields are preserved in their original order.
 func (a *Agent) FullContent() string {
-	frontmatter := fmt.Sprintf("---\nname: %s\nversion: %s\ndescription: %s\n",
-		a.Name, a.Version, a.Description)
+	if a.Frontmatter == nil {
+		a.Frontmatter = NewFrontmatter()
+	}
 
-	// Add optional fields if present
-	if a.Class != "" {
-		frontmatter += fmt.Sprintf("class: %s\n", a.Class)
+	// Reflect any changes made to the typed fields back into the frontmatter.
+	// Commands and skills are named after their file or directory unless their
+	// frontmatter says otherwise.
+	if a.ArtifactKind() == KindAgent || a.Frontmatter.Has("name") {
+		a.Frontmatter.sync("name", a.Name, false)
 	}
-	if a.Specialty != "" {
-		frontmatter += fmt.Sprintf("specialty: %s\n", a.Specialty)
+	a.Frontmatter.sync("version", a.Version, false)
+	a.Frontmatter.sync("description", a.Description, false)
+	a.Frontmatter.sync("class", a.Class, true)
+	a.Frontmatter.sync("specialty", a.Specialty, true)
+
+	return a.Frontmatter.Block() + a.Content
+}
+
+// Tools returns the tools the agent is restricted to, or nil if the agent
+// inherits all tools. Accepts both the comma-separated string form Claude Code
+// writes and a YAML list.
+func (a *Agent) Tools() []string {
+	if a.Frontmatter == nil {
+		return nil
 	}
 
-	frontmatter += "---\n"
+	node := a.Frontmatter.Get("tools")
+	if node == nil {
+		return nil
+	}
 
-	return frontmatter + a.Content
+	var tools []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if tool := strings.TrimSpace(item.Value); tool != "" {
+				tools = append(tools, tool)
+			}
+		}
+	case yaml.ScalarNode:
+		for _, part := range strings.Split(node.Value, ",") {
+			if tool := strings.TrimSpace(part); tool != "" {
+				tools = append(tools, tool)
+			}
+		}
+	}
+
+	return tools
+}
+
+// Model returns the model the agent requests (e.g. "sonnet", "inherit"), or ""
+func (a *Agent) Model() string {
+	if a.Frontmatter == nil {
+		return ""
+	}
+	return a.Frontmatter.String("model")
+}
+
+// Color returns the display color for the agent, or ""
+func (a *Agent) Color() string {
+	if a.Frontmatter == nil {
+		return ""
+	}
+	return a.Frontmatter.String("color")
 }
 
 // FileName returns just the filename without path
@@ -301,27 +504,44 @@ func (a *Agent) GetPhaseWeights() PhaseWeights {
 	return GetPhaseWeightsByClass(a.Class)
 }
 
-// GetPhaseWeightsByClass returns phase weights for a given class
-func GetPhaseWeightsByClass(class string) PhaseWeights {
-	weights := map[string]PhaseWeights{
-		"workflow-specialist": {
-			Research: 15,
-			Execute:  70,
-			Validate: 15,
-		},
-		"technology-implementer": {
-			Research: 30,
-			Execute:  55,
-			Validate: 15,
-		},
-		"strategic-planner": {
-			Research: 45,
-			Execute:  30,
-			Validate: 25,
-		},
+// classWeights are the phase weights of each known agent class
+var classWeights = map[string]PhaseWeights{
+	"workflow-specialist": {
+		Research: 15,
+		Execute:  70,
+		Validate: 15,
+	},
+	"technology-implementer": {
+		Research: 30,
+		Execute:  55,
+		Validate: 15,
+	},
+	"strategic-planner": {
+		Research: 45,
+		Execute:  30,
+		Validate: 25,
+	},
+}
+
+// Classes returns the known agent classes, sorted
+func Classes() []string {
+	classes := make([]string, 0, len(classWeights))
+	for class := range classWeights {
+		classes = append(classes, class)
 	}
+	sort.Strings(classes)
+	return classes
+}
+
+// IsKnownClass reports whether class is one of the known agent classes
+func IsKnownClass(class string) bool {
+	_, exists := classWeights[class]
+	return exists
+}
 
-	if w, exists := weights[class]; exists {
+// GetPhaseWeightsByClass returns phase weights for a given class
+func GetPhaseWeightsByClass(class string) PhaseWeights {
+	if w, exists := classWeights[class]; exists {
 		return w
 	}
 
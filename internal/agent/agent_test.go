@@ -3,6 +3,7 @@ package agent
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -93,7 +94,7 @@ func TestLoadAgents(t *testing.T) {
 		createTestAgent(t, tmpDir, "agent2", "2.0.0", "Second agent", "Content 2")
 		createTestAgent(t, tmpDir, "agent3", "3.0.0", "Third agent", "Content 3")
 
-		agents, err := LoadAgents(tmpDir)
+		agents, _, err := LoadAgents(tmpDir)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 3)
@@ -119,7 +120,7 @@ func TestLoadAgents(t *testing.T) {
 		createTestAgent(t, coreDir, "core-agent", "1.0.0", "Core", "Core content")
 		createTestAgent(t, specializedDir, "specialized-agent", "1.0.0", "Specialized", "Specialized content")
 
-		agents, err := LoadAgents(tmpDir)
+		agents, _, err := LoadAgents(tmpDir)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 2)
@@ -143,7 +144,7 @@ func TestLoadAgents(t *testing.T) {
 		_ = os.WriteFile(filepath.Join(tmpDir, "README.txt"), []byte("readme"), 0644)
 		_ = os.WriteFile(filepath.Join(tmpDir, "data.json"), []byte("{}"), 0644)
 
-		agents, err := LoadAgents(tmpDir)
+		agents, _, err := LoadAgents(tmpDir)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 1)
@@ -153,14 +154,14 @@ func TestLoadAgents(t *testing.T) {
 	t.Run("empty directory", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		agents, err := LoadAgents(tmpDir)
+		agents, _, err := LoadAgents(tmpDir)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 0)
 	})
 
 	t.Run("directory does not exist", func(t *testing.T) {
-		_, err := LoadAgents("/nonexistent/directory")
+		_, _, err := LoadAgents("/nonexistent/directory")
 		assert.Error(t, err)
 	})
 }
@@ -178,7 +179,7 @@ func TestLoadAgentsFromSources(t *testing.T) {
 			{Path: source2, Priority: 150},
 		}
 
-		agents, err := LoadAgentsFromSources(sources)
+		agents, _, err := LoadAgentsFromSources(sources)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 2)
@@ -197,7 +198,7 @@ func TestLoadAgentsFromSources(t *testing.T) {
 			{Path: source2, Priority: 100}, // Higher number = lower priority
 		}
 
-		agents, err := LoadAgentsFromSources(sources)
+		agents, _, err := LoadAgentsFromSources(sources)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 1)
@@ -217,7 +218,7 @@ func TestLoadAgentsFromSources(t *testing.T) {
 			{Path: validSource, Priority: 50},
 		}
 
-		agents, err := LoadAgentsFromSources(sources)
+		agents, _, err := LoadAgentsFromSources(sources)
 
 		// Should still succeed with valid source
 		require.NoError(t, err)
@@ -225,16 +226,88 @@ func TestLoadAgentsFromSources(t *testing.T) {
 		assert.Equal(t, "valid", agents[0].Name)
 	})
 
+	t.Run("sorted by category then name", func(t *testing.T) {
+		source1 := t.TempDir()
+		source2 := t.TempDir()
+		for _, dir := range []string{"specialized", "core"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(source1, dir), 0755))
+		}
+
+		createTestAgent(t, filepath.Join(source1, "specialized"), "zeta", "1.0.0", "Z", "Content")
+		createTestAgent(t, filepath.Join(source1, "core"), "beta", "1.0.0", "B", "Content")
+		createTestAgent(t, source2, "omega", "1.0.0", "O", "Content")
+		createTestAgent(t, filepath.Join(source1, "core"), "alpha", "1.0.0", "A", "Content")
+
+		sources := []AgentSource{
+			{Name: "one", Path: source1, Priority: 100},
+			{Name: "two", Path: source2, Priority: 50},
+		}
+
+		for i := 0; i < 5; i++ {
+			agents, _, err := LoadAgentsFromSources(sources)
+			require.NoError(t, err)
+
+			var names []string
+			for _, ag := range agents {
+				names = append(names, ag.Name)
+			}
+			assert.Equal(t, []string{"omega", "alpha", "beta", "zeta"}, names)
+		}
+	})
+
+	t.Run("equal priorities resolve in config order with a tie", func(t *testing.T) {
+		first := t.TempDir()
+		second := t.TempDir()
+
+		createTestAgent(t, first, "frontend", "1.0.0", "First", "Content")
+		createTestAgent(t, second, "frontend", "2.0.0", "Second", "Content")
+
+		for _, order := range [][]AgentSource{
+			{{Name: "first", Path: first, Priority: 50}, {Name: "second", Path: second, Priority: 50}},
+			{{Name: "second", Path: second, Priority: 50}, {Name: "first", Path: first, Priority: 50}},
+		} {
+			agents, report, err := LoadAgentsFromSources(order)
+			require.NoError(t, err)
+			require.Len(t, agents, 1)
+			assert.Equal(t, order[0].Name, agents[0].Source)
+
+			assert.Equal(t, []Tie{{ID: "frontend", Priority: 50, Winner: order[0].Name, Others: []string{order[1].Name}}}, report.Ties)
+			assert.True(t, report.HasProblems())
+			assert.Contains(t, report.Problems()[0], "listed first in the config")
+		}
+	})
+
 	t.Run("empty sources list", func(t *testing.T) {
 		sources := []AgentSource{}
 
-		agents, err := LoadAgentsFromSources(sources)
+		agents, _, err := LoadAgentsFromSources(sources)
 
 		require.NoError(t, err)
 		assert.Len(t, agents, 0)
 	})
 }
 
+func TestLoadAgentsFromFiles(t *testing.T) {
+	files := map[string][]byte{
+		"core/frontend.md": []byte("---\nname: frontend\nversion: 2.0.0\n---\nBody\n"),
+		"backend.md":       []byte("---\nname: backend\nversion: 1.0.0\n---\nBody\n"),
+		"drafts/wip.md":    []byte("---\nname: wip\n---\n"),
+		"broken.md":        []byte("no frontmatter"),
+		"notes.txt":        []byte("not an agent"),
+		".camiignore":      []byte("# drafts\ndrafts/\n"),
+	}
+
+	agents := LoadAgentsFromFiles("/sources/team", files)
+
+	require.Len(t, agents, 2)
+	assert.Equal(t, "backend", agents[0].Name)
+	assert.Equal(t, "", agents[0].Category)
+	assert.Equal(t, "frontend", agents[1].Name)
+	assert.Equal(t, "core", agents[1].Category)
+	assert.Equal(t, filepath.Join("/sources/team", "core", "frontend.md"), agents[1].FilePath)
+	assert.Equal(t, "---\nname: frontend\nversion: 2.0.0\n---\nBody\n", agents[1].FullContent())
+}
+
 func TestAgentFullContent(t *testing.T) {
 	agent := &Agent{
 		Name:        "test",
@@ -260,3 +333,94 @@ func TestAgentFileName(t *testing.T) {
 	fileName := agent.FileName()
 	assert.Equal(t, "my-agent.md", fileName)
 }
+
+func TestFrontmatterRoundTrip(t *testing.T) {
+	original := "---\n" +
+		"name: reviewer\n" +
+		"description: \"Reviews code: carefully\"\n" +
+		"tools: Read, Grep, Glob\n" +
+		"model: sonnet\n" +
+		"color: purple\n" +
+		"version: \"1.0\"   # pinned\n" +
+		"x-team: platform\n" +
+		"---\n" +
+		"\n# Reviewer\n\nBody text.\n"
+
+	t.Run("unmodified agent reproduces original bytes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "reviewer.md")
+		require.NoError(t, os.WriteFile(filePath, []byte(original), 0644))
+
+		agent, err := LoadAgent(filePath)
+		require.NoError(t, err)
+
+		assert.Equal(t, original, agent.FullContent())
+	})
+
+	t.Run("typed accessors read Claude Code keys", func(t *testing.T) {
+		agent, err := ParseAgent([]byte(original), "reviewer.md")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Read", "Grep", "Glob"}, agent.Tools())
+		assert.Equal(t, "sonnet", agent.Model())
+		assert.Equal(t, "purple", agent.Color())
+		assert.Equal(t, "platform", agent.Frontmatter.String("x-team"))
+		assert.Equal(t, []string{"name", "description", "tools", "model", "color", "version", "x-team"}, agent.Frontmatter.Keys())
+	})
+
+	t.Run("tools as YAML list", func(t *testing.T) {
+		agent, err := ParseAgent([]byte("---\nname: a\ntools:\n  - Read\n  - Bash\n---\nbody"), "a.md")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Read", "Bash"}, agent.Tools())
+	})
+
+	t.Run("missing tools means unrestricted", func(t *testing.T) {
+		agent, err := ParseAgent([]byte("---\nname: a\n---\nbody"), "a.md")
+		require.NoError(t, err)
+
+		assert.Nil(t, agent.Tools())
+	})
+
+	t.Run("changing a field keeps other keys in order", func(t *testing.T) {
+		agent, err := ParseAgent([]byte(original), "reviewer.md")
+		require.NoError(t, err)
+
+		agent.Version = "1.1"
+		content := agent.FullContent()
+
+		reloaded, err := ParseAgent([]byte(content), "reviewer.md")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1", reloaded.Version)
+		assert.Equal(t, "Reviews code: carefully", reloaded.Description)
+		assert.Equal(t, []string{"Read", "Grep", "Glob"}, reloaded.Tools())
+		assert.Equal(t, agent.Frontmatter.Keys(), reloaded.Frontmatter.Keys())
+		assert.Contains(t, content, "# pinned")
+		assert.True(t, strings.HasSuffix(content, "\n# Reviewer\n\nBody text.\n"))
+	})
+
+	t.Run("CRLF line endings are preserved", func(t *testing.T) {
+		crlf := "---\r\nname: a\r\ntools: Read\r\n---\r\nbody\r\n"
+		agent, err := ParseAgent([]byte(crlf), "a.md")
+		require.NoError(t, err)
+
+		assert.Equal(t, "a", agent.Name)
+		assert.Equal(t, crlf, agent.FullContent())
+	})
+}
+
+func TestSplitFrontmatter(t *testing.T) {
+	t.Run("splits block and body", func(t *testing.T) {
+		content := "---\nname: a\n---\n# Body\n"
+
+		frontmatter, body, err := SplitFrontmatter(content)
+		require.NoError(t, err)
+		assert.Equal(t, "---\nname: a\n---\n", frontmatter)
+		assert.Equal(t, "# Body\n", body)
+	})
+
+	t.Run("missing frontmatter", func(t *testing.T) {
+		_, _, err := SplitFrontmatter("# Just a body\n")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,203 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kind is the kind of artifact a source provides
+type Kind string
+
+const (
+	KindAgent   Kind = "agent"   // Subagent, deployed to .claude/agents
+	KindCommand Kind = "command" // Slash command, deployed to .claude/commands
+	KindSkill   Kind = "skill"   // Skill directory, deployed to .claude/skills
+)
+
+// commandPrefix marks a name as a slash command wherever agents and commands
+// are requested together, e.g. "frontend,/review"
+const commandPrefix = "/"
+
+// commandDirs are where a source keeps its slash commands, relative to its
+// root. Files below them are commands, everything else is an agent.
+var commandDirs = []string{"commands", filepath.Join(".claude", "commands")}
+
+// Dir returns the directory under a project's .claude that artifacts of this
+// kind are deployed to
+func (k Kind) Dir() string {
+	switch k {
+	case KindCommand:
+		return "commands"
+	case KindSkill:
+		return "skills"
+	}
+	return "agents"
+}
+
+// ID returns the name an artifact is requested and tracked by: agents by
+// name, commands as /name and skills as skill:name
+func ID(kind Kind, name string) string {
+	switch kind {
+	case KindCommand:
+		return commandPrefix + name
+	case KindSkill:
+		return skillPrefix + name
+	}
+	return name
+}
+
+// ParseID splits a requested name into the kind of artifact and its name
+func ParseID(id string) (Kind, string) {
+	if name, ok := strings.CutPrefix(id, commandPrefix); ok {
+		return KindCommand, name
+	}
+	if name, ok := strings.CutPrefix(id, skillPrefix); ok {
+		return KindSkill, name
+	}
+	return KindAgent, id
+}
+
+// ArtifactKind returns the kind of artifact, treating an unset kind as an agent
+func (a *Agent) ArtifactKind() Kind {
+	if a.Kind == "" {
+		return KindAgent
+	}
+	return a.Kind
+}
+
+// ID returns the name the artifact is requested and tracked by
+func (a *Agent) ID() string {
+	return ID(a.Kind, a.Name)
+}
+
+// LoadCommands reads all slash commands from a source directory's commands/
+// or .claude/commands/ folder. Subfolders become the command's category.
+func LoadCommands(dir string) ([]*Agent, *LoadReport, error) {
+	return loadArtifacts(dir, KindCommand)
+}
+
+// LoadCommandsFromSources loads commands from multiple sources with the same
+// priority-based deduplication as LoadAgentsFromSources
+func LoadCommandsFromSources(sources []AgentSource) ([]*Agent, *LoadReport, error) {
+	return loadFromSources(sources, KindCommand)
+}
+
+// LoadCommandsFromFiles parses the commands among in-memory source files, as
+// LoadAgentsFromFiles does for agents
+func LoadCommandsFromFiles(root string, files map[string][]byte) []*Agent {
+	return loadFromFiles(root, files, KindCommand)
+}
+
+// LoadCommand parses a single command file
+func LoadCommand(filePath string) (*Agent, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return ParseCommand(data, filePath)
+}
+
+// ParseCommand parses slash command content. Commands are named after their
+// file unless the frontmatter names them, and frontmatter is optional.
+func ParseCommand(data []byte, filePath string) (*Agent, error) {
+	cmd, err := parseOptionalFrontmatter(data, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.Kind = KindCommand
+	if cmd.Name == "" {
+		cmd.Name = strings.TrimSuffix(filepath.Base(filePath), ".md")
+	}
+	return cmd, nil
+}
+
+// parseOptionalFrontmatter parses content that may or may not start with
+// frontmatter, keeping it as given either way
+func parseOptionalFrontmatter(data []byte, filePath string) (*Agent, error) {
+	if open, _ := cutLine(string(data)); strings.TrimSpace(open) == "---" {
+		return ParseAgent(data, filePath)
+	}
+
+	frontmatter := NewFrontmatter()
+	frontmatter.open = ""
+	frontmatter.close = ""
+	return &Agent{
+		FilePath:    filePath,
+		Content:     string(data),
+		Frontmatter: frontmatter,
+	}, nil
+}
+
+// Load parses a single file as an artifact of the given kind. For skills,
+// the file is the SKILL.md and its supporting files are read too.
+func Load(kind Kind, filePath string) (*Agent, error) {
+	switch kind {
+	case KindCommand:
+		return LoadCommand(filePath)
+	case KindSkill:
+		return LoadSkill(filePath)
+	}
+	return LoadAgent(filePath)
+}
+
+// Parse parses file content as an artifact of the given kind
+func Parse(kind Kind, data []byte, filePath string) (*Agent, error) {
+	switch kind {
+	case KindCommand:
+		return ParseCommand(data, filePath)
+	case KindSkill:
+		return ParseSkill(data, filePath)
+	}
+	return ParseAgent(data, filePath)
+}
+
+// relPathIn returns the path of a source file relative to the first of dirs
+// it's in, and whether it is in one
+func relPathIn(dirs []string, relPath string) (string, bool) {
+	for _, dir := range dirs {
+		if rest, ok := strings.CutPrefix(relPath, dir+string(filepath.Separator)); ok {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+// kindOf returns the kind of artifact a source file holds, and its path
+// relative to where artifacts of that kind live in the source. Every file
+// below a skills folder belongs to a skill, supporting files included.
+func kindOf(relPath string) (Kind, string) {
+	if rest, ok := relPathIn(skillDirs, relPath); ok {
+		return KindSkill, rest
+	}
+	if rest, ok := relPathIn(commandDirs, relPath); ok {
+		return KindCommand, rest
+	}
+	return KindAgent, relPath
+}
+
+// isArtifactFile reports whether a source file of a kind defines an
+// artifact: every markdown file does for agents and commands, only the
+// SKILL.md at the top of a skill's directory does for skills
+func isArtifactFile(kind Kind, kindPath string) bool {
+	if kind != KindSkill {
+		return true
+	}
+	return filepath.Base(kindPath) == SkillFile && filepath.Dir(kindPath) != "."
+}
+
+// artifactCategory returns the category of an artifact from its path
+// relative to where artifacts of its kind live: the first folder above it,
+// or above its directory for a skill
+func artifactCategory(kind Kind, kindPath string) string {
+	dir := filepath.Dir(kindPath)
+	if kind == KindSkill {
+		dir = filepath.Dir(dir)
+	}
+	if dir == "." {
+		return ""
+	}
+	return strings.Split(dir, string(filepath.Separator))[0]
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCommands(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		createTestAgent(t, dir, "frontend", "1.0.0", "Frontend", "Agent content")
+
+		gitDir := filepath.Join(dir, "commands", "git")
+		require.NoError(t, os.MkdirAll(gitDir, 0755))
+		createTestAgent(t, gitDir, "commit", "1.0.0", "Write a commit message", "Commit content")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "commands", "review.md"), []byte("Review $ARGUMENTS\n"), 0644))
+		return dir
+	}
+
+	t.Run("commands are loaded from commands/", func(t *testing.T) {
+		commands, _, err := LoadCommands(setup(t))
+		require.NoError(t, err)
+		require.Len(t, commands, 2)
+
+		byName := make(map[string]*Agent)
+		for _, cmd := range commands {
+			assert.Equal(t, KindCommand, cmd.Kind)
+			byName[cmd.ID()] = cmd
+		}
+		require.Contains(t, byName, "/commit")
+		assert.Equal(t, "git", byName["/commit"].Category)
+		assert.Equal(t, "1.0.0", byName["/commit"].Version)
+		require.Contains(t, byName, "/review")
+		assert.Equal(t, "", byName["/review"].Category)
+	})
+
+	t.Run("agents exclude commands", func(t *testing.T) {
+		agents, _, err := LoadAgents(setup(t))
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		assert.Equal(t, "frontend", agents[0].ID())
+	})
+
+	t.Run("commands from files", func(t *testing.T) {
+		files := map[string][]byte{
+			"frontend.md":                []byte("---\nname: frontend\n---\n"),
+			".claude/commands/review.md": []byte("Review $ARGUMENTS\n"),
+		}
+
+		commands := LoadCommandsFromFiles("/sources/team", files)
+		require.Len(t, commands, 1)
+		assert.Equal(t, "/review", commands[0].ID())
+		assert.Len(t, LoadAgentsFromFiles("/sources/team", files), 1)
+	})
+}
+
+func TestParseCommand(t *testing.T) {
+	t.Run("without frontmatter", func(t *testing.T) {
+		content := "Review the staged changes.\n"
+		cmd, err := ParseCommand([]byte(content), "/commands/review.md")
+		require.NoError(t, err)
+
+		assert.Equal(t, "review", cmd.Name)
+		assert.Equal(t, KindCommand, cmd.Kind)
+		assert.Equal(t, content, cmd.FullContent())
+	})
+
+	t.Run("with frontmatter", func(t *testing.T) {
+		content := "---\ndescription: Review changes\nallowed-tools: Bash(git diff:*)\n---\nReview $ARGUMENTS\n"
+		cmd, err := ParseCommand([]byte(content), "/commands/review.md")
+		require.NoError(t, err)
+
+		assert.Equal(t, "review", cmd.Name)
+		assert.Equal(t, "Review changes", cmd.Description)
+		assert.Equal(t, content, cmd.FullContent())
+	})
+
+	t.Run("version added to a command without frontmatter", func(t *testing.T) {
+		cmd, err := ParseCommand([]byte("Review the staged changes.\n"), "/commands/review.md")
+		require.NoError(t, err)
+
+		cmd.Version = "1.0.0"
+		assert.Equal(t, "---\nversion: 1.0.0\n---\nReview the staged changes.\n", cmd.FullContent())
+	})
+}
+
+func TestParseID(t *testing.T) {
+	kind, name := ParseID("/review")
+	assert.Equal(t, KindCommand, kind)
+	assert.Equal(t, "review", name)
+	assert.Equal(t, "/review", ID(kind, name))
+
+	kind, name = ParseID("frontend")
+	assert.Equal(t, KindAgent, kind)
+	assert.Equal(t, "frontend", name)
+	assert.Equal(t, KindAgent.Dir(), "agents")
+	assert.Equal(t, KindCommand.Dir(), "commands")
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Provider is one source's copy of an artifact
+type Provider struct {
+	Source   string `json:"source"`
+	Priority int    `json:"priority"`
+	Version  string `json:"version,omitempty"`
+	File     string `json:"file"`
+	Wins     bool   `json:"wins"`
+}
+
+// Resolution explains which source's copy of an artifact is used: every
+// source that provides it, the winner first, and why the winner wins
+type Resolution struct {
+	ID        string     `json:"id"`
+	Providers []Provider `json:"providers"`
+	Reason    string     `json:"reason"`
+}
+
+// Explain works out how an artifact, given by ID, was resolved from the
+// loaded artifacts and their load report. Sources supply the priorities of
+// winners, which the report doesn't record. Returns nil if no source
+// provides the artifact.
+func Explain(id string, artifacts []*Agent, report *LoadReport, sources []AgentSource) *Resolution {
+	var winner *Agent
+	for _, ag := range artifacts {
+		if ag.ID() == id {
+			winner = ag
+			break
+		}
+	}
+	if winner == nil {
+		return nil
+	}
+
+	priority := 0
+	for _, source := range sources {
+		if source.Name == winner.Source {
+			priority = source.Priority
+		}
+	}
+
+	r := &Resolution{
+		ID: id,
+		Providers: []Provider{{
+			Source:   winner.Source,
+			Priority: priority,
+			Version:  winner.Version,
+			File:     winner.FilePath,
+			Wins:     true,
+		}},
+	}
+	if report != nil {
+		for _, s := range report.Shadowed {
+			if s.ID == id {
+				r.Providers = append(r.Providers, Provider{Source: s.Source, Priority: s.Priority, Version: s.Version, File: s.File})
+			}
+		}
+	}
+	sort.SliceStable(r.Providers[1:], func(i, j int) bool {
+		return r.Providers[1+i].Priority < r.Providers[1+j].Priority
+	})
+
+	switch {
+	case len(r.Providers) == 1:
+		r.Reason = "it is the only source that provides " + id
+	default:
+		var tied []string
+		for _, p := range r.Providers[1:] {
+			if p.Priority == priority {
+				tied = append(tied, p.Source)
+			}
+		}
+		if len(tied) == 0 {
+			r.Reason = fmt.Sprintf("priority %d is the highest (lowest number) of the %d sources that provide %s", priority, len(r.Providers), id)
+		} else {
+			r.Reason = fmt.Sprintf("priority %d ties with %s; the source listed first in the config wins", priority, joinNames(tied))
+		}
+	}
+
+	return r
+}
+
+// joinNames joins names as "a", "a and b" or "a, b and c"
+func joinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	}
+	text := names[0]
+	for _, name := range names[1 : len(names)-1] {
+		text += ", " + name
+	}
+	return text + " and " + names[len(names)-1]
+}
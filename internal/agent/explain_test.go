@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	load := func(t *testing.T, sources []AgentSource) *Resolution {
+		t.Helper()
+		agents, report, err := LoadAgentsFromSources(sources)
+		require.NoError(t, err)
+		return Explain("frontend", agents, report, sources)
+	}
+
+	t.Run("only source", func(t *testing.T) {
+		dir := t.TempDir()
+		file := createTestAgent(t, dir, "frontend", "1.0.0", "Builds UIs", "Body")
+
+		r := load(t, []AgentSource{{Name: "team", Path: dir, Priority: 50}})
+		require.NotNil(t, r)
+		assert.Equal(t, []Provider{{Source: "team", Priority: 50, Version: "1.0.0", File: file, Wins: true}}, r.Providers)
+		assert.Contains(t, r.Reason, "only source")
+	})
+
+	t.Run("lowest priority number wins", func(t *testing.T) {
+		low, mid, high := t.TempDir(), t.TempDir(), t.TempDir()
+		createTestAgent(t, low, "frontend", "1.0.0", "Low", "Body")
+		createTestAgent(t, mid, "frontend", "2.0.0", "Mid", "Body")
+		createTestAgent(t, high, "frontend", "3.0.0", "High", "Body")
+
+		r := load(t, []AgentSource{
+			{Name: "low", Path: low, Priority: 100},
+			{Name: "high", Path: high, Priority: 10},
+			{Name: "mid", Path: mid, Priority: 50},
+		})
+		require.NotNil(t, r)
+		require.Len(t, r.Providers, 3)
+		assert.True(t, r.Providers[0].Wins)
+		assert.Equal(t, "high", r.Providers[0].Source)
+		assert.Equal(t, "3.0.0", r.Providers[0].Version)
+		assert.Equal(t, []string{"mid", "low"}, []string{r.Providers[1].Source, r.Providers[2].Source})
+		assert.False(t, r.Providers[1].Wins)
+		assert.Contains(t, r.Reason, "priority 10 is the highest")
+	})
+
+	t.Run("ties go to the first configured source", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		createTestAgent(t, first, "frontend", "1.0.0", "First", "Body")
+		createTestAgent(t, second, "frontend", "2.0.0", "Second", "Body")
+
+		r := load(t, []AgentSource{
+			{Name: "first", Path: first, Priority: 50},
+			{Name: "second", Path: second, Priority: 50},
+		})
+		require.NotNil(t, r)
+		assert.Equal(t, "first", r.Providers[0].Source)
+		assert.Contains(t, r.Reason, "ties with second")
+	})
+
+	t.Run("unknown agent", func(t *testing.T) {
+		dir := t.TempDir()
+		createTestAgent(t, dir, "backend", "1.0.0", "Builds APIs", "Body")
+		assert.Nil(t, load(t, []AgentSource{{Name: "team", Path: dir, Priority: 50}}))
+	})
+}
@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Frontmatter holds an agent's YAML frontmatter as an ordered node tree.
+// Keys CAMI doesn't model itself (tools, model, color, custom keys) are kept
+// as-is, and the original text is reproduced byte-for-byte unless a key is
+// changed through Set or Delete.
+type Frontmatter struct {
+	open  string // Opening delimiter line as read ("---\n")
+	close string // Closing delimiter line as read ("---\n"), empty if missing
+	raw   string // Frontmatter text between the delimiters, exactly as read
+	doc   *yaml.Node
+	dirty bool
+}
+
+// NewFrontmatter returns an empty frontmatter block
+func NewFrontmatter() *Frontmatter {
+	return &Frontmatter{
+		open:  "---\n",
+		close: "---\n",
+		doc: &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		},
+	}
+}
+
+// ParseFrontmatter parses the YAML text found between the frontmatter delimiters
+func ParseFrontmatter(raw string) (*Frontmatter, error) {
+	fm := NewFrontmatter()
+	fm.raw = raw
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, err
+	}
+
+	// Empty frontmatter decodes to a zero node; keep the empty mapping
+	if doc.Kind == 0 {
+		return fm, nil
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("frontmatter must be a YAML mapping")
+	}
+
+	fm.doc = &doc
+	return fm, nil
+}
+
+// Node returns the mapping node backing the frontmatter
+func (f *Frontmatter) Node() *yaml.Node {
+	return f.doc.Content[0]
+}
+
+// Keys returns the frontmatter keys in file order
+func (f *Frontmatter) Keys() []string {
+	mapping := f.Node()
+	keys := make([]string, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, mapping.Content[i].Value)
+	}
+	return keys
+}
+
+// Get returns the value node for a key, or nil if the key is not present
+func (f *Frontmatter) Get(key string) *yaml.Node {
+	mapping := f.Node()
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Has reports whether a key is present
+func (f *Frontmatter) Has(key string) bool {
+	return f.Get(key) != nil
+}
+
+// String returns the value of a scalar key, or "" if absent or not a scalar
+func (f *Frontmatter) String(key string) string {
+	node := f.Get(key)
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// Decode decodes the value of a key into v; absent keys leave v untouched
+func (f *Frontmatter) Decode(key string, v any) error {
+	node := f.Get(key)
+	if node == nil {
+		return nil
+	}
+	return node.Decode(v)
+}
+
+// Set sets a key to a string value, appending the key if it's new.
+// Setting a key to its current value is a no-op so the original text is kept.
+func (f *Frontmatter) Set(key, value string) {
+	if node := f.Get(key); node != nil && node.Kind == yaml.ScalarNode && node.Tag != "!!null" && node.Value == value {
+		return
+	}
+	f.SetNode(key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+// SetNode sets a key to an arbitrary value node, appending the key if it's new
+func (f *Frontmatter) SetNode(key string, value *yaml.Node) {
+	mapping := f.Node()
+	f.dirty = true
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			// Keep comments attached to the old value
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// Delete removes a key if present
+func (f *Frontmatter) Delete(key string) {
+	mapping := f.Node()
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			f.dirty = true
+			return
+		}
+	}
+}
+
+// Text returns the frontmatter YAML without delimiters. Unmodified frontmatter
+// is returned exactly as it was read.
+func (f *Frontmatter) Text() string {
+	if !f.dirty {
+		return f.raw
+	}
+
+	if len(f.Node().Content) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f.doc); err != nil {
+		// Encoding a tree we parsed ourselves shouldn't fail; fall back to the original
+		return f.raw
+	}
+	_ = encoder.Close()
+
+	return buf.String()
+}
+
+// Block returns the complete frontmatter block including delimiters
+func (f *Frontmatter) Block() string {
+	open, closing := f.open, f.close
+	if f.dirty {
+		// Commands may have no frontmatter until a key is set
+		if open == "" && len(f.Node().Content) > 0 {
+			open = "---\n"
+		}
+		if closing == "" && open != "" {
+			closing = "---\n"
+		}
+	}
+
+	text := f.Text()
+	if f.dirty && text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	return open + text + closing
+}
+
+// SplitFrontmatter splits agent file content into its frontmatter block,
+// delimiters included, and the body that follows, so the two can be compared
+// separately. Joined, they give back content exactly.
+func SplitFrontmatter(content string) (frontmatter, body string, err error) {
+	open, raw, closing, body, err := splitFrontmatter(content)
+	if err != nil {
+		return "", "", err
+	}
+	return open + raw + closing, body, nil
+}
+
+// splitFrontmatter splits file content into opening delimiter, frontmatter text,
+// closing delimiter and body, preserving line endings exactly
+func splitFrontmatter(text string) (open, raw, closing, body string, err error) {
+	if text == "" {
+		return "", "", "", "", fmt.Errorf("empty file")
+	}
+
+	open, rest := cutLine(text)
+	if strings.TrimSpace(open) != "---" {
+		return "", "", "", "", fmt.Errorf("missing frontmatter delimiter")
+	}
+
+	offset := 0
+	for offset < len(rest) {
+		line, remainder := cutLine(rest[offset:])
+		if strings.TrimSpace(line) == "---" {
+			return open, rest[:offset], line, remainder, nil
+		}
+		offset = len(rest) - len(remainder)
+	}
+
+	// No closing delimiter: everything after the opening line is frontmatter
+	return open, rest, "", "", nil
+}
+
+// cutLine returns the first line of s including its terminator, and the rest
+func cutLine(s string) (string, string) {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		return s[:idx+1], s[idx+1:]
+	}
+	return s, ""
+}
+
+// sync updates key to match a typed field value. Empty optional values remove
+// the key; empty required values are left alone so absent keys stay absent.
+func (f *Frontmatter) sync(key, value string, optional bool) {
+	if f.String(key) == value {
+		return
+	}
+	if value == "" {
+		if optional {
+			f.Delete(key)
+		}
+		return
+	}
+	f.Set(key, value)
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// mcpServersKey is the frontmatter key, and the .mcp.json key, that holds
+// MCP server configurations by server name
+const mcpServersKey = "mcpServers"
+
+// mcpSiblingSuffix names the file next to an agent in its source that
+// declares the MCP servers the agent requires, in .mcp.json format, e.g.
+// frontend.mcp.json for frontend.md
+const mcpSiblingSuffix = ".mcp.json"
+
+// MCPSiblingPath returns the path of the MCP server file that may accompany
+// an agent file in its source
+func MCPSiblingPath(filePath string) string {
+	return strings.TrimSuffix(filePath, ".md") + mcpSiblingSuffix
+}
+
+// MCPServers returns the MCP servers the agent requires, by name, as the
+// JSON configuration Claude Code reads from .mcp.json. Servers come from the
+// sibling .mcp.json file in the source and from the mcpServers frontmatter
+// key, which wins when both declare a server.
+func (a *Agent) MCPServers() (map[string]json.RawMessage, error) {
+	servers := make(map[string]json.RawMessage)
+
+	if len(a.MCPConfig) > 0 {
+		var config struct {
+			MCPServers map[string]json.RawMessage `json:"mcpServers"`
+		}
+		if err := json.Unmarshal(a.MCPConfig, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", MCPSiblingPath(a.FileName()), err)
+		}
+		for name, server := range config.MCPServers {
+			servers[name] = server
+		}
+	}
+
+	if a.Frontmatter != nil {
+		var declared map[string]any
+		if err := a.Frontmatter.Decode(mcpServersKey, &declared); err != nil {
+			return nil, fmt.Errorf("failed to parse %s frontmatter: %w", mcpServersKey, err)
+		}
+		for name, server := range declared {
+			data, err := json.Marshal(server)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert MCP server %s: %w", name, err)
+			}
+			servers[name] = data
+		}
+	}
+
+	for name, server := range servers {
+		var config map[string]any
+		if err := json.Unmarshal(server, &config); err != nil || config == nil {
+			return nil, fmt.Errorf("MCP server %s must be an object", name)
+		}
+	}
+
+	return servers, nil
+}
+
+// readMCPSibling reads the MCP server file next to an agent in its source,
+// returning nil if there is none
+func readMCPSibling(filePath string) []byte {
+	data, err := os.ReadFile(MCPSiblingPath(filePath))
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServers(t *testing.T) {
+	t.Run("agent without servers", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: frontend\n---\nBody\n"), "/fake/frontend.md")
+		require.NoError(t, err)
+
+		servers, err := ag.MCPServers()
+		require.NoError(t, err)
+		assert.Empty(t, servers)
+	})
+
+	t.Run("servers from frontmatter", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: frontend\nmcpServers:\n  github:\n    command: npx\n    args: [\"-y\", \"server-github\"]\n---\nBody\n"), "/fake/frontend.md")
+		require.NoError(t, err)
+
+		servers, err := ag.MCPServers()
+		require.NoError(t, err)
+		require.Contains(t, servers, "github")
+		assert.JSONEq(t, `{"command": "npx", "args": ["-y", "server-github"]}`, string(servers["github"]))
+	})
+
+	t.Run("servers from a sibling file, frontmatter wins", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "frontend.md")
+		require.NoError(t, os.WriteFile(filePath, []byte("---\nname: frontend\nmcpServers:\n  github:\n    command: gh-mcp\n---\nBody\n"), 0644))
+		require.NoError(t, os.WriteFile(MCPSiblingPath(filePath), []byte(`{"mcpServers": {"github": {"command": "npx"}, "figma": {"url": "http://localhost:3845/mcp"}}}`), 0644))
+
+		agents, _, err := LoadAgents(dir)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		servers, err := agents[0].MCPServers()
+		require.NoError(t, err)
+		require.Len(t, servers, 2)
+		assert.JSONEq(t, `{"command": "gh-mcp"}`, string(servers["github"]))
+		assert.JSONEq(t, `{"url": "http://localhost:3845/mcp"}`, string(servers["figma"]))
+	})
+
+	t.Run("server that is not an object", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: frontend\nmcpServers:\n  github: npx\n---\nBody\n"), "/fake/frontend.md")
+		require.NoError(t, err)
+
+		_, err = ag.MCPServers()
+		assert.ErrorContains(t, err, "github")
+	})
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// LoadReport records what happened while loading artifacts beyond the
+// artifacts themselves: files that failed to load, schema problems in files
+// that loaded, files .camiignore skipped, copies shadowed by a higher
+// priority source, names provided by sources of equal priority and sources
+// that couldn't be read. Loaders return it
+// instead of printing, so callers such as the MCP server decide where it goes.
+type LoadReport struct {
+	Errors       []FileError   `json:"errors,omitempty"`        // Files that failed to load
+	Diagnostics  []FileError   `json:"diagnostics,omitempty"`   // Schema problems in files that loaded
+	Ignored      []IgnoredFile `json:"ignored,omitempty"`       // Files skipped by .camiignore
+	Shadowed     []Shadowed    `json:"shadowed,omitempty"`      // Copies a higher priority source overrides
+	Ties         []Tie         `json:"ties,omitempty"`          // Names sources of equal priority both provide
+	SourceErrors []SourceError `json:"source_errors,omitempty"` // Sources that couldn't be read
+}
+
+// FileError is a problem with one file in a source
+type FileError struct {
+	Source string `json:"source,omitempty"`
+	Diagnostic
+}
+
+// IgnoredFile is a file .camiignore kept from loading
+type IgnoredFile struct {
+	Source string `json:"source,omitempty"`
+	File   string `json:"file"`
+}
+
+// Shadowed is a copy of an artifact that isn't used because a source with a
+// higher priority (lower number) provides the same name
+type Shadowed struct {
+	ID         string `json:"id"`
+	Version    string `json:"version,omitempty"`
+	Source     string `json:"source"`
+	Priority   int    `json:"priority"`
+	File       string `json:"file"`
+	By         string `json:"shadowed_by"` // Source of the copy that is used
+	ByPriority int    `json:"shadowed_by_priority"`
+}
+
+// Tie is a name that sources of equal priority both provide. The source
+// listed first in the config wins; the tie is worth fixing by giving the
+// sources different priorities.
+type Tie struct {
+	ID       string   `json:"id"`
+	Priority int      `json:"priority"`
+	Winner   string   `json:"winner"`
+	Others   []string `json:"others"` // Sources whose copies lost the tie
+}
+
+// SourceError is a source that couldn't be read at all
+type SourceError struct {
+	Source  string `json:"source"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Merge appends another report's entries to r
+func (r *LoadReport) Merge(other *LoadReport) {
+	if other == nil {
+		return
+	}
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Diagnostics = append(r.Diagnostics, other.Diagnostics...)
+	r.Ignored = append(r.Ignored, other.Ignored...)
+	r.Shadowed = append(r.Shadowed, other.Shadowed...)
+	r.Ties = append(r.Ties, other.Ties...)
+	r.SourceErrors = append(r.SourceErrors, other.SourceErrors...)
+}
+
+// HasProblems reports whether anything failed to load, has schema problems
+// or was resolved by a priority tie. Ignored and shadowed files are expected
+// and don't count.
+func (r *LoadReport) HasProblems() bool {
+	return r != nil && len(r.Errors)+len(r.Diagnostics)+len(r.SourceErrors)+len(r.Ties) > 0
+}
+
+// Problems describes each failed source, failed file, schema problem and
+// priority tie on a line of its own
+func (r *LoadReport) Problems() []string {
+	if r == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, e := range r.SourceErrors {
+		lines = append(lines, fmt.Sprintf("failed to load source %s (%s): %s", e.Source, e.Path, e.Message))
+	}
+	for _, e := range r.Errors {
+		lines = append(lines, e.Error())
+	}
+	for _, d := range r.Diagnostics {
+		lines = append(lines, d.Error())
+	}
+	for _, tie := range r.Ties {
+		sources := joinNames(append([]string{tie.Winner}, tie.Others...))
+		lines = append(lines, fmt.Sprintf("%s: sources %s share priority %d; %s wins as it is listed first in the config", tie.ID, sources, tie.Priority, tie.Winner))
+	}
+	return lines
+}
+
+// setSource fills in the source of entries that don't name one
+func (r *LoadReport) setSource(source string) {
+	for i := range r.Errors {
+		if r.Errors[i].Source == "" {
+			r.Errors[i].Source = source
+		}
+	}
+	for i := range r.Diagnostics {
+		if r.Diagnostics[i].Source == "" {
+			r.Diagnostics[i].Source = source
+		}
+	}
+	for i := range r.Ignored {
+		if r.Ignored[i].Source == "" {
+			r.Ignored[i].Source = source
+		}
+	}
+}
+
+// fileError turns a load error into a file error, keeping the position of
+// a Diagnostic
+func fileError(filePath string, err error) FileError {
+	var d Diagnostic
+	if errors.As(err, &d) {
+		return FileError{Diagnostic: d}
+	}
+	return FileError{Diagnostic: Diagnostic{File: filePath, Message: err.Error()}}
+}
+
+// sortShadowed orders shadowed copies by ID, then by priority
+func sortShadowed(shadowed []Shadowed) {
+	sort.SliceStable(shadowed, func(i, j int) bool {
+		if shadowed[i].ID != shadowed[j].ID {
+			return shadowed[i].ID < shadowed[j].ID
+		}
+		return shadowed[i].Priority < shadowed[j].Priority
+	})
+}
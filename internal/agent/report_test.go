@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReport(t *testing.T) {
+	t.Run("records failed and ignored files instead of printing", func(t *testing.T) {
+		dir := t.TempDir()
+		createTestAgent(t, dir, "frontend", "1.0.0", "Builds UIs", "Body")
+		broken := filepath.Join(dir, "broken.md")
+		require.NoError(t, os.WriteFile(broken, []byte("---\nname: broken\ndescription: a: b\n---\nBody\n"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "drafts"), 0755))
+		createTestAgent(t, filepath.Join(dir, "drafts"), "draft", "0.1.0", "Not ready", "Body")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".camiignore"), []byte("drafts/\n"), 0644))
+
+		agents, report, err := LoadAgents(dir)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, broken, report.Errors[0].File)
+		assert.Equal(t, 3, report.Errors[0].Line)
+
+		require.Len(t, report.Ignored, 1)
+		assert.Equal(t, filepath.Join(dir, "drafts", "draft.md"), report.Ignored[0].File)
+
+		assert.True(t, report.HasProblems())
+		require.Len(t, report.Problems(), 1)
+		assert.Contains(t, report.Problems()[0], "broken.md:3")
+	})
+
+	t.Run("records schema diagnostics of loaded agents", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "frontend.md"), []byte("---\nname: frontend\ndescription: Builds UIs\ncolor: magenta\n---\nBody\n"), 0644))
+
+		agents, report, err := LoadAgents(dir)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		require.Len(t, report.Diagnostics, 1)
+		assert.Equal(t, "color", report.Diagnostics[0].Key)
+		assert.Empty(t, report.Errors)
+	})
+
+	t.Run("records shadowed copies and failed sources by name", func(t *testing.T) {
+		high, low := t.TempDir(), t.TempDir()
+		createTestAgent(t, high, "frontend", "2.0.0", "High priority", "Body")
+		lowFile := createTestAgent(t, low, "frontend", "1.0.0", "Low priority", "Body")
+		require.NoError(t, os.WriteFile(filepath.Join(low, "broken.md"), []byte("---\nname: [\n---\n"), 0644))
+
+		agents, report, err := LoadAgentsFromSources([]AgentSource{
+			{Name: "low", Path: low, Priority: 100},
+			{Name: "missing", Path: filepath.Join(low, "missing"), Priority: 1},
+			{Name: "high", Path: high, Priority: 10},
+		})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		assert.Equal(t, "high", agents[0].Source)
+
+		assert.Equal(t, []Shadowed{{
+			ID:         "frontend",
+			Version:    "1.0.0",
+			Source:     "low",
+			Priority:   100,
+			File:       lowFile,
+			By:         "high",
+			ByPriority: 10,
+		}}, report.Shadowed)
+
+		require.Len(t, report.SourceErrors, 1)
+		assert.Equal(t, "missing", report.SourceErrors[0].Source)
+
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "low", report.Errors[0].Source)
+	})
+
+	t.Run("merges reports", func(t *testing.T) {
+		report := &LoadReport{}
+		report.Merge(&LoadReport{Ignored: []IgnoredFile{{File: "a.md"}}})
+		report.Merge(nil)
+		report.Merge(&LoadReport{SourceErrors: []SourceError{{Source: "team", Path: "/x", Message: "gone"}}})
+
+		assert.Len(t, report.Ignored, 1)
+		assert.Equal(t, []string{"failed to load source team (/x): gone"}, report.Problems())
+
+		var none *LoadReport
+		assert.False(t, none.HasProblems())
+	})
+}
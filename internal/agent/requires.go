@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requiresKey is the frontmatter key listing what an agent depends on
+const requiresKey = "requires"
+
+// CycleError is returned when agents require each other in a loop
+type CycleError struct {
+	Cycle []string // IDs along the loop, starting and ending with the same agent
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// MissingDependency is a requirement that could not be resolved
+type MissingDependency struct {
+	Agent       string // ID of the agent declaring the requirement
+	Requirement string
+	Err         error
+}
+
+// MissingDependencyError is returned when agents require others that no
+// source provides, or in no acceptable version
+type MissingDependencyError struct {
+	Missing []MissingDependency
+}
+
+func (e *MissingDependencyError) Error() string {
+	parts := make([]string, len(e.Missing))
+	for i, m := range e.Missing {
+		parts[i] = fmt.Sprintf("%s requires %s (%v)", m.Agent, m.Requirement, m.Err)
+	}
+	return "missing dependencies: " + strings.Join(parts, "; ")
+}
+
+// Requires returns what the agent depends on, from its requires frontmatter
+// key: agents, commands as /name and skills as skill:name, each optionally
+// with @constraint. A single requirement may be given as a plain string.
+func (a *Agent) Requires() ([]string, error) {
+	if a.Frontmatter == nil || !a.Frontmatter.Has(requiresKey) {
+		return nil, nil
+	}
+
+	if value := a.Frontmatter.String(requiresKey); value != "" {
+		return []string{value}, nil
+	}
+
+	var requires []string
+	if err := a.Frontmatter.Decode(requiresKey, &requires); err != nil {
+		return nil, fmt.Errorf("failed to parse %s frontmatter: %w", requiresKey, err)
+	}
+	return requires, nil
+}
+
+// ResolveDependencies returns agents followed by everything they require,
+// transitively, each once. Lookup resolves a requirement to the agent to
+// deploy; it should return an agent already in the result when asked for one
+// again. Requirements lookup fails on are collected into a
+// MissingDependencyError, and requirement loops are reported as a CycleError.
+func ResolveDependencies(agents []*Agent, lookup func(requirement string) (*Agent, error)) ([]*Agent, error) {
+	resolved := append([]*Agent(nil), agents...)
+	included := make(map[string]bool)
+	for _, ag := range agents {
+		included[ag.ID()] = true
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var path []string
+	var missing []MissingDependency
+
+	var visit func(ag *Agent) error
+	visit = func(ag *Agent) error {
+		id := ag.ID()
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			for i, step := range path {
+				if step == id {
+					return &CycleError{Cycle: append(append([]string(nil), path[i:]...), id)}
+				}
+			}
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+
+		requires, err := ag.Requires()
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+
+		for _, requirement := range requires {
+			dep, err := lookup(requirement)
+			if err != nil {
+				missing = append(missing, MissingDependency{Agent: id, Requirement: requirement, Err: err})
+				continue
+			}
+
+			if !included[dep.ID()] {
+				included[dep.ID()] = true
+				resolved = append(resolved, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, ag := range agents {
+		if err := visit(ag); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &MissingDependencyError{Missing: missing}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequires(t *testing.T) {
+	t.Run("list of requirements", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: orchestrator\nrequires:\n  - frontend@^2\n  - /review\n---\nBody\n"), "/fake/orchestrator.md")
+		require.NoError(t, err)
+
+		requires, err := ag.Requires()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend@^2", "/review"}, requires)
+	})
+
+	t.Run("single requirement", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: orchestrator\nrequires: frontend\n---\nBody\n"), "/fake/orchestrator.md")
+		require.NoError(t, err)
+
+		requires, err := ag.Requires()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend"}, requires)
+	})
+
+	t.Run("no requirements", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: frontend\n---\nBody\n"), "/fake/frontend.md")
+		require.NoError(t, err)
+
+		requires, err := ag.Requires()
+		require.NoError(t, err)
+		assert.Empty(t, requires)
+	})
+}
+
+func TestResolveDependencies(t *testing.T) {
+	parse := func(t *testing.T, name string, requires ...string) *Agent {
+		t.Helper()
+		content := "---\nname: " + name + "\n"
+		if len(requires) > 0 {
+			content += "requires:\n"
+			for _, req := range requires {
+				content += "  - " + req + "\n"
+			}
+		}
+		ag, err := ParseAgent([]byte(content+"---\nBody\n"), "/fake/"+name+".md")
+		require.NoError(t, err)
+		return ag
+	}
+
+	lookupIn := func(agents ...*Agent) func(string) (*Agent, error) {
+		byID := make(map[string]*Agent)
+		for _, ag := range agents {
+			byID[ag.ID()] = ag
+		}
+		return func(requirement string) (*Agent, error) {
+			if ag, ok := byID[requirement]; ok {
+				return ag, nil
+			}
+			return nil, fmt.Errorf("agent not found: %s", requirement)
+		}
+	}
+
+	ids := func(agents []*Agent) []string {
+		var ids []string
+		for _, ag := range agents {
+			ids = append(ids, ag.ID())
+		}
+		return ids
+	}
+
+	t.Run("transitive closure, requested agents first", func(t *testing.T) {
+		orchestrator := parse(t, "orchestrator", "frontend", "backend")
+		frontend := parse(t, "frontend", "designer")
+		backend := parse(t, "backend", "designer")
+		designer := parse(t, "designer")
+		other := parse(t, "other")
+
+		resolved, err := ResolveDependencies([]*Agent{other, orchestrator}, lookupIn(orchestrator, frontend, backend, designer, other))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other", "orchestrator", "frontend", "designer", "backend"}, ids(resolved))
+	})
+
+	t.Run("requested dependencies are not repeated", func(t *testing.T) {
+		orchestrator := parse(t, "orchestrator", "frontend")
+		frontend := parse(t, "frontend")
+
+		resolved, err := ResolveDependencies([]*Agent{orchestrator, frontend}, lookupIn(orchestrator, frontend))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orchestrator", "frontend"}, ids(resolved))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		a := parse(t, "a", "b")
+		b := parse(t, "b", "c")
+		c := parse(t, "c", "a")
+
+		_, err := ResolveDependencies([]*Agent{a}, lookupIn(a, b, c))
+		var cycle *CycleError
+		require.True(t, errors.As(err, &cycle), "got %v", err)
+		assert.Equal(t, []string{"a", "b", "c", "a"}, cycle.Cycle)
+		assert.EqualError(t, err, "dependency cycle: a -> b -> c -> a")
+	})
+
+	t.Run("reports every missing dependency", func(t *testing.T) {
+		orchestrator := parse(t, "orchestrator", "ghost", "frontend")
+		frontend := parse(t, "frontend", "phantom")
+
+		_, err := ResolveDependencies([]*Agent{orchestrator}, lookupIn(orchestrator, frontend))
+		var missing *MissingDependencyError
+		require.True(t, errors.As(err, &missing), "got %v", err)
+		require.Len(t, missing.Missing, 2)
+		assert.Equal(t, "orchestrator", missing.Missing[0].Agent)
+		assert.Equal(t, "ghost", missing.Missing[0].Requirement)
+		assert.Equal(t, "frontend", missing.Missing[1].Agent)
+		assert.Equal(t, "phantom", missing.Missing[1].Requirement)
+	})
+}
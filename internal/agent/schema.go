@@ -0,0 +1,185 @@
+package agent
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the agent frontmatter schema CAMI ships.
+// It changes only when a change to the schema would reject frontmatter an
+// earlier version accepted.
+const SchemaVersion = 1
+
+//go:embed agent.v1.schema.json
+var schemaJSON []byte
+
+// Schema returns the JSON Schema for agent frontmatter: the CAMI keys plus
+// the keys Claude Code reads, such as tools, model and color. Keys it
+// doesn't describe are allowed.
+func Schema() []byte {
+	return schemaJSON
+}
+
+// frontmatterSchema is the parsed schema, with each top-level property
+// resolved on its own so a violation can be placed on its key's line
+var frontmatterSchema = sync.OnceValues(func() (*parsedSchema, error) {
+	var root jsonschema.Schema
+	if err := json.Unmarshal(schemaJSON, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse agent schema: %w", err)
+	}
+
+	parsed := &parsedSchema{
+		required:     root.Required,
+		properties:   make(map[string]*jsonschema.Resolved, len(root.Properties)),
+		descriptions: make(map[string]string, len(root.Properties)),
+	}
+	for key, property := range root.Properties {
+		resolved, err := property.CloneSchemas().Resolve(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve agent schema property %s: %w", key, err)
+		}
+		parsed.properties[key] = resolved
+		parsed.descriptions[key] = property.Description
+	}
+	return parsed, nil
+})
+
+type parsedSchema struct {
+	required     []string
+	properties   map[string]*jsonschema.Resolved
+	descriptions map[string]string
+}
+
+// Diagnostic is a problem found in an agent file while loading it, placed
+// at a 1-based line and column when it concerns one spot
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Key     string `json:"key,omitempty"` // Frontmatter key the problem is in, if any
+	Message string `json:"message"`
+}
+
+// Error formats the diagnostic as file:line:column: message
+func (d Diagnostic) Error() string {
+	position := d.File
+	if d.Line > 0 {
+		position += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			position += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return position + ": " + d.Message
+}
+
+// Validate checks the agent's frontmatter against Schema, returning a
+// diagnostic for each missing required key and each key whose value the
+// schema rejects. Lines are lines of the agent file, counting the opening
+// --- as line 1.
+func (a *Agent) Validate() []Diagnostic {
+	if a.Frontmatter == nil {
+		return nil
+	}
+
+	schema, err := frontmatterSchema()
+	if err != nil {
+		return []Diagnostic{{File: a.FilePath, Message: err.Error()}}
+	}
+
+	var diagnostics []Diagnostic
+	for _, key := range schema.required {
+		if !a.Frontmatter.Has(key) {
+			diagnostics = append(diagnostics, Diagnostic{
+				File:    a.FilePath,
+				Line:    1,
+				Column:  1,
+				Key:     key,
+				Message: fmt.Sprintf("missing required key %q", key),
+			})
+		}
+	}
+
+	mapping := a.Frontmatter.Node()
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i].Value, mapping.Content[i+1]
+		property, ok := schema.properties[key]
+		if !ok {
+			continue
+		}
+
+		if err := validateNode(property, value); err != nil {
+			message := err.Error()
+			// The validator can't say which alternative of a oneOf or anyOf
+			// was meant, so describe what the key takes instead
+			if strings.Contains(message, "did not validate against any of") {
+				message = fmt.Sprintf("invalid value (expected: %s)", schema.descriptions[key])
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				File:    a.FilePath,
+				Line:    value.Line + 1,
+				Column:  value.Column,
+				Key:     key,
+				Message: fmt.Sprintf("%s: %s", key, message),
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// validateNode validates a YAML value as the JSON value it stands for
+func validateNode(schema *jsonschema.Resolved, node *yaml.Node) error {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("value cannot be represented as JSON")
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if err := schema.Validate(value); err != nil {
+		return fmt.Errorf("%s", schemaMessage(err))
+	}
+	return nil
+}
+
+// schemaMessage drops the "validating <schema>:" context the validator
+// wraps its errors in, leaving the violation itself
+func schemaMessage(err error) string {
+	message := err.Error()
+	for strings.HasPrefix(message, "validating ") {
+		_, rest, ok := strings.Cut(message, ": ")
+		if !ok {
+			break
+		}
+		message = rest
+	}
+	return message
+}
+
+// yamlLine matches the line number yaml.v3 puts in its syntax errors
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// frontmatterError turns a frontmatter parse error into a diagnostic on the
+// line of the file it occurred on
+func frontmatterError(filePath string, err error) Diagnostic {
+	d := Diagnostic{File: filePath, Message: "failed to parse frontmatter: " + err.Error()}
+	if match := yamlLine.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		d.Line = line + 1
+		d.Message = "failed to parse frontmatter: " + strings.TrimPrefix(err.Error(), match[0])
+	}
+	return d
+}
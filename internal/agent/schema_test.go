@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("is valid JSON with a versioned id", func(t *testing.T) {
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(Schema(), &schema))
+		assert.Contains(t, schema["$id"], "agent.v1.schema.json")
+	})
+
+	t.Run("names every known class", func(t *testing.T) {
+		var schema struct {
+			Properties struct {
+				Class struct {
+					Enum []string `json:"enum"`
+				} `json:"class"`
+			} `json:"properties"`
+		}
+		require.NoError(t, json.Unmarshal(Schema(), &schema))
+		assert.ElementsMatch(t, Classes(), schema.Properties.Class.Enum)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts valid frontmatter", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: frontend\nversion: \"1.2.0\"\ndescription: Builds UIs\nclass: technology-implementer\ntools: [Read, Write]\nmodel: sonnet\ncolor: blue\nrequires: backend\nmcpServers:\n  github:\n    command: gh-mcp\ncustom: kept\n---\nBody\n"), "frontend.md")
+		require.NoError(t, err)
+		assert.Empty(t, ag.Validate())
+	})
+
+	t.Run("places violations on their line and column", func(t *testing.T) {
+		ag, err := ParseAgent([]byte("---\nname: Frontend Dev\nversion: 1.0\nclass: wizard\ncolor:   magenta\ntools: 3\n---\nBody\n"), "frontend.md")
+		require.NoError(t, err)
+
+		diagnostics := ag.Validate()
+		require.Len(t, diagnostics, 6)
+
+		assert.Equal(t, Diagnostic{File: "frontend.md", Line: 1, Column: 1, Key: "description", Message: `missing required key "description"`}, diagnostics[0])
+
+		byKey := make(map[string]Diagnostic)
+		for _, d := range diagnostics[1:] {
+			byKey[d.Key] = d
+		}
+		assert.Equal(t, 2, byKey["name"].Line)
+		assert.Equal(t, 3, byKey["version"].Line)
+		assert.Contains(t, byKey["version"].Message, "string")
+		assert.Equal(t, 4, byKey["class"].Line)
+		assert.Equal(t, 5, byKey["color"].Line)
+		assert.Equal(t, 10, byKey["color"].Column)
+		assert.Equal(t, 6, byKey["tools"].Line)
+		assert.Equal(t, "frontend.md:5:10: "+byKey["color"].Message, byKey["color"].Error())
+	})
+
+	t.Run("loading collects diagnostics instead of failing", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "frontend.md"), []byte("---\nname: frontend\nversion: \"1.0.0\"\ndescription: Builds UIs\ncolor: magenta\n---\nBody\n"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "commands"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "commands", "review.md"), []byte("---\ncolor: magenta\n---\nReview\n"), 0644))
+
+		agents, _, err := LoadAgents(dir)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		require.Len(t, agents[0].Diagnostics, 1)
+		assert.Equal(t, "color", agents[0].Diagnostics[0].Key)
+
+		commands, _, err := LoadCommands(dir)
+		require.NoError(t, err)
+		require.Len(t, commands, 1)
+		assert.Empty(t, commands[0].Diagnostics)
+	})
+
+	t.Run("frontmatter syntax errors carry the file line", func(t *testing.T) {
+		_, err := ParseAgent([]byte("---\nname: frontend\ndescription: [unclosed\n---\nBody\n"), "frontend.md")
+		require.Error(t, err)
+
+		var d Diagnostic
+		require.ErrorAs(t, err, &d)
+		assert.Equal(t, "frontend.md", d.File)
+		assert.Greater(t, d.Line, 1)
+		assert.Contains(t, err.Error(), "failed to parse frontmatter")
+	})
+}
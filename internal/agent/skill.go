@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SkillFile is the file that defines a skill at the top of its directory
+const SkillFile = "SKILL.md"
+
+// skillPrefix marks a name as a skill wherever artifacts are requested
+// together, e.g. "frontend,skill:pdf"
+const skillPrefix = "skill:"
+
+// skillDirs are where a source keeps its skills, relative to its root. Each
+// skill is a directory holding a SKILL.md and any supporting files.
+var skillDirs = []string{"skills", filepath.Join(".claude", "skills")}
+
+// LoadSkills reads all skills from a source directory's skills/ or
+// .claude/skills/ folder, with their supporting files. Folders above a
+// skill's directory become its category.
+func LoadSkills(dir string) ([]*Agent, *LoadReport, error) {
+	return loadArtifacts(dir, KindSkill)
+}
+
+// LoadSkillsFromSources loads skills from multiple sources with the same
+// priority-based deduplication as LoadAgentsFromSources
+func LoadSkillsFromSources(sources []AgentSource) ([]*Agent, *LoadReport, error) {
+	return loadFromSources(sources, KindSkill)
+}
+
+// LoadSkillsFromFiles parses the skills among in-memory source files, as
+// LoadAgentsFromFiles does for agents
+func LoadSkillsFromFiles(root string, files map[string][]byte) []*Agent {
+	return loadFromFiles(root, files, KindSkill)
+}
+
+// LoadSkill parses a skill from its SKILL.md and reads every other file in
+// its directory as a supporting file
+func LoadSkill(filePath string) (*Agent, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	skill, err := ParseSkill(data, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if skill.Files, err = readSkillFiles(filepath.Dir(filePath)); err != nil {
+		return nil, err
+	}
+	return skill, nil
+}
+
+// ParseSkill parses SKILL.md content without its supporting files. Skills
+// are named after their directory unless the frontmatter names them. The
+// name becomes the skill's deployed directory, so it must be a single path
+// segment.
+func ParseSkill(data []byte, filePath string) (*Agent, error) {
+	skill, err := parseOptionalFrontmatter(data, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	skill.Kind = KindSkill
+	if skill.Name == "" {
+		skill.Name = filepath.Base(filepath.Dir(filePath))
+	}
+	if err := ValidateSkillName(skill.Name); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return skill, nil
+}
+
+// ValidateSkillName checks a skill name can be used as the directory it is
+// deployed to: one path segment, not "." or ".."
+func ValidateSkillName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid skill name %q: must be a single directory name", name)
+	}
+	return nil
+}
+
+// SkillFiles picks the supporting files of the skill defined by skillFile
+// out of files keyed by slash-separated path, keyed by their path relative
+// to the skill's directory
+func SkillFiles(files map[string][]byte, skillFile string) map[string][]byte {
+	prefix := path.Dir(filepath.ToSlash(skillFile)) + "/"
+
+	supporting := make(map[string][]byte)
+	for p, data := range files {
+		if rel, ok := strings.CutPrefix(p, prefix); ok && rel != SkillFile {
+			supporting[rel] = data
+		}
+	}
+	return supporting
+}
+
+// IsSkillFile reports whether a source file, given by its path relative to
+// the source root, belongs to a skill
+func IsSkillFile(relPath string) bool {
+	kind, _ := kindOf(filepath.FromSlash(relPath))
+	return kind == KindSkill
+}
+
+// Tree returns every file deploying the artifact writes, keyed by
+// slash-separated path relative to where it is deployed: its one file, or a
+// skill's SKILL.md and supporting files
+func (a *Agent) Tree() map[string][]byte {
+	if a.ArtifactKind() != KindSkill {
+		return map[string][]byte{a.FileName(): []byte(a.FullContent())}
+	}
+
+	tree := make(map[string][]byte, len(a.Files)+1)
+	for p, data := range a.Files {
+		tree[p] = data
+	}
+	tree[SkillFile] = []byte(a.FullContent())
+	return tree
+}
+
+// readSkillFiles reads the supporting files in a skill's directory
+func readSkillFiles(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == SkillFile {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skill files: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSkills(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		createTestAgent(t, dir, "frontend", "1.0.0", "Frontend", "Agent content")
+
+		pdfDir := filepath.Join(dir, "skills", "documents", "pdf")
+		require.NoError(t, os.MkdirAll(filepath.Join(pdfDir, "scripts"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(pdfDir, SkillFile), []byte("---\nname: pdf\ndescription: Work with PDFs\n---\nUse the scripts.\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(pdfDir, "reference.md"), []byte("# Reference\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(pdfDir, "scripts", "extract.py"), []byte("print('extract')\n"), 0644))
+
+		notesDir := filepath.Join(dir, "skills", "notes")
+		require.NoError(t, os.MkdirAll(notesDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(notesDir, SkillFile), []byte("Take notes.\n"), 0644))
+		return dir
+	}
+
+	t.Run("skills are loaded with their supporting files", func(t *testing.T) {
+		skills, _, err := LoadSkills(setup(t))
+		require.NoError(t, err)
+		require.Len(t, skills, 2)
+
+		byID := make(map[string]*Agent)
+		for _, skill := range skills {
+			assert.Equal(t, KindSkill, skill.Kind)
+			byID[skill.ID()] = skill
+		}
+
+		pdf := byID["skill:pdf"]
+		require.NotNil(t, pdf)
+		assert.Equal(t, "documents", pdf.Category)
+		assert.Equal(t, "Work with PDFs", pdf.Description)
+		assert.Equal(t, map[string][]byte{
+			"reference.md":       []byte("# Reference\n"),
+			"scripts/extract.py": []byte("print('extract')\n"),
+		}, pdf.Files)
+
+		notes := byID["skill:notes"]
+		require.NotNil(t, notes)
+		assert.Equal(t, "", notes.Category)
+		assert.Equal(t, "Take notes.\n", notes.FullContent())
+	})
+
+	t.Run("agents exclude skill files", func(t *testing.T) {
+		agents, _, err := LoadAgents(setup(t))
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		assert.Equal(t, "frontend", agents[0].Name)
+	})
+
+	t.Run("skills from files", func(t *testing.T) {
+		files := map[string][]byte{
+			"frontend.md":                  []byte("---\nname: frontend\n---\n"),
+			"skills/pdf/SKILL.md":          []byte("---\nname: pdf\n---\nUse the scripts.\n"),
+			"skills/pdf/scripts/run.sh":    []byte("echo run\n"),
+			".claude/skills/lint/SKILL.md": []byte("Lint.\n"),
+		}
+
+		skills := LoadSkillsFromFiles("/sources/team", files)
+		require.Len(t, skills, 2)
+		assert.Equal(t, "skill:lint", skills[0].ID())
+		assert.Equal(t, "skill:pdf", skills[1].ID())
+		assert.Equal(t, map[string][]byte{"scripts/run.sh": []byte("echo run\n")}, skills[1].Files)
+		assert.Len(t, LoadAgentsFromFiles("/sources/team", files), 1)
+	})
+}
+
+func TestParseSkillName(t *testing.T) {
+	t.Run("names that aren't one directory are rejected", func(t *testing.T) {
+		for _, name := range []string{`"."`, `".."`, "../../../x", "docs/pdf", `'a\b'`} {
+			_, err := ParseSkill([]byte("---\nname: "+name+"\n---\nBody\n"), "/sources/team/skills/pdf/SKILL.md")
+			assert.ErrorContains(t, err, "invalid skill name", name)
+		}
+	})
+
+	t.Run("the directory name is used without a name", func(t *testing.T) {
+		skill, err := ParseSkill([]byte("Body\n"), "/sources/team/skills/pdf/SKILL.md")
+		require.NoError(t, err)
+		assert.Equal(t, "pdf", skill.Name)
+	})
+}
+
+func TestSkillTree(t *testing.T) {
+	skill, err := ParseSkill([]byte("Use the scripts.\n"), "/skills/pdf/SKILL.md")
+	require.NoError(t, err)
+	skill.Files = map[string][]byte{"scripts/run.sh": []byte("echo run\n")}
+
+	assert.Equal(t, "pdf", skill.Name)
+	assert.Equal(t, map[string][]byte{
+		SkillFile:        []byte("Use the scripts.\n"),
+		"scripts/run.sh": []byte("echo run\n"),
+	}, skill.Tree())
+
+	ag := &Agent{Name: "frontend", FilePath: "/agents/frontend.md", Content: "Body\n"}
+	assert.Equal(t, []string{"frontend.md"}, mapKeys(ag.Tree()))
+	assert.True(t, IsSkillFile("skills/pdf/scripts/run.sh"))
+	assert.False(t, IsSkillFile("core/frontend.md"))
+}
+
+func mapKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -49,6 +49,17 @@ const (
 	CleanupThreshold = 10
 )
 
+// BackupPath returns where a backup of the target directory taken at the
+// given time goes
+func BackupPath(targetPath string, at time.Time) string {
+	// Generate backup directory name with timestamp
+	timestamp := at.Format("20060102-150405")
+	backupName := fmt.Sprintf("%s%s", BackupPrefix, timestamp)
+
+	// Backup goes in the same parent directory as target
+	return filepath.Join(filepath.Dir(targetPath), backupName)
+}
+
 // CreateBackup creates a backup of the target directory
 func CreateBackup(targetPath string) (string, error) {
 	// Validate target exists
@@ -60,13 +71,7 @@ func CreateBackup(targetPath string) (string, error) {
 		return "", fmt.Errorf("target path is not a directory: %s", targetPath)
 	}
 
-	// Generate backup directory name with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	backupName := fmt.Sprintf("%s%s", BackupPrefix, timestamp)
-
-	// Backup goes in the same parent directory as target
-	parentDir := filepath.Dir(targetPath)
-	backupPath := filepath.Join(parentDir, backupName)
+	backupPath := BackupPath(targetPath, time.Now())
 
 	// Copy entire directory
 	if err := copyDir(targetPath, backupPath); err != nil {
@@ -0,0 +1,182 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the folder of a source that holds bundle definitions
+const Dir = "bundles"
+
+// Bundle is a named group of agents, commands and skills a source offers to
+// deploy as one unit
+type Bundle struct {
+	Name        string   `yaml:"name" json:"name"` // Defaults to the file name
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Agents      []string `yaml:"agents" json:"agents"` // "name" or "name@constraint"; commands as /name, skills as skill:name
+	Source      string   `yaml:"-" json:"source,omitempty"`
+	FilePath    string   `yaml:"-" json:"file_path"`
+}
+
+// Load reads the bundles defined in a source's bundles/*.yaml files. A source
+// without a bundles folder has no bundles. Files that fail to load are
+// skipped and recorded in the report, as agent files are.
+func Load(sourcePath string) ([]*Bundle, *agent.LoadReport, error) {
+	report := &agent.LoadReport{}
+
+	dir := filepath.Join(sourcePath, Dir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, report, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s directory: %w", Dir, err)
+	}
+
+	var bundles []*Bundle
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		filePath := filepath.Join(dir, entry.Name())
+		b, err := LoadFile(filePath)
+		if err != nil {
+			d := agent.Diagnostic{File: filePath, Message: err.Error()}
+			errors.As(err, &d)
+			report.Errors = append(report.Errors, agent.FileError{Diagnostic: d})
+			continue
+		}
+		bundles = append(bundles, b)
+	}
+
+	sortByName(bundles)
+	return bundles, report, nil
+}
+
+// LoadFile reads and validates one bundle definition. Errors are
+// agent.Diagnostic values naming the file.
+func LoadFile(filePath string) (*Bundle, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, agent.Diagnostic{File: filePath, Message: fmt.Sprintf("failed to read bundle: %v", err)}
+	}
+
+	b := &Bundle{}
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, agent.Diagnostic{File: filePath, Message: fmt.Sprintf("failed to parse bundle: %v", err)}
+	}
+	b.FilePath = filePath
+	if b.Name == "" {
+		b.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+
+	if err := b.Validate(); err != nil {
+		return nil, agent.Diagnostic{File: filePath, Message: fmt.Sprintf("invalid bundle: %v", err)}
+	}
+	return b, nil
+}
+
+// LoadFromSources loads bundles from multiple sources, keeping the highest
+// priority (lowest number) definition of each name. Bundle files and sources
+// that fail to load are skipped and recorded in the report, as agents are.
+func LoadFromSources(sources []agent.AgentSource) ([]*Bundle, *agent.LoadReport, error) {
+	report := &agent.LoadReport{}
+	byName := make(map[string]*Bundle)
+	priorities := make(map[string]int)
+
+	for _, source := range sources {
+		bundles, sourceReport, err := Load(source.Path)
+		if err != nil {
+			report.SourceErrors = append(report.SourceErrors, agent.SourceError{Source: source.Name, Path: source.Path, Message: err.Error()})
+			continue
+		}
+		for i := range sourceReport.Errors {
+			sourceReport.Errors[i].Source = source.Name
+		}
+		report.Merge(sourceReport)
+
+		for _, b := range bundles {
+			b.Source = source.Name
+			if existing, ok := priorities[b.Name]; !ok || source.Priority < existing {
+				byName[b.Name] = b
+				priorities[b.Name] = source.Priority
+			}
+		}
+	}
+
+	bundles := make([]*Bundle, 0, len(byName))
+	for _, b := range byName {
+		bundles = append(bundles, b)
+	}
+	sortByName(bundles)
+	return bundles, report, nil
+}
+
+// Find returns the bundle with a name, or nil
+func Find(bundles []*Bundle, name string) *Bundle {
+	for _, b := range bundles {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// Validate checks the bundle lists at least one agent and that every entry
+// is a valid requirement naming a different agent
+func (b *Bundle) Validate() error {
+	if len(b.Agents) == 0 {
+		return fmt.Errorf("bundle %s lists no agents", b.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range b.Agents {
+		req, err := spec.ParseRequirement(name)
+		if err != nil {
+			return err
+		}
+		if seen[req.Name] {
+			return fmt.Errorf("bundle %s lists %s more than once", b.Name, req.Name)
+		}
+		seen[req.Name] = true
+	}
+	return nil
+}
+
+// Contains reports whether the bundle lists an agent, given by ID
+func (b *Bundle) Contains(id string) bool {
+	for _, name := range b.Agents {
+		if req, err := spec.ParseRequirement(name); err == nil && req.Name == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Tag returns copies of agents marked as deployed from the bundle, so the
+// project manifest records it
+func (b *Bundle) Tag(agents []*agent.Agent) []*agent.Agent {
+	tagged := make([]*agent.Agent, len(agents))
+	for i, ag := range agents {
+		copied := *ag
+		copied.Bundle = b.Name
+		tagged[i] = &copied
+	}
+	return tagged
+}
+
+func sortByName(bundles []*Bundle) {
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Name < bundles[j].Name
+	})
+}
@@ -0,0 +1,113 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBundle(t *testing.T, sourcePath, fileName, content string) {
+	t.Helper()
+	dir := filepath.Join(sourcePath, Dir)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0644))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("bundles are read and sorted by name", func(t *testing.T) {
+		dir := t.TempDir()
+		writeBundle(t, dir, "web-stack.yaml", "description: Web apps\nagents:\n  - frontend@^2\n  - backend\n")
+		writeBundle(t, dir, "api.yml", "name: api-stack\nagents: [backend, /review]\n")
+		writeBundle(t, dir, "README.md", "# Bundles\n")
+
+		bundles, report, err := Load(dir)
+		require.NoError(t, err)
+		assert.False(t, report.HasProblems())
+		require.Len(t, bundles, 2)
+
+		assert.Equal(t, "api-stack", bundles[0].Name)
+		assert.Equal(t, []string{"backend", "/review"}, bundles[0].Agents)
+		assert.Equal(t, "web-stack", bundles[1].Name)
+		assert.Equal(t, "Web apps", bundles[1].Description)
+		assert.Equal(t, filepath.Join(dir, Dir, "web-stack.yaml"), bundles[1].FilePath)
+	})
+
+	t.Run("source without bundles", func(t *testing.T) {
+		bundles, report, err := Load(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, bundles)
+		assert.False(t, report.HasProblems())
+	})
+
+	t.Run("invalid bundles are reported and the rest still load", func(t *testing.T) {
+		dir := t.TempDir()
+		writeBundle(t, dir, "empty.yaml", "description: Nothing\n")
+		writeBundle(t, dir, "twice.yaml", "agents: [frontend, frontend@^2]\n")
+		writeBundle(t, dir, "broken.yaml", "agents: [unclosed\n")
+		writeBundle(t, dir, "web-stack.yaml", "agents: [frontend]\n")
+
+		bundles, report, err := Load(dir)
+		require.NoError(t, err)
+		require.Len(t, bundles, 1)
+		assert.Equal(t, "web-stack", bundles[0].Name)
+
+		require.Len(t, report.Errors, 3)
+		errs := make(map[string]string)
+		for _, e := range report.Errors {
+			errs[filepath.Base(e.File)] = e.Message
+		}
+		assert.Contains(t, errs["empty.yaml"], "lists no agents")
+		assert.Contains(t, errs["twice.yaml"], "lists frontend more than once")
+		assert.Contains(t, errs["broken.yaml"], "failed to parse bundle")
+	})
+}
+
+func TestLoadFromSources(t *testing.T) {
+	high, low, broken := t.TempDir(), t.TempDir(), t.TempDir()
+	writeBundle(t, high, "web-stack.yaml", "agents: [frontend]\n")
+	writeBundle(t, low, "web-stack.yaml", "agents: [frontend, backend]\n")
+	writeBundle(t, low, "ops.yaml", "agents: [deploy]\n")
+	writeBundle(t, broken, "empty.yaml", "agents: []\n")
+	writeBundle(t, broken, "review.yaml", "agents: [/review]\n")
+
+	bundles, report, err := LoadFromSources([]agent.AgentSource{
+		{Name: "low", Path: low, Priority: 100},
+		{Name: "high", Path: high, Priority: 10},
+		{Name: "broken", Path: broken, Priority: 50},
+	})
+	require.NoError(t, err)
+	require.Len(t, bundles, 3)
+	assert.Empty(t, report.SourceErrors)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "broken", report.Errors[0].Source)
+	assert.Equal(t, filepath.Join(broken, Dir, "empty.yaml"), report.Errors[0].File)
+	assert.Equal(t, "broken", Find(bundles, "review").Source)
+
+	web := Find(bundles, "web-stack")
+	require.NotNil(t, web)
+	assert.Equal(t, "high", web.Source)
+	assert.Equal(t, []string{"frontend"}, web.Agents)
+	assert.Equal(t, "low", Find(bundles, "ops").Source)
+	assert.Nil(t, Find(bundles, "missing"))
+}
+
+func TestBundle(t *testing.T) {
+	b := &Bundle{Name: "web-stack", Agents: []string{"frontend@^2", "/review"}}
+
+	t.Run("contains agents by ID", func(t *testing.T) {
+		assert.True(t, b.Contains("frontend"))
+		assert.True(t, b.Contains("/review"))
+		assert.False(t, b.Contains("review"))
+	})
+
+	t.Run("tag marks copies", func(t *testing.T) {
+		frontend := &agent.Agent{Name: "frontend"}
+		tagged := b.Tag([]*agent.Agent{frontend})
+		assert.Equal(t, "web-stack", tagged[0].Bundle)
+		assert.Empty(t, frontend.Bundle)
+	})
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
+	"github.com/lando/cami/internal/docs"
+	"github.com/spf13/cobra"
+)
+
+// ApplyOutput represents the JSON output for apply command
+type ApplyOutput struct {
+	Success bool                `json:"success"`
+	DryRun  bool                `json:"dry_run"`
+	Path    string              `json:"path"`
+	Items   []*deploy.ApplyItem `json:"items"`
+	Results []ResultItem        `json:"results"`
+}
+
+// NewApplyCommand creates the apply subcommand
+func NewApplyCommand(vcAgentsDir string) *cobra.Command {
+	var (
+		dryRun       bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply [location]",
+		Short: "Converge deployed agents to the project spec",
+		Long: `Make a project's .claude/agents/ match its .claude/cami.yaml.
+
+Every agent the spec lists, and every agent those require, is resolved
+against the configured sources and added or updated; agents the project
+manifest tracks that are neither listed nor required are removed. The planned adds, updates and removals are shown before
+they are applied. Custom overrides, files edited since deployment and agents
+CAMI didn't deploy are left alone.
+
+The location defaults to the current directory.`,
+		Example: `  cami apply
+  cami apply my-app --dry-run
+  cami apply ~/projects/my-app --output json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := "."
+			if len(args) > 0 {
+				location = args[0]
+			}
+			return runApply(vcAgentsDir, location, dryRun, outputFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without applying")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runApply(vcAgentsDir, location string, dryRun bool, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	projectPath, err := deploy.ResolveProjectPath(cfg, location)
+	if err != nil {
+		return err
+	}
+
+	resolver, err := newResolver(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	plan, err := deploy.PlanApply(projectPath, resolver)
+	if err != nil {
+		return err
+	}
+
+	output := ApplyOutput{
+		Success: true,
+		DryRun:  dryRun,
+		Path:    projectPath,
+		Items:   plan.Items,
+		Results: []ResultItem{},
+	}
+	if output.Items == nil {
+		output.Items = []*deploy.ApplyItem{}
+	}
+
+	if outputFormat == "text" {
+		printApplyPlan(plan, dryRun)
+	}
+
+	if !dryRun && len(plan.Changes()) > 0 {
+		results, removed, err := deploy.Apply(plan, cfg.AgentSources)
+		for _, result := range results {
+			item := ResultItem{Agent: result.Agent.ID(), Status: "success", Message: result.Message}
+			if !result.Success {
+				item.Status = "failed"
+				output.Success = false
+			}
+			output.Results = append(output.Results, item)
+		}
+		for _, result := range removed {
+			item := ResultItem{Agent: result.Name, Status: "success", Message: result.Message}
+			if !result.Success {
+				item.Status = "failed"
+				output.Success = false
+			}
+			output.Results = append(output.Results, item)
+		}
+		if err != nil {
+			return fmt.Errorf("apply failed: %w", err)
+		}
+
+		if err := docs.RefreshCLAUDEmd(projectPath, "Deployed Agents"); err != nil {
+			return fmt.Errorf("failed to update CLAUDE.md: %w", err)
+		}
+	}
+
+	// Output results
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+	} else if len(output.Results) > 0 {
+		fmt.Printf("\nResults:\n")
+		for _, item := range output.Results {
+			statusIcon := "✓"
+			if item.Status == "failed" {
+				statusIcon = "✗"
+			}
+			fmt.Printf("  %s %s: %s\n", statusIcon, item.Agent, item.Message)
+		}
+	}
+
+	// Return non-zero exit code if apply was not successful
+	if !output.Success {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func printApplyPlan(plan *deploy.ApplyPlan, dryRun bool) {
+	if dryRun {
+		fmt.Printf("Apply Plan for %s (dry run):\n\n", plan.ProjectPath)
+	} else {
+		fmt.Printf("Apply Plan for %s:\n\n", plan.ProjectPath)
+	}
+
+	if len(plan.Items) == 0 {
+		fmt.Printf("  No agents in spec or manifest\n")
+		return
+	}
+
+	for _, item := range plan.Items {
+		switch item.Action {
+		case deploy.ApplyAdd:
+			fmt.Printf("  + %s: add %s\n", item.Name, versionLabel(item.ToVersion))
+		case deploy.ApplyUpdate:
+			fmt.Printf("  ~ %s: %s → %s\n", item.Name, versionLabel(item.FromVersion), versionLabel(item.ToVersion))
+		case deploy.ApplyRemove:
+			fmt.Printf("  - %s: remove\n", item.Name)
+		case deploy.ApplyUnchanged:
+			fmt.Printf("  ✓ %s: up to date\n", item.Name)
+		default:
+			fmt.Printf("  ⚠ %s: skipped (%s)\n", item.Name, item.Reason)
+		}
+	}
+
+	if len(plan.Changes()) == 0 {
+		fmt.Printf("\nNothing to apply\n")
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/bundle"
+	"github.com/lando/cami/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// BundleListOutput represents the JSON output for bundle list command
+type BundleListOutput struct {
+	Count   int              `json:"count"`
+	Bundles []*bundle.Bundle `json:"bundles"`
+}
+
+// NewBundleCommand creates the bundle management command
+func NewBundleCommand(vcAgentsDir string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage agent bundles",
+		Long: `Manage agent bundles: named groups of agents deployed as one unit.
+
+Sources define bundles in bundles/*.yaml, each listing the agents, slash
+commands (/name) and skills (skill:name) it deploys. When sources define a
+bundle of the same name, the higher priority source wins, as with agents.
+Deploy a bundle with 'cami deploy --bundle <name>'.`,
+	}
+
+	cmd.AddCommand(NewBundleListCommand(vcAgentsDir))
+
+	return cmd
+}
+
+// NewBundleListCommand creates the bundle list command
+func NewBundleListCommand(vcAgentsDir string) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List available bundles",
+		Long:  `List the bundles defined by configured sources and the agents each deploys.`,
+		Example: `  cami bundle list
+  cami bundle list --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundleList(vcAgentsDir, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runBundleList(vcAgentsDir, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	bundles, err := loadAvailableBundles(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		output := BundleListOutput{
+			Count:   len(bundles),
+			Bundles: bundles,
+		}
+		if output.Bundles == nil {
+			output.Bundles = []*bundle.Bundle{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	if len(bundles) == 0 {
+		fmt.Println("No bundles found")
+		return nil
+	}
+
+	fmt.Printf("Available Bundles (%d):\n\n", len(bundles))
+	for _, b := range bundles {
+		fmt.Printf("  %s", b.Name)
+		if b.Source != "" {
+			fmt.Printf(" [%s]", b.Source)
+		}
+		fmt.Println()
+		if b.Description != "" {
+			fmt.Printf("    %s\n", b.Description)
+		}
+		fmt.Printf("    Agents: %s\n", strings.Join(b.Agents, ", "))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// loadAvailableBundles loads bundles from all configured sources with
+// priority, falling back to the legacy agents directory when no sources are
+// configured
+func loadAvailableBundles(vcAgentsDir string) ([]*bundle.Bundle, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var (
+		bundles []*bundle.Bundle
+		report  *agent.LoadReport
+	)
+	if len(cfg.AgentSources) == 0 {
+		bundles, report, err = bundle.Load(vcAgentsDir)
+	} else {
+		bundles, report, err = bundle.LoadFromSources(agentSources(cfg))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bundles: %w", err)
+	}
+	for _, problem := range report.Problems() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+	}
+	return bundles, nil
+}
+
+// findBundle returns the available bundle with a name
+func findBundle(vcAgentsDir, name string) (*bundle.Bundle, error) {
+	bundles, err := loadAvailableBundles(vcAgentsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	b := bundle.Find(bundles, name)
+	if b == nil {
+		return nil, fmt.Errorf("bundle not found: %s", name)
+	}
+	return b, nil
+}
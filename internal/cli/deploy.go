@@ -6,19 +6,21 @@ import (
 	"os"
 	"strings"
 
-	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/bundle"
 	"github.com/lando/cami/internal/config"
 	"github.com/lando/cami/internal/deploy"
+	"github.com/lando/cami/internal/plan"
 	"github.com/spf13/cobra"
 )
 
 // DeployOutput represents the JSON output format for deploy command
 type DeployOutput struct {
-	Success   bool         `json:"success"`
-	Deployed  []string     `json:"deployed"`
-	Failed    []string     `json:"failed"`
-	Conflicts []string     `json:"conflicts"`
-	Results   []ResultItem `json:"results"`
+	Success      bool         `json:"success"`
+	Deployed     []string     `json:"deployed"`
+	Failed       []string     `json:"failed"`
+	Conflicts    []string     `json:"conflicts"`
+	Dependencies []string     `json:"dependencies"` // Agents deployed because a requested agent requires them
+	Results      []ResultItem `json:"results"`
 }
 
 // ResultItem represents a single deployment result
@@ -32,8 +34,11 @@ type ResultItem struct {
 func NewDeployCommand(vcAgentsDir string) *cobra.Command {
 	var (
 		agentNames   string
+		bundleName   string
 		location     string
 		overwrite    bool
+		merge        bool
+		dryRun       bool
 		outputFormat string
 	)
 
@@ -41,128 +46,187 @@ func NewDeployCommand(vcAgentsDir string) *cobra.Command {
 		Use:   "deploy",
 		Short: "Deploy agents to a target project",
 		Long: `Deploy one or more agents to a target project location.
-Agents are deployed to the .claude/agents directory in the target location.`,
+Agents are deployed to the .claude/agents directory in the target location
+and tracked in the project manifest (.claude/cami-manifest.yaml) alongside
+agents deployed earlier. Files and manifests are written as one transaction:
+if any step fails, the whole deployment is rolled back.
+
+Slash commands from a source's commands/ folder are deployed to
+.claude/commands by prefixing their name with a slash, e.g. -a frontend,/review.
+Skills from a source's skills/ folder are deployed, with every file in their
+directory, to .claude/skills/<name> by prefixing their name with skill:.
+
+MCP servers an agent declares, in its mcpServers frontmatter or a sibling
+<name>.mcp.json in its source, are merged into the project's .mcp.json.
+Servers already configured there by hand are left as they are.
+
+Use --bundle instead of --agents to deploy a bundle a source defines in
+bundles/*.yaml, see 'cami bundle list'. The project manifest records the
+bundle each of its agents, and their dependencies, were deployed from.
+
+Agents that list others under requires: in their frontmatter are deployed
+with everything they require, transitively. Deployment fails if a required
+agent is missing or agents require each other in a cycle.
+
+Append @constraint to an agent name to pick a version, e.g. frontend@^2.1 or
+frontend@2.x. All versions across sources and their git tags are considered.
+Constraints declared in the project's .claude/cami.yaml apply to agents
+requested without one, and explicit versions must still satisfy them.
+
+With --merge, agents that were edited locally since they were deployed are
+three-way merged with the new source version, using the content recorded at
+deployment as the base. Overlapping edits are written with conflict markers
+and reported as conflicts.
+
+With --dry-run, the agent files that would be created, overwritten or skipped
+and the manifest entries that would be added or updated are printed, and
+nothing is written.
+
+Use --location user to deploy to the user scope, ~/.claude/agents, which
+Claude Code loads in every project. A project agent of the same name takes
+precedence over a user-scope agent.`,
 		Example: `  cami deploy --agents frontend,backend --location ~/projects/my-app
   cami deploy -a frontend,backend -l ~/projects/my-app --overwrite
+  cami deploy -a frontend -l ~/projects/my-app --merge
+  cami deploy -a frontend@^2.1,backend@2.x -l ~/projects/my-app
+  cami deploy -a code-reviewer -l user
+  cami deploy -a /review,/release-notes -l ~/projects/my-app
+  cami deploy -a skill:pdf -l ~/projects/my-app
+  cami deploy --bundle web-stack -l ~/projects/my-app
+  cami deploy -a frontend,backend -l ~/projects/my-app --overwrite --dry-run
   cami deploy -a frontend,backend -l ~/projects/my-app --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDeploy(vcAgentsDir, agentNames, location, overwrite, outputFormat)
+			return runDeploy(vcAgentsDir, agentNames, bundleName, location, overwrite, merge, dryRun, outputFormat)
 		},
 	}
 
-	cmd.Flags().StringVarP(&agentNames, "agents", "a", "", "Comma-separated list of agent names (required)")
-	cmd.Flags().StringVarP(&location, "location", "l", "", "Target project path (required)")
+	cmd.Flags().StringVarP(&agentNames, "agents", "a", "", "Comma-separated list of agents, optionally name@constraint")
+	cmd.Flags().StringVarP(&bundleName, "bundle", "b", "", "Deploy the agents of a bundle instead of a list")
+	cmd.Flags().StringVarP(&location, "location", "l", "", "Target project path, or user for ~/.claude/agents (required)")
 	cmd.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Overwrite existing files")
+	cmd.Flags().BoolVar(&merge, "merge", false, "Merge source updates into locally modified files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the planned file and manifest changes without deploying")
 	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
 
-	cmd.MarkFlagRequired("agents")
 	cmd.MarkFlagRequired("location")
+	cmd.MarkFlagsOneRequired("agents", "bundle")
+	cmd.MarkFlagsMutuallyExclusive("agents", "bundle")
+	cmd.MarkFlagsMutuallyExclusive("overwrite", "merge")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "merge")
 
 	return cmd
 }
 
-func runDeploy(vcAgentsDir, agentNames, location string, overwrite bool, outputFormat string) error {
+func runDeploy(vcAgentsDir, agentNames, bundleName, location string, overwrite, merge, dryRun bool, outputFormat string) error {
 	// Validate output format
 	if outputFormat != "text" && outputFormat != "json" {
 		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
 	}
 
+	location, err := config.ExpandUserScope(location)
+	if err != nil {
+		return err
+	}
+
 	// Validate target path
 	if err := deploy.ValidateTargetPath(location); err != nil {
 		return fmt.Errorf("invalid location: %w", err)
 	}
 
-	// Load config
-	cfg, err := config.Load()
+	resolver, err := newResolver(vcAgentsDir)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
 
-	// Load all available agents from all sources
-	var allAgents []*agent.Agent
-	if len(cfg.AgentSources) == 0 {
-		// Fall back to legacy behavior
-		allAgents, err = agent.LoadAgents(vcAgentsDir)
-		if err != nil {
-			return fmt.Errorf("failed to load agents: %w", err)
+	// Parse requested agent names, or take them from the bundle
+	var b *bundle.Bundle
+	var requestedNames []string
+	if bundleName != "" {
+		if b, err = findBundle(vcAgentsDir, bundleName); err != nil {
+			return err
 		}
+		requestedNames = b.Agents
 	} else {
-		// Convert config sources to agent sources
-		var sources []agent.AgentSource
-		for _, src := range cfg.AgentSources {
-			sources = append(sources, agent.AgentSource{
-				Path:     src.Path,
-				Priority: src.Priority,
-			})
-		}
-
-		// Load from all sources with priority
-		allAgents, err = agent.LoadAgentsFromSources(sources)
-		if err != nil {
-			return fmt.Errorf("failed to load agents: %w", err)
+		requestedNames = strings.Split(agentNames, ",")
+		for i := range requestedNames {
+			requestedNames[i] = strings.TrimSpace(requestedNames[i])
 		}
 	}
 
-	// Parse requested agent names
-	requestedNames := strings.Split(agentNames, ",")
-	for i := range requestedNames {
-		requestedNames[i] = strings.TrimSpace(requestedNames[i])
+	// Resolve each request to a version, honoring the project's spec, and
+	// pull in the agents they require
+	agentsToDeploy, err := resolver.ResolveProject(location, requestedNames)
+	if err != nil {
+		return err
 	}
-
-	// Build map of available agents
-	agentMap := make(map[string]*agent.Agent)
-	for _, ag := range allAgents {
-		agentMap[ag.Name] = ag
+	dependencies := []string{}
+	for _, ag := range agentsToDeploy[len(requestedNames):] {
+		dependencies = append(dependencies, ag.ID())
 	}
-
-	// Select agents to deploy
-	var agentsToDeploy []*agent.Agent
-	var notFound []string
-
-	for _, name := range requestedNames {
-		if ag, ok := agentMap[name]; ok {
-			agentsToDeploy = append(agentsToDeploy, ag)
-		} else {
-			notFound = append(notFound, name)
-		}
+	if b != nil {
+		agentsToDeploy = b.Tag(agentsToDeploy)
 	}
 
-	// Report agents not found
-	if len(notFound) > 0 {
-		return fmt.Errorf("agents not found: %s", strings.Join(notFound, ", "))
+	if dryRun {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		deployPlan, err := deploy.PlanDeploy(agentsToDeploy, location, overwrite, cfg.AgentSources)
+		if err != nil {
+			return fmt.Errorf("failed to plan deployment: %w", err)
+		}
+		return printPlan(deployPlan, outputFormat)
 	}
 
 	// Deploy agents
-	results, err := deploy.DeployAgents(agentsToDeploy, location, overwrite)
-	if err != nil {
-		return fmt.Errorf("deployment failed: %w", err)
+	var results []*deploy.Result
+	if merge {
+		results, err = deploy.MergeAgents(agentsToDeploy, location)
+		if err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+	} else {
+		// Deploy and track in the project and central manifests as one transaction
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		results, err = deploy.DeployAndRecord(agentsToDeploy, location, overwrite, cfg.AgentSources)
+		if err != nil {
+			return fmt.Errorf("deployment failed: %w", err)
+		}
 	}
 
 	// Process results
 	output := DeployOutput{
-		Success:   true,
-		Deployed:  []string{},
-		Failed:    []string{},
-		Conflicts: []string{},
-		Results:   []ResultItem{},
+		Success:      true,
+		Deployed:     []string{},
+		Failed:       []string{},
+		Conflicts:    []string{},
+		Dependencies: dependencies,
+		Results:      []ResultItem{},
 	}
 
 	for _, result := range results {
 		item := ResultItem{
-			Agent:   result.Agent.Name,
+			Agent:   result.Agent.ID(),
 			Message: result.Message,
 		}
 
-		if result.Success {
+		if result.Success && result.Merged {
+			item.Status = "merged"
+			output.Deployed = append(output.Deployed, result.Agent.ID())
+		} else if result.Success {
 			item.Status = "success"
-			output.Deployed = append(output.Deployed, result.Agent.Name)
+			output.Deployed = append(output.Deployed, result.Agent.ID())
 		} else if result.Conflict {
 			item.Status = "conflict"
-			output.Conflicts = append(output.Conflicts, result.Agent.Name)
+			output.Conflicts = append(output.Conflicts, result.Agent.ID())
 			output.Success = false
 		} else {
 			item.Status = "failed"
-			output.Failed = append(output.Failed, result.Agent.Name)
+			output.Failed = append(output.Failed, result.Agent.ID())
 			output.Success = false
 		}
 
@@ -178,6 +242,9 @@ func runDeploy(vcAgentsDir, agentNames, location string, overwrite bool, outputF
 		}
 	} else {
 		// Text output
+		if len(output.Dependencies) > 0 {
+			fmt.Printf("Including required agents: %s\n\n", strings.Join(output.Dependencies, ", "))
+		}
 		fmt.Printf("Deployment Results:\n\n")
 		for _, item := range output.Results {
 			statusIcon := "✓"
@@ -206,3 +273,19 @@ func runDeploy(vcAgentsDir, agentNames, location string, overwrite bool, outputF
 
 	return nil
 }
+
+// printPlan prints a dry-run plan as text or JSON
+func printPlan(p *plan.Plan, outputFormat string) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(p); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Print(p.Text())
+	fmt.Println("\nDry run: no changes were made.")
+	return nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
+	"github.com/spf13/cobra"
+)
+
+// DiffOutput represents the JSON output for diff command
+type DiffOutput struct {
+	Path   string              `json:"path"`
+	Agents []*deploy.AgentDiff `json:"agents"`
+}
+
+// NewDiffCommand creates the diff subcommand
+func NewDiffCommand(vcAgentsDir string) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "diff <location> [agent...]",
+		Short: "Show differences between deployed and source agents",
+		Long: `Show a unified diff between deployed agent files and their source.
+
+Each agent is compared with the source version a sync would deploy, honoring
+constraints in the project's .claude/cami.yaml. Frontmatter and body changes
+are shown separately, so an agent edited without a version bump is caught.
+Whitespace-only differences are ignored.
+
+With no agent names, every agent in the project's .claude/agents, slash
+command in .claude/commands and skill in .claude/skills is compared. Name
+commands as /name and skills as skill:name; a skill's SKILL.md is compared.`,
+		Example: `  cami diff my-app
+  cami diff ~/projects/my-app frontend backend
+  cami diff ~/projects/my-app /review
+  cami diff . --output json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(vcAgentsDir, args[0], args[1:], outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runDiff(vcAgentsDir, location string, names []string, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	projectPath, err := deploy.ResolveProjectPath(cfg, location)
+	if err != nil {
+		return err
+	}
+
+	resolver, err := newResolver(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	diffs, err := deploy.DiffProject(projectPath, names, resolver)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		output := DiffOutput{Path: projectPath, Agents: diffs}
+		if output.Agents == nil {
+			output.Agents = []*deploy.AgentDiff{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	if len(diffs) == 0 {
+		fmt.Println("No agents deployed")
+		return nil
+	}
+
+	for _, d := range diffs {
+		switch {
+		case d.Error != "":
+			fmt.Printf("⚠ %s: %s\n\n", d.Name, d.Error)
+		case !d.Changed():
+			fmt.Printf("✓ %s: matches source\n\n", d.Name)
+		default:
+			fmt.Printf("✗ %s: %s deployed, %s in source\n\n", d.Name, versionLabel(d.DeployedVersion), versionLabel(d.SourceVersion))
+			if d.FrontmatterChanged {
+				fmt.Printf("Frontmatter changes:\n%s\n", d.FrontmatterDiff)
+			}
+			if d.BodyChanged {
+				fmt.Printf("Body changes:\n%s\n", d.BodyDiff)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+// NewLintCommand creates the lint subcommand
+func NewLintCommand(vcAgentsDir string) *cobra.Command {
+	var (
+		outputFormat string
+		failOn       string
+		disable      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [source|path]",
+		Short: "Check agents in sources for problems",
+		Long: `Check the agents, slash commands and skills in a source for problems.
+
+Give a configured source name or a directory path; with neither, every
+configured source is linted. Findings have a severity of error, warning or
+info. Rules:
+
+  load-error          Files load, e.g. their frontmatter is valid YAML
+  missing-metadata    Agents declare a name, version and description
+  invalid-semver      Versions are valid semantic versions
+  name-mismatch       Names match the file name, or a skill's directory name
+  duplicate-name      Names are unique within a source (error) and across
+                      sources (warning: the higher priority source wins)
+  unknown-class       Classes are one of the known agent classes
+  description-length  Descriptions are between 20 and 1024 characters
+  unknown-tool        Tools are ones Claude Code offers, or MCP tools
+  broken-reference    Agents listed under requires: exist in a source
+  empty-body          Agents have instructions after their frontmatter
+  schema              Frontmatter matches the agent schema ('cami schema')
+
+The command exits non-zero when any finding is at least as serious as
+--fail-on, so it can gate CI. Use --output sarif for code scanning.`,
+		Example: `  cami lint
+  cami lint team-agents
+  cami lint ./my-agents --output sarif > cami.sarif
+  cami lint --fail-on warning --disable description-length`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runLint(vcAgentsDir, target, outputFormat, failOn, disable)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text, json or sarif")
+	cmd.Flags().StringVar(&failOn, "fail-on", "error", "Exit non-zero on findings of this severity or worse: error, warning or info")
+	cmd.Flags().StringVar(&disable, "disable", "", "Comma-separated list of rules to skip")
+
+	return cmd
+}
+
+func runLint(vcAgentsDir, target, outputFormat, failOn, disable string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" && outputFormat != "sarif" {
+		return fmt.Errorf("invalid output format: %s (must be 'text', 'json' or 'sarif')", outputFormat)
+	}
+
+	threshold, err := lint.ParseSeverity(failOn)
+	if err != nil {
+		return err
+	}
+
+	rules := lint.DefaultRules()
+	if disable != "" {
+		for _, id := range strings.Split(disable, ",") {
+			id = strings.TrimSpace(id)
+			if lint.FindRule(rules, id) == nil {
+				return fmt.Errorf("unknown rule: %s", id)
+			}
+			for i, rule := range rules {
+				if rule.ID() == id {
+					rules = append(rules[:i], rules[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	set, root, err := lintSet(vcAgentsDir, target)
+	if err != nil {
+		return err
+	}
+
+	report := lint.Run(set, rules)
+
+	switch outputFormat {
+	case "json", "sarif":
+		var output any = report
+		if outputFormat == "sarif" {
+			output = lint.SARIF(report, rules, version, root)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode %s output: %w", strings.ToUpper(outputFormat), err)
+		}
+	default:
+		printLintReport(report, root)
+	}
+
+	// Return non-zero exit code for findings at or above the threshold
+	if report.Failed(threshold) {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// lintSet loads what to lint: a configured source by name, a directory, or
+// every configured source. References may name any agent the configured
+// sources provide. It also returns the root findings are reported relative
+// to, empty when linting several sources.
+func lintSet(vcAgentsDir, target string) (*lint.Set, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	available, _, err := loadAvailableArtifacts(vcAgentsDir)
+	if err != nil {
+		return nil, "", err
+	}
+	set := &lint.Set{Available: make(map[string]bool, len(available))}
+	for _, ag := range available {
+		set.Available[ag.ID()] = true
+	}
+
+	var sources []config.AgentSource
+	switch {
+	case target == "" && len(cfg.AgentSources) == 0:
+		sources = []config.AgentSource{{Name: "agents", Path: vcAgentsDir}}
+	case target == "":
+		sources = cfg.AgentSources
+	default:
+		for _, src := range cfg.AgentSources {
+			if src.Name == target {
+				sources = []config.AgentSource{src}
+			}
+		}
+		if sources == nil {
+			info, err := os.Stat(target)
+			if err != nil || !info.IsDir() {
+				return nil, "", fmt.Errorf("%s is not a configured source or a directory", target)
+			}
+			path, err := filepath.Abs(target)
+			if err != nil {
+				return nil, "", err
+			}
+			sources = []config.AgentSource{{Name: filepath.Base(path), Path: path}}
+		}
+	}
+
+	for _, src := range sources {
+		source, err := lint.LoadSource(src.Name, src.Path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load source %s: %w", src.Name, err)
+		}
+		set.Sources = append(set.Sources, source)
+	}
+
+	root := ""
+	if len(sources) == 1 {
+		root = sources[0].Path
+	}
+	return set, root, nil
+}
+
+// printLintReport prints findings grouped by file, with paths relative to
+// root when there is one
+func printLintReport(report *lint.Report, root string) {
+	if len(report.Findings) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+
+	file := ""
+	for _, f := range report.Findings {
+		if f.File != file {
+			if file != "" {
+				fmt.Println()
+			}
+			file = f.File
+			display := file
+			if rel, err := filepath.Rel(root, file); root != "" && err == nil && !strings.HasPrefix(rel, "..") {
+				display = rel
+			}
+			fmt.Println(display)
+		}
+
+		line := ""
+		if f.Line > 0 {
+			line = fmt.Sprintf("%d", f.Line)
+		}
+		fmt.Printf("  %4s  %-7s  %-18s  %s\n", line, f.Severity, f.Rule, f.Message)
+	}
+
+	fmt.Printf("\nSummary: %d errors, %d warnings, %d info\n", report.Errors, report.Warnings, report.Infos)
+}
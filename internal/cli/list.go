@@ -7,6 +7,7 @@ import (
 
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/resolve"
 	"github.com/spf13/cobra"
 )
 
@@ -21,76 +22,100 @@ type AgentInfo struct {
 
 // ListOutput represents the JSON output for list command
 type ListOutput struct {
-	Count  int         `json:"count"`
-	Agents []AgentInfo `json:"agents"`
+	Count      int               `json:"count"`
+	Agents     []AgentInfo       `json:"agents"`
+	Commands   []AgentInfo       `json:"commands"`              // Slash commands, named /name
+	Skills     []AgentInfo       `json:"skills"`                // Skill directories, named skill:name
+	Shadowed   []agent.Shadowed  `json:"shadowed,omitempty"`    // With --show-shadowed
+	LoadReport *agent.LoadReport `json:"load_report,omitempty"` // With --verbose
 }
 
 // NewListCommand creates the list subcommand
 func NewListCommand(vcAgentsDir string) *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		verbose      bool
+		showShadowed bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
-		Short: "List available agents",
-		Long:  `List all available agents from configured sources.`,
+		Short: "List available agents, slash commands and skills",
+		Long: `List all available agents from configured sources.
+
+Slash commands that sources keep in commands/ or .claude/commands/ are listed
+after the agents as /name, and skills kept in skills/ or .claude/skills/ as
+skill:name. Deploy, diff and remove them by that name.
+
+With --verbose, the list ends with what loading skipped: files that failed to
+load, schema problems, sources that couldn't be read, files .camiignore
+excluded and copies a higher priority source overrides. --show-shadowed
+lists only those overridden copies; 'cami which <agent>' explains one agent.`,
 		Example: `  cami list
+  cami list --verbose
+  cami list --show-shadowed
   cami list --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(vcAgentsDir, outputFormat)
+			return runList(vcAgentsDir, outputFormat, verbose, showShadowed)
 		},
 	}
 
 	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show files that were skipped or failed to load")
+	cmd.Flags().BoolVar(&showShadowed, "show-shadowed", false, "Show copies overridden by a higher priority source")
 
 	return cmd
 }
 
-func runList(vcAgentsDir, outputFormat string) error {
+func runList(vcAgentsDir, outputFormat string, verbose, showShadowed bool) error {
 	// Validate output format
 	if outputFormat != "text" && outputFormat != "json" {
 		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
 	}
 
-	// Load config
-	cfg, err := config.Load()
+	report := &agent.LoadReport{}
+
+	agents, agentReport, err := loadAvailableAgents(vcAgentsDir)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
+	report.Merge(agentReport)
 
-	// If no sources configured, fall back to legacy behavior
-	var agents []*agent.Agent
-	if len(cfg.AgentSources) == 0 {
-		agents, err = agent.LoadAgents(vcAgentsDir)
-		if err != nil {
-			return fmt.Errorf("failed to load agents: %w", err)
-		}
-	} else {
-		// Convert config sources to agent sources
-		var sources []agent.AgentSource
-		for _, src := range cfg.AgentSources {
-			sources = append(sources, agent.AgentSource{
-				Path:     src.Path,
-				Priority: src.Priority,
-			})
-		}
+	commands, commandReport, err := loadAvailableCommands(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+	report.Merge(commandReport)
 
-		// Load from all sources with priority
-		agents, err = agent.LoadAgentsFromSources(sources)
-		if err != nil {
-			return fmt.Errorf("failed to load agents: %w", err)
-		}
+	skills, skillReport, err := loadAvailableSkills(vcAgentsDir)
+	if err != nil {
+		return err
 	}
+	report.Merge(skillReport)
 
-	if len(agents) == 0 {
+	if len(agents) == 0 && len(commands) == 0 && len(skills) == 0 && outputFormat == "text" {
 		fmt.Println("No agents found")
+		if verbose {
+			printLoadReport(report)
+		} else {
+			printLoadProblemsHint(report)
+		}
 		return nil
 	}
 
 	// Prepare output
 	if outputFormat == "json" {
 		output := ListOutput{
-			Count:  len(agents),
-			Agents: make([]AgentInfo, len(agents)),
+			Count:    len(agents),
+			Agents:   make([]AgentInfo, len(agents)),
+			Commands: artifactInfos(commands),
+			Skills:   artifactInfos(skills),
+		}
+		if verbose {
+			output.LoadReport = report
+		}
+		if showShadowed {
+			output.Shadowed = report.Shadowed
 		}
 
 		for i, ag := range agents {
@@ -153,7 +178,228 @@ func runList(vcAgentsDir, outputFormat string) error {
 				fmt.Println()
 			}
 		}
+
+		printArtifacts("Slash Commands", commands)
+		printArtifacts("Skills", skills)
+
+		if verbose {
+			printLoadReport(report)
+		} else {
+			if showShadowed {
+				printShadowed(report.Shadowed)
+			}
+			printLoadProblemsHint(report)
+		}
 	}
 
 	return nil
 }
+
+// printLoadReport prints each section of a load report that has entries
+func printLoadReport(report *agent.LoadReport) {
+	if problems := report.Problems(); len(problems) > 0 {
+		fmt.Printf("Load Problems (%d):\n\n", len(problems))
+		for _, problem := range problems {
+			fmt.Printf("  ✗ %s\n", problem)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Ignored) > 0 {
+		fmt.Printf("Ignored by .camiignore (%d):\n\n", len(report.Ignored))
+		for _, ignored := range report.Ignored {
+			fmt.Printf("  %s\n", ignored.File)
+		}
+		fmt.Println()
+	}
+
+	printShadowed(report.Shadowed)
+}
+
+// printShadowed lists copies overridden by a higher priority source
+func printShadowed(shadowed []agent.Shadowed) {
+	if len(shadowed) == 0 {
+		return
+	}
+
+	fmt.Printf("Shadowed (%d):\n\n", len(shadowed))
+	for _, s := range shadowed {
+		fmt.Printf("  %s from %s (priority %d), overridden by %s (priority %d)\n", s.ID, s.Source, s.Priority, s.By, s.ByPriority)
+		fmt.Printf("    %s\n", s.File)
+	}
+	fmt.Println()
+}
+
+// printLoadProblemsHint points at --verbose when loading skipped files
+func printLoadProblemsHint(report *agent.LoadReport) {
+	if report.HasProblems() {
+		fmt.Fprintf(os.Stderr, "Warning: %d load problem(s) (run 'cami list --verbose' for details)\n", len(report.Problems()))
+	}
+}
+
+// artifactInfos describes commands or skills for JSON output, named by ID
+func artifactInfos(artifacts []*agent.Agent) []AgentInfo {
+	infos := make([]AgentInfo, len(artifacts))
+	for i, ag := range artifacts {
+		infos[i] = AgentInfo{
+			Name:        ag.ID(),
+			Version:     ag.Version,
+			Description: ag.Description,
+			Category:    ag.Category,
+			FilePath:    ag.FilePath,
+		}
+	}
+	return infos
+}
+
+// printArtifacts prints a section of commands or skills, named by ID
+func printArtifacts(title string, artifacts []*agent.Agent) {
+	if len(artifacts) == 0 {
+		return
+	}
+
+	fmt.Printf("%s (%d):\n\n", title, len(artifacts))
+	for _, ag := range artifacts {
+		fmt.Printf("  %s", ag.ID())
+		if ag.Version != "" {
+			fmt.Printf(" (v%s)", ag.Version)
+		}
+		if len(ag.Files) > 0 {
+			fmt.Printf(" [%d supporting files]", len(ag.Files))
+		}
+		fmt.Println()
+		if ag.Description != "" {
+			fmt.Printf("    %s\n", ag.Description)
+		}
+		fmt.Println()
+	}
+}
+
+// loadAvailableAgents loads agents from all configured sources with priority,
+// falling back to the legacy agents directory when no sources are configured.
+// The report says which files were skipped and why.
+func loadAvailableAgents(vcAgentsDir string) ([]*agent.Agent, *agent.LoadReport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(cfg.AgentSources) == 0 {
+		agents, report, err := agent.LoadAgents(vcAgentsDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+		return agents, report, nil
+	}
+
+	agents, report, err := agent.LoadAgentsFromSources(agentSources(cfg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+	}
+	return agents, report, nil
+}
+
+// newResolver returns a version resolver over the configured sources, or over
+// the legacy agents directory when no sources are configured
+func newResolver(vcAgentsDir string) (*resolve.Resolver, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	available, _, err := loadAvailableArtifacts(vcAgentsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := agentSources(cfg)
+	if len(sources) == 0 {
+		sources = []agent.AgentSource{{Path: vcAgentsDir}}
+	}
+
+	return resolve.New(sources, available), nil
+}
+
+// loadAvailableArtifacts loads every agent, slash command and skill, with
+// the combined load report
+func loadAvailableArtifacts(vcAgentsDir string) ([]*agent.Agent, *agent.LoadReport, error) {
+	report := &agent.LoadReport{}
+
+	agents, agentReport, err := loadAvailableAgents(vcAgentsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Merge(agentReport)
+
+	commands, commandReport, err := loadAvailableCommands(vcAgentsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Merge(commandReport)
+
+	skills, skillReport, err := loadAvailableSkills(vcAgentsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Merge(skillReport)
+
+	return append(append(agents, commands...), skills...), report, nil
+}
+
+// loadAvailableCommands loads slash commands the way loadAvailableAgents
+// loads agents
+func loadAvailableCommands(vcAgentsDir string) ([]*agent.Agent, *agent.LoadReport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(cfg.AgentSources) == 0 {
+		commands, report, err := agent.LoadCommands(vcAgentsDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load commands: %w", err)
+		}
+		return commands, report, nil
+	}
+
+	commands, report, err := agent.LoadCommandsFromSources(agentSources(cfg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load commands: %w", err)
+	}
+	return commands, report, nil
+}
+
+// loadAvailableSkills loads skills the way loadAvailableAgents loads agents
+func loadAvailableSkills(vcAgentsDir string) ([]*agent.Agent, *agent.LoadReport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(cfg.AgentSources) == 0 {
+		skills, report, err := agent.LoadSkills(vcAgentsDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load skills: %w", err)
+		}
+		return skills, report, nil
+	}
+
+	skills, report, err := agent.LoadSkillsFromSources(agentSources(cfg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load skills: %w", err)
+	}
+	return skills, report, nil
+}
+
+// agentSources converts configured sources to agent loader sources
+func agentSources(cfg *config.Config) []agent.AgentSource {
+	sources := make([]agent.AgentSource, len(cfg.AgentSources))
+	for i, src := range cfg.AgentSources {
+		sources[i] = agent.AgentSource{
+			Name:     src.Name,
+			Path:     src.Path,
+			Priority: src.Priority,
+		}
+	}
+	return sources
+}
@@ -23,7 +23,8 @@ func NewLocationsCommand() *cobra.Command {
 		Use:   "locations",
 		Short: "List all configured deployment locations",
 		Long: `List all configured deployment locations.
-Locations are stored in your CAMI workspace config.yaml and can be used for deployment targets.`,
+Locations are stored in your CAMI workspace config.yaml and can be used for deployment targets.
+The user scope (~/.claude/agents, named "user") is always listed first.`,
 		Example: `  cami locations
   cami locations --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -116,9 +117,10 @@ func runListLocations(outputFormat string) error {
 	}
 
 	// Prepare output
+	targets := cfg.DeployTargets()
 	output := LocationsOutput{
-		Locations: cfg.Locations,
-		Count:     len(cfg.Locations),
+		Locations: targets,
+		Count:     len(targets),
 	}
 
 	if outputFormat == "json" {
@@ -131,26 +133,28 @@ func runListLocations(outputFormat string) error {
 	}
 
 	// Text output
-	if len(cfg.Locations) == 0 {
-		fmt.Println("No deployment locations configured.")
-		fmt.Println("\nTo add a location:")
-		fmt.Println("  cami location add --name <name> --path <path>")
-		return nil
-	}
-
-	fmt.Printf("Configured Deployment Locations (%d):\n\n", len(cfg.Locations))
+	fmt.Printf("Deployment Locations (%d):\n\n", len(targets))
 
 	// Use tabwriter for aligned columns
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "NAME\tPATH")
 	fmt.Fprintln(w, "----\t----")
 
-	for _, loc := range cfg.Locations {
+	for _, loc := range targets {
+		if loc.Name == config.UserScopeName {
+			fmt.Fprintf(w, "%s\t%s (user scope)\n", loc.Name, filepath.Join(loc.Path, ".claude", "agents"))
+			continue
+		}
 		fmt.Fprintf(w, "%s\t%s\n", loc.Name, loc.Path)
 	}
 
 	w.Flush()
 
+	if len(cfg.Locations) == 0 {
+		fmt.Println("\nNo project locations configured. To add one:")
+		fmt.Println("  cami location add --name <name> --path <path>")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
+	"github.com/lando/cami/internal/lock"
+	"github.com/lando/cami/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// InstallOutput represents the JSON output for install command
+type InstallOutput struct {
+	Success   bool         `json:"success"`
+	Path      string       `json:"path"`
+	Installed []ResultItem `json:"installed"`
+	Warning   string       `json:"warning,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// NewLockCommand creates the lock subcommand
+func NewLockCommand() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "lock [location]",
+		Short: "Write a lock file pinning deployed agents",
+		Long: `Write .claude/cami-lock.yaml for a project.
+
+The lock records every agent in the project's manifest with its source,
+source remote, resolved git commit, file path within the source and content
+hash. Commit it alongside the project so teammates and CI can recreate the
+exact same agents with 'cami install --frozen'.
+
+Agents must come from git sources, and the file at the recorded commit must
+match what was deployed. The location defaults to the current directory.`,
+		Example: `  cami lock
+  cami lock my-app
+  cami lock ~/projects/my-app --output json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := "."
+			if len(args) > 0 {
+				location = args[0]
+			}
+			return runLock(location, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// NewInstallCommand creates the install subcommand
+func NewInstallCommand() *cobra.Command {
+	var (
+		frozen       bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install [location]",
+		Short: "Install agents from a project's lock file",
+		Long: `Recreate .claude/agents/ from .claude/cami-lock.yaml.
+
+Each agent is fetched from its locked remote at its locked commit and
+checked against its locked content hash, so no CAMI workspace or configured
+sources are needed. Any mismatch fails the install.
+
+With --frozen, a lock file that no longer satisfies .claude/cami.yaml is an
+error instead of a warning. Use it in CI. The location defaults to the
+current directory.`,
+		Example: `  cami install
+  cami install --frozen
+  cami install ~/projects/my-app --output json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := "."
+			if len(args) > 0 {
+				location = args[0]
+			}
+			return runInstall(location, frozen, outputFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&frozen, "frozen", false, "Fail if the lock file is out of date with the project spec")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runLock(location, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	projectPath, err := deploy.ResolveProjectPath(cfg, location)
+	if err != nil {
+		return err
+	}
+
+	l, err := lock.Generate(projectPath, cfg.AgentSources)
+	if err != nil {
+		return err
+	}
+
+	if err := lock.Write(projectPath, l); err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(l); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("✓ Locked %d agents in %s\n\n", len(l.Agents), lock.Filename)
+	for _, entry := range l.Agents {
+		commit := entry.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		fmt.Printf("  %s %s @ %s (%s)\n", entry.Name, versionLabel(entry.Version), commit, entry.Remote)
+	}
+
+	return nil
+}
+
+func runInstall(location string, frozen bool, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	projectPath, err := deploy.ResolveProjectPath(cfg, location)
+	if err != nil {
+		return err
+	}
+
+	l, err := lock.Read(projectPath)
+	if err != nil {
+		return fmt.Errorf("%w (run 'cami lock' first)", err)
+	}
+
+	output := InstallOutput{
+		Success:   true,
+		Path:      projectPath,
+		Installed: []ResultItem{},
+	}
+
+	if spec.Exists(projectPath) {
+		projectSpec, err := spec.Read(projectPath)
+		if err != nil {
+			return err
+		}
+		if err := l.CheckSpec(projectSpec); err != nil {
+			if frozen {
+				return err
+			}
+			output.Warning = err.Error()
+		}
+	}
+
+	results, err := deploy.InstallLocked(projectPath, l)
+	for _, result := range results {
+		status := "success"
+		if !result.Success {
+			status = "failed"
+			output.Success = false
+		}
+		output.Installed = append(output.Installed, ResultItem{
+			Agent:   result.Agent.ID(),
+			Status:  status,
+			Message: result.Message,
+		})
+	}
+	if err != nil {
+		output.Success = false
+		output.Error = err.Error()
+	}
+
+	// Output results
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+	} else {
+		printInstallText(output)
+	}
+
+	// Return non-zero exit code if install was not successful
+	if !output.Success {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func printInstallText(output InstallOutput) {
+	if output.Warning != "" {
+		fmt.Printf("⚠ %s\n\n", output.Warning)
+	}
+
+	fmt.Printf("Install Results (%s):\n\n", output.Path)
+	for _, result := range output.Installed {
+		if result.Status == "success" {
+			fmt.Printf("  ✓ %s\n", result.Agent)
+		} else {
+			fmt.Printf("  ✗ %s: %s\n", result.Agent, result.Message)
+		}
+	}
+
+	if output.Error != "" {
+		fmt.Printf("\n✗ %s\n", output.Error)
+		return
+	}
+
+	fmt.Printf("\n✓ Installed %d agents from %s\n", len(output.Installed), lock.Filename)
+}
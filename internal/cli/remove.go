@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
+	"github.com/lando/cami/internal/docs"
+	"github.com/spf13/cobra"
+)
+
+// RemoveOutput represents the JSON output for remove command
+type RemoveOutput struct {
+	Success bool         `json:"success"`
+	Removed []string     `json:"removed"`
+	Failed  []string     `json:"failed"`
+	Results []ResultItem `json:"results"`
+}
+
+// NewRemoveCommand creates the remove subcommand
+func NewRemoveCommand(vcAgentsDir string) *cobra.Command {
+	var (
+		agentNames   string
+		location     string
+		orphaned     bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove deployed agents from a project",
+		Long: `Remove one or more agents from a project's .claude/agents directory.
+Slash commands are removed from .claude/commands by their /name, and skills
+from .claude/skills, with their whole directory, by skill:name.
+
+Removed agents are dropped from the project and central manifests, and the
+CLAUDE.md managed section is refreshed. MCP servers CAMI added to .mcp.json
+for them are removed unless another deployed agent still requires them.
+Removing an agent that another deployed agent lists under requires: prints a
+warning naming the agents that still depend on it. With --orphaned, every agent the
+project manifest tracks that no configured source provides any more is removed.`,
+		Example: `  cami remove --agents frontend,backend --location ~/projects/my-app
+  cami remove -a /review -l ~/projects/my-app
+  cami remove -l ~/projects/my-app --orphaned
+  cami remove -a frontend -l user
+  cami remove -a frontend -l ~/projects/my-app --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(vcAgentsDir, agentNames, location, orphaned, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&agentNames, "agents", "a", "", "Comma-separated list of agents to remove")
+	cmd.Flags().StringVarP(&location, "location", "l", "", "Target project path, or user for ~/.claude/agents (required)")
+	cmd.Flags().BoolVar(&orphaned, "orphaned", false, "Remove tracked agents no source provides")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	cmd.MarkFlagRequired("location")
+	cmd.MarkFlagsOneRequired("agents", "orphaned")
+	cmd.MarkFlagsMutuallyExclusive("agents", "orphaned")
+
+	return cmd
+}
+
+func runRemove(vcAgentsDir, agentNames, location string, orphaned bool, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	location, err := config.ExpandUserScope(location)
+	if err != nil {
+		return err
+	}
+
+	// Validate target path
+	if err := deploy.ValidateTargetPath(location); err != nil {
+		return fmt.Errorf("invalid location: %w", err)
+	}
+
+	var names []string
+	if orphaned {
+		available, report, err := loadAvailableArtifacts(vcAgentsDir)
+		if err != nil {
+			return err
+		}
+		// An agent that failed to load would look orphaned and be removed
+		if len(report.SourceErrors) > 0 || len(report.Errors) > 0 {
+			return fmt.Errorf("some agents failed to load, so orphans can't be told apart (run 'cami list --verbose' for details)")
+		}
+		names, err = deploy.OrphanedAgents(location, available)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, name := range strings.Split(agentNames, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	output := RemoveOutput{
+		Success: true,
+		Removed: []string{},
+		Failed:  []string{},
+		Results: []ResultItem{},
+	}
+
+	if len(names) > 0 {
+		results, err := deploy.UndeployAgents(location, names)
+		if err != nil {
+			return fmt.Errorf("remove failed: %w", err)
+		}
+
+		for _, result := range results {
+			item := ResultItem{
+				Agent:   result.Name,
+				Status:  "success",
+				Message: result.Message,
+			}
+			if result.Success {
+				output.Removed = append(output.Removed, result.Name)
+				if len(result.Dependents) > 0 {
+					item.Status = "warning"
+				}
+			} else {
+				item.Status = "failed"
+				output.Failed = append(output.Failed, result.Name)
+				output.Success = false
+			}
+			output.Results = append(output.Results, item)
+		}
+
+		if len(output.Removed) > 0 {
+			if err := docs.RefreshCLAUDEmd(location, "Deployed Agents"); err != nil {
+				return fmt.Errorf("failed to update CLAUDE.md: %w", err)
+			}
+		}
+	}
+
+	// Output results
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+	} else if len(output.Results) == 0 {
+		fmt.Println("No orphaned agents to remove")
+	} else {
+		fmt.Printf("Removal Results:\n\n")
+		for _, item := range output.Results {
+			statusIcon := "✓"
+			if item.Status == "failed" {
+				statusIcon = "✗"
+			} else if item.Status == "warning" {
+				statusIcon = "⚠"
+			}
+			fmt.Printf("  %s %s: %s\n", statusIcon, item.Agent, item.Message)
+		}
+
+		fmt.Printf("\nSummary:\n")
+		fmt.Printf("  Removed: %d\n", len(output.Removed))
+		if len(output.Failed) > 0 {
+			fmt.Printf("  Failed: %d\n", len(output.Failed))
+		}
+	}
+
+	// Return non-zero exit code if removal was not successful
+	if !output.Success {
+		os.Exit(1)
+	}
+
+	return nil
+}
@@ -29,9 +29,20 @@ When run without arguments, launches the interactive TUI.`,
 	rootCmd.AddCommand(NewInitCommand())
 	rootCmd.AddCommand(NewSourceCommand())
 	rootCmd.AddCommand(NewDeployCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewRemoveCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewSyncCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewApplyCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewLockCommand())
+	rootCmd.AddCommand(NewInstallCommand())
+	rootCmd.AddCommand(NewDiffCommand(vcAgentsDir))
 	rootCmd.AddCommand(NewUpdateDocsCommand())
 	rootCmd.AddCommand(NewListCommand(vcAgentsDir))
-	rootCmd.AddCommand(NewScanCommand())
+	rootCmd.AddCommand(NewWhichCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewSearchCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewBundleCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewLintCommand(vcAgentsDir))
+	rootCmd.AddCommand(NewSchemaCommand())
+	rootCmd.AddCommand(NewScanCommand(vcAgentsDir))
 	rootCmd.AddCommand(NewDiscoverCommand())
 	rootCmd.AddCommand(NewLocationsCommand())
 	rootCmd.AddCommand(NewLocationCommand())
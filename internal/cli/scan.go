@@ -4,20 +4,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/discovery"
 	"github.com/lando/cami/internal/docs"
 	"github.com/spf13/cobra"
 )
 
 // ScanOutput represents the JSON output for scan command
 type ScanOutput struct {
-	Location string      `json:"location"`
-	Count    int         `json:"count"`
-	Agents   []AgentInfo `json:"agents"`
+	Location string          `json:"location"`
+	Scope    string          `json:"scope"` // "user" or "project"
+	Count    int             `json:"count"`
+	Agents   []ScanAgentInfo `json:"agents"`
+}
+
+// ScanAgentInfo represents a deployed agent and its drift status
+type ScanAgentInfo struct {
+	Name             string   `json:"name"`
+	Version          string   `json:"version"`
+	Description      string   `json:"description"`
+	AvailableVersion string   `json:"available_version,omitempty"`
+	Status           string   `json:"status"`
+	OverridesUser    bool     `json:"overrides_user,omitempty"`
+	ShadowedBy       []string `json:"shadowed_by,omitempty"`
 }
 
 // NewScanCommand creates the scan subcommand
-func NewScanCommand() *cobra.Command {
+func NewScanCommand(vcAgentsDir string) *cobra.Command {
 	var (
 		location     string
 		outputFormat string
@@ -26,15 +42,26 @@ func NewScanCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "scan",
 		Short: "Scan deployed agents at a location",
-		Long:  `Scan a project location and list all deployed agents in the .claude/agents directory.`,
+		Long: `Scan a project location and list all deployed agents in the .claude/agents directory.
+
+Each agent is compared with its source. In projects with a CAMI manifest the
+recorded content hashes tell whether the deployed file, the source or both
+changed since deployment (locally-modified, source-modified, both-modified),
+and agents no source provides (orphaned) or the manifest doesn't know about
+(untracked) are flagged. Other projects are compared by version.
+
+Use --location user to scan the user scope, ~/.claude/agents. Project agents
+that override a user-scope agent of the same name are marked, and user-scope
+agents list the configured locations that shadow them.`,
 		Example: `  cami scan --location ~/projects/my-app
+  cami scan -l user
   cami scan -l ~/projects/my-app --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runScan(location, outputFormat)
+			return runScan(vcAgentsDir, location, outputFormat)
 		},
 	}
 
-	cmd.Flags().StringVarP(&location, "location", "l", "", "Target project path (required)")
+	cmd.Flags().StringVarP(&location, "location", "l", "", "Target project path, or user for ~/.claude/agents (required)")
 	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
 
 	cmd.MarkFlagRequired("location")
@@ -42,12 +69,17 @@ func NewScanCommand() *cobra.Command {
 	return cmd
 }
 
-func runScan(location, outputFormat string) error {
+func runScan(vcAgentsDir, location, outputFormat string) error {
 	// Validate output format
 	if outputFormat != "text" && outputFormat != "json" {
 		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
 	}
 
+	location, err := config.ExpandUserScope(location)
+	if err != nil {
+		return err
+	}
+
 	// Scan deployed agents
 	agents, err := docs.ScanDeployedAgentsInfo(location)
 	if err != nil {
@@ -59,20 +91,49 @@ func runScan(location, outputFormat string) error {
 		return nil
 	}
 
+	// Compare against sources. Scanning without them would show every
+	// tracked agent as orphaned.
+	availableAgents, report, err := loadAvailableAgents(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+	locationStatus, err := scanLocationStatus(location, availableAgents)
+	if err != nil {
+		return fmt.Errorf("failed to scan agents: %w", err)
+	}
+	statuses := make(map[string]*discovery.AgentStatus)
+	for _, status := range locationStatus.AgentStatuses {
+		statuses[status.Agent.Name] = status
+	}
+
+	infos := make([]ScanAgentInfo, len(agents))
+	for i, ag := range agents {
+		infos[i] = ScanAgentInfo{
+			Name:        ag.Name,
+			Version:     ag.Version,
+			Description: ag.Description,
+			Status:      string(discovery.StatusUnknown),
+		}
+		if status, ok := statuses[ag.Name]; ok {
+			infos[i].AvailableVersion = status.AvailableVersion
+			infos[i].Status = string(status.Status)
+			infos[i].OverridesUser = status.OverridesUser
+			infos[i].ShadowedBy = status.ShadowedBy
+		}
+	}
+
+	scope := "project"
+	if config.IsUserScope(location) {
+		scope = config.UserScopeName
+	}
+
 	// Prepare output
 	if outputFormat == "json" {
 		output := ScanOutput{
 			Location: location,
-			Count:    len(agents),
-			Agents:   make([]AgentInfo, len(agents)),
-		}
-
-		for i, ag := range agents {
-			output.Agents[i] = AgentInfo{
-				Name:        ag.Name,
-				Version:     ag.Version,
-				Description: ag.Description,
-			}
+			Scope:    scope,
+			Count:    len(infos),
+			Agents:   infos,
 		}
 
 		encoder := json.NewEncoder(os.Stdout)
@@ -82,19 +143,60 @@ func runScan(location, outputFormat string) error {
 		}
 	} else {
 		// Text output
-		fmt.Printf("Deployed Agents at %s (%d):\n\n", location, len(agents))
-		for _, ag := range agents {
-			fmt.Printf("  %s", ag.Name)
-			if ag.Version != "" {
-				fmt.Printf(" (v%s)", ag.Version)
+		if scope == config.UserScopeName {
+			fmt.Printf("Deployed Agents in the user scope at %s (%d):\n\n", location, len(infos))
+		} else {
+			fmt.Printf("Deployed Agents at %s (%d):\n\n", location, len(infos))
+		}
+		for _, info := range infos {
+			status := discovery.DeploymentStatus(info.Status)
+			fmt.Printf("  %s %s", discovery.GetStatusSymbol(status), info.Name)
+			if info.Version != "" {
+				fmt.Printf(" (v%s)", info.Version)
+			}
+			fmt.Printf(" - %s", info.Status)
+			if status == discovery.StatusSourceModified || status == discovery.StatusBothModified || status == discovery.StatusUpdateAvailable {
+				if info.AvailableVersion != info.Version {
+					fmt.Printf(", source at %s", versionLabel(info.AvailableVersion))
+				}
+			}
+			if info.OverridesUser {
+				fmt.Print(", overrides user-scope agent")
+			}
+			if len(info.ShadowedBy) > 0 {
+				fmt.Printf(", shadowed in %s", strings.Join(info.ShadowedBy, ", "))
 			}
 			fmt.Println()
-			if ag.Description != "" {
-				fmt.Printf("    %s\n", ag.Description)
+			if info.Description != "" {
+				fmt.Printf("    %s\n", info.Description)
 			}
 			fmt.Println()
 		}
+		printLoadProblemsHint(report)
 	}
 
 	return nil
 }
+
+// scanLocationStatus scans one location. The user scope is scanned together
+// with the configured locations so its agents know which projects shadow them.
+func scanLocationStatus(location string, availableAgents []*agent.Agent) (*discovery.LocationStatus, error) {
+	if !config.IsUserScope(location) {
+		return discovery.ScanLocation(&config.DeployLocation{Path: location}, availableAgents)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	result, err := discovery.ScanAllLocations(cfg.DeployTargets(), availableAgents)
+	if err != nil {
+		return nil, err
+	}
+	for _, locStatus := range result.LocationStatuses {
+		if config.IsUserScope(locStatus.Location.Path) {
+			return locStatus, nil
+		}
+	}
+	return discovery.ScanLocation(&config.DeployLocation{Path: location}, availableAgents)
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCommand creates the schema subcommand
+func NewSchemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for agent frontmatter",
+		Long: fmt.Sprintf(`Print the JSON Schema (version %d) CAMI validates agent frontmatter
+against: the CAMI keys (name, version, description, class, specialty,
+requires, mcpServers) plus the keys Claude Code reads (tools, model, color).
+
+Point an editor's YAML schema settings at the output to get completion and
+validation while writing agents. Problems CAMI finds when loading agents
+are reported by 'cami lint'.`, agent.SchemaVersion),
+		Example: `  cami schema
+  cami schema > agent.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stdout.Write(agent.Schema()); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/search"
+	"github.com/spf13/cobra"
+)
+
+// NewSearchCommand creates the search subcommand
+func NewSearchCommand(vcAgentsDir string) *cobra.Command {
+	var (
+		outputFormat string
+		filter       search.Filter
+		limit        int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search available agents",
+		Long: `Search available agents by name, specialty, description and body text.
+
+Results are ranked by relevance, with matches in the name counting most and
+matches in the body least, and each shows where the query matched. Every
+word of the query must match. Filter by class, category, source or a version
+constraint; with filters alone, every agent they allow is listed.`,
+		Example: `  cami search react
+  cami search "api design" --class technology-implementer
+  cami search testing --source team --version ">=2.0.0"
+  cami search --category core --output json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) == 1 {
+				query = args[0]
+			}
+			return runSearch(vcAgentsDir, query, filter, limit, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.Class, "class", "", "Only agents of this class")
+	cmd.Flags().StringVar(&filter.Category, "category", "", "Only agents in this category")
+	cmd.Flags().StringVar(&filter.Source, "source", "", "Only agents from this source")
+	cmd.Flags().StringVar(&filter.Version, "version", "", "Only agents matching this version constraint (e.g. ^2, >=1.5.0)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", search.DefaultLimit, "Maximum number of results")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runSearch(vcAgentsDir, query string, filter search.Filter, limit int, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	if query == "" && filter == (search.Filter{}) {
+		return fmt.Errorf("nothing to search for: give a query or a filter (run 'cami list' to see every agent)")
+	}
+
+	agents, report, err := loadAvailableAgents(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	results, err := search.New(agents).Search(query, filter, limit)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	if results.Total == 0 {
+		fmt.Println("No agents found")
+		printLoadProblemsHint(report)
+		return nil
+	}
+
+	found := fmt.Sprintf("Found %d agents", results.Total)
+	if results.Total == 1 {
+		found = "Found 1 agent"
+	}
+	if len(results.Results) < results.Total {
+		fmt.Printf("%s, showing the top %d:\n\n", found, len(results.Results))
+	} else {
+		fmt.Printf("%s:\n\n", found)
+	}
+
+	for _, r := range results.Results {
+		version := ""
+		if r.Version != "" {
+			version = " v" + r.Version
+		}
+		fmt.Printf("  • %s%s (%s)\n", r.ID, version, sourceName(r.Source))
+		if r.Snippet != "" {
+			fmt.Printf("    %s\n", r.Snippet)
+		}
+		fmt.Println()
+	}
+
+	for _, facet := range []string{"class", "category", "source"} {
+		if line := search.FacetLine(results.Facets[facet]); line != "" {
+			fmt.Printf("%-9s %s\n", facet+":", line)
+		}
+	}
+
+	printLoadProblemsHint(report)
+	return nil
+}
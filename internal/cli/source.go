@@ -9,6 +9,7 @@ import (
 
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/git"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +39,7 @@ when agent names conflict (1 = highest priority, 100 = lowest).`,
 func NewSourceAddCommand() *cobra.Command {
 	var priority int
 	var name string
+	var ref string
 
 	cmd := &cobra.Command{
 		Use:   "add <git-url>",
@@ -45,11 +47,13 @@ func NewSourceAddCommand() *cobra.Command {
 		Long: `Add a new agent source by cloning a Git repository.
 
 The repository will be cloned to sources/<name>/ and added to your configuration.
+Use --ref to pin the source to a branch, tag or commit SHA.
 
 Examples:
   cami source add git@github.com:company/agents.git
   cami source add git@github.com:yourorg/team-agents.git --name official --priority 10
-  cami source add git@github.com:mycompany/custom-agents.git --priority 50`,
+  cami source add git@github.com:mycompany/custom-agents.git --priority 50
+  cami source add git@github.com:company/agents.git --ref v3.2.0`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			url := args[0]
@@ -64,12 +68,13 @@ Examples:
 				priority = 50
 			}
 
-			return SourceAddCommand(url, name, priority)
+			return SourceAddCommand(url, name, priority, ref)
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Name for the source (derived from URL if not specified)")
 	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Priority (lower = higher precedence, default: 50)")
+	cmd.Flags().StringVar(&ref, "ref", "", "Branch, tag or commit SHA to pin the source to")
 
 	return cmd
 }
@@ -95,7 +100,8 @@ func NewSourceUpdateCommand() *cobra.Command {
 		Short: "Update agent sources",
 		Long: `Update (git pull) agent sources.
 
-If no name is specified, updates all sources with git remotes.`,
+If no name is specified, updates all sources with git remotes. Sources pinned
+to a branch follow that branch; sources pinned to a tag or commit stay put.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sourceName := ""
@@ -141,8 +147,8 @@ the directory. Use 'rm -rf sources/<name>' to delete the files.`,
 	return cmd
 }
 
-// SourceAddCommand adds a new agent source
-func SourceAddCommand(url, name string, priority int) error {
+// SourceAddCommand adds a new agent source, optionally pinned to a ref
+func SourceAddCommand(url, name string, priority int, ref string) error {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -179,6 +185,13 @@ func SourceAddCommand(url, name string, priority int) error {
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
 
+	if ref != "" {
+		if err := git.Checkout(targetPath, ref); err != nil {
+			_ = os.RemoveAll(targetPath)
+			return fmt.Errorf("failed to check out %s: %w", ref, err)
+		}
+	}
+
 	// Count agents
 	agents, err := agent.LoadAgentsFromPath(targetPath)
 	if err != nil {
@@ -194,6 +207,7 @@ func SourceAddCommand(url, name string, priority int) error {
 		Git: &config.GitConfig{
 			Enabled: true,
 			Remote:  url,
+			Ref:     ref,
 		},
 	}
 
@@ -206,6 +220,9 @@ func SourceAddCommand(url, name string, priority int) error {
 	}
 
 	fmt.Printf("\n✓ Cloned %s to sources/%s\n", name, name)
+	if ref != "" {
+		fmt.Printf("✓ Pinned to %s\n", ref)
+	}
 	fmt.Printf("✓ Added source with priority %d\n", priority)
 	if agents != nil {
 		fmt.Printf("✓ Found %d agents\n", len(agents))
@@ -245,6 +262,9 @@ func SourceListCommand() error {
 
 		if source.Git != nil && source.Git.Enabled {
 			fmt.Printf("    Git: %s\n", source.Git.Remote)
+			if source.Git.Ref != "" {
+				fmt.Printf("    Ref: %s\n", source.Git.Ref)
+			}
 		}
 
 		fmt.Println()
@@ -274,21 +294,22 @@ func SourceUpdateCommand(sourceName string) error {
 			continue
 		}
 
-		fmt.Printf("Updating %s...\n", source.Name)
-
-		cmd := exec.Command("git", "-C", source.Path, "pull")
-		output, err := cmd.CombinedOutput()
+		if source.Git.Ref != "" {
+			fmt.Printf("Updating %s (%s)...\n", source.Name, source.Git.Ref)
+		} else {
+			fmt.Printf("Updating %s...\n", source.Name)
+		}
 
+		moved, err := git.Update(source.Path, source.Git.Ref)
 		if err != nil {
 			fmt.Printf("  ✗ Failed: %v\n", err)
 			continue
 		}
 
-		outputStr := string(output)
-		if strings.Contains(outputStr, "Already up to date") {
-			fmt.Printf("  ✓ Up to date\n")
-		} else {
+		if moved {
 			fmt.Printf("  ✓ Updated\n")
+		} else {
+			fmt.Printf("  ✓ Up to date\n")
 		}
 
 		updated = append(updated, source.Name)
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
+	"github.com/spf13/cobra"
+)
+
+// SyncOutput represents the JSON output for sync command
+type SyncOutput struct {
+	Success  bool          `json:"success"`
+	DryRun   bool          `json:"dry_run"`
+	Projects []SyncProject `json:"projects"`
+}
+
+// SyncProject represents the sync outcome for a single project
+type SyncProject struct {
+	Path    string             `json:"path"`
+	Items   []*deploy.SyncItem `json:"items"`
+	Updated []string           `json:"updated"`
+	Failed  []string           `json:"failed"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// NewSyncCommand creates the sync subcommand
+func NewSyncCommand(vcAgentsDir string) *cobra.Command {
+	var (
+		dryRun       bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync [location...]",
+		Short: "Update deployed agents in tracked projects",
+		Long: `Bring tracked projects up to date with their agent sources.
+
+Every agent recorded in a project's manifest is compared against the
+configured sources and redeployed if the source has changed. Version
+constraints in the project's .claude/cami.yaml are honored. Agents marked
+as custom overrides, agents edited locally since deployment, and agents no
+source provides anymore are skipped.
+
+With no arguments, all configured locations and projects recorded in the
+central manifest are synced.`,
+		Example: `  cami sync
+  cami sync my-app
+  cami sync ~/projects/my-app --dry-run
+  cami sync --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(vcAgentsDir, args, dryRun, outputFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without deploying")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runSync(vcAgentsDir string, locations []string, dryRun bool, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Resolve projects to sync
+	var projects []string
+	if len(locations) == 0 {
+		projects = deploy.TrackedProjects(cfg)
+	} else {
+		for _, loc := range locations {
+			path, err := deploy.ResolveProjectPath(cfg, loc)
+			if err != nil {
+				return err
+			}
+			projects = append(projects, path)
+		}
+	}
+
+	if len(projects) == 0 {
+		if outputFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(SyncOutput{Success: true, DryRun: dryRun, Projects: []SyncProject{}}); err != nil {
+				return fmt.Errorf("failed to encode JSON output: %w", err)
+			}
+			return nil
+		}
+		fmt.Println("No tracked projects found")
+		return nil
+	}
+
+	resolver, err := newResolver(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	output := SyncOutput{
+		Success:  true,
+		DryRun:   dryRun,
+		Projects: []SyncProject{},
+	}
+
+	for _, projectPath := range projects {
+		project := SyncProject{
+			Path:    projectPath,
+			Updated: []string{},
+			Failed:  []string{},
+		}
+
+		plan, err := deploy.PlanSync(projectPath, resolver)
+		if err != nil {
+			project.Error = err.Error()
+			output.Success = false
+			output.Projects = append(output.Projects, project)
+			continue
+		}
+		project.Items = plan.Items
+
+		if !dryRun {
+			results, err := deploy.ApplySync(plan)
+			for _, result := range results {
+				if result.Success {
+					project.Updated = append(project.Updated, result.Agent.ID())
+				} else {
+					project.Failed = append(project.Failed, result.Agent.ID())
+					output.Success = false
+				}
+			}
+			if err != nil {
+				project.Error = err.Error()
+				output.Success = false
+			}
+		}
+
+		output.Projects = append(output.Projects, project)
+	}
+
+	// Output results
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+	} else {
+		printSyncText(output)
+	}
+
+	// Return non-zero exit code if sync was not successful
+	if !output.Success {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func printSyncText(output SyncOutput) {
+	if output.DryRun {
+		fmt.Printf("Sync Plan (dry run):\n\n")
+	} else {
+		fmt.Printf("Sync Results:\n\n")
+	}
+
+	totalUpdates := 0
+	for _, project := range output.Projects {
+		fmt.Printf("%s\n", project.Path)
+
+		if project.Error != "" && len(project.Items) == 0 {
+			fmt.Printf("  ✗ %s\n\n", project.Error)
+			continue
+		}
+
+		if len(project.Items) == 0 {
+			fmt.Printf("  No agents tracked in manifest\n\n")
+			continue
+		}
+
+		failed := make(map[string]bool)
+		for _, name := range project.Failed {
+			failed[name] = true
+		}
+
+		for _, item := range project.Items {
+			switch item.Action {
+			case deploy.SyncUpdate:
+				totalUpdates++
+				icon := "✓"
+				if failed[item.Name] {
+					icon = "✗"
+				}
+				fmt.Printf("  %s %s: %s → %s\n", icon, item.Name, versionLabel(item.FromVersion), versionLabel(item.ToVersion))
+			case deploy.SyncUpToDate:
+				fmt.Printf("  ✓ %s: up to date\n", item.Name)
+			default:
+				fmt.Printf("  ⚠ %s: skipped (%s)\n", item.Name, item.Reason)
+			}
+		}
+
+		if project.Error != "" {
+			fmt.Printf("  ✗ %s\n", project.Error)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Summary:\n")
+	fmt.Printf("  Projects: %d\n", len(output.Projects))
+	if output.DryRun {
+		fmt.Printf("  Would update: %d\n", totalUpdates)
+	} else {
+		fmt.Printf("  Updated: %d\n", totalUpdates)
+	}
+}
+
+// versionLabel formats a version for display, tolerating missing versions
+func versionLabel(version string) string {
+	if version == "" {
+		return "unversioned"
+	}
+	return "v" + version
+}
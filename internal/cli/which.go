@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewWhichCommand creates the which subcommand
+func NewWhichCommand(vcAgentsDir string) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "which <agent>",
+		Short: "Show which source an agent comes from",
+		Long: `Show every source that provides an agent, with its version, path and
+priority, and which copy is used and why.
+
+The copy from the source with the lowest priority number wins; the others
+are shadowed. Name slash commands as /name and skills as skill:name.`,
+		Example: `  cami which frontend
+  cami which /review
+  cami which frontend --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWhich(vcAgentsDir, args[0], outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runWhich(vcAgentsDir, id, outputFormat string) error {
+	// Validate output format
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	available, report, err := loadAvailableArtifacts(vcAgentsDir)
+	if err != nil {
+		return err
+	}
+
+	resolution := agent.Explain(id, available, report, agentSources(cfg))
+	if resolution == nil {
+		return fmt.Errorf("no source provides %s (run 'cami list --verbose' to see files that failed to load)", id)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(resolution); err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("%s:\n\n", resolution.ID)
+	for _, p := range resolution.Providers {
+		status := "shadowed"
+		if p.Wins {
+			status = "✓ used"
+		}
+		version := p.Version
+		if version == "" {
+			version = "unversioned"
+		} else {
+			version = "v" + version
+		}
+		fmt.Printf("  %-10s %s (priority %d) %s\n", status, sourceName(p.Source), p.Priority, version)
+		fmt.Printf("             %s\n", p.File)
+	}
+	fmt.Printf("\n%s wins: %s\n", sourceName(resolution.Providers[0].Source), resolution.Reason)
+
+	return nil
+}
+
+// sourceName names a source for display; agents loaded without configured
+// sources come from the legacy agents directory
+func sourceName(name string) string {
+	if name == "" {
+		return "agents directory"
+	}
+	return name
+}
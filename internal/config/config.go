@@ -32,6 +32,7 @@ type AgentSource struct {
 type GitConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	Remote  string `yaml:"remote,omitempty"`
+	Ref     string `yaml:"ref,omitempty"` // Branch, tag or commit to track; empty follows the cloned branch
 }
 
 // DeployLocation represents a deployment target
@@ -43,6 +44,9 @@ type DeployLocation struct {
 const (
 	configDirName  = "cami-workspace"
 	configFileName = "config.yaml"
+
+	// UserScopeName is the reserved location name of the user scope
+	UserScopeName = "user"
 )
 
 // GetConfigDir returns the path to the config directory
@@ -70,6 +74,39 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(configDir, configFileName), nil
 }
 
+// UserScope returns the user-level deploy location. Agents deployed there go
+// to ~/.claude/agents, which Claude Code loads in every project; an agent of
+// the same name in a project's .claude/agents takes precedence over it.
+func UserScope() (DeployLocation, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return DeployLocation{}, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return DeployLocation{Name: UserScopeName, Path: homeDir}, nil
+}
+
+// IsUserScope reports whether path is the user scope
+func IsUserScope(path string) bool {
+	user, err := UserScope()
+	if err != nil {
+		return false
+	}
+	return filepath.Clean(path) == filepath.Clean(user.Path)
+}
+
+// ExpandUserScope turns the location name "user" into the user scope's path;
+// any other location is returned as is
+func ExpandUserScope(location string) (string, error) {
+	if location != UserScopeName {
+		return location, nil
+	}
+	user, err := UserScope()
+	if err != nil {
+		return "", err
+	}
+	return user.Path, nil
+}
+
 // Load reads the configuration from disk
 func Load() (*Config, error) {
 	configPath, err := GetConfigPath()
@@ -205,8 +242,22 @@ func (c *Config) GetAgentSource(name string) (*AgentSource, error) {
 	return nil, fmt.Errorf("source with name %q not found", name)
 }
 
+// DeployTargets returns every location agents can be deployed to: the user
+// scope followed by the configured locations
+func (c *Config) DeployTargets() []DeployLocation {
+	var targets []DeployLocation
+	if user, err := UserScope(); err == nil {
+		targets = append(targets, user)
+	}
+	return append(targets, c.Locations...)
+}
+
 // AddDeployLocation adds a new deployment location
 func (c *Config) AddDeployLocation(name, path string) error {
+	if name == UserScopeName {
+		return fmt.Errorf("location name %q is reserved for the user scope", name)
+	}
+
 	// Check if location already exists
 	for _, loc := range c.Locations {
 		if loc.Name == name {
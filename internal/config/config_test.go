@@ -240,6 +240,58 @@ func TestAddDeployLocation(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "does not exist")
 	})
+
+	t.Run("error on the reserved user scope name", func(t *testing.T) {
+		cfg := &Config{
+			Version:   "1",
+			Locations: []DeployLocation{},
+		}
+
+		err := cfg.AddDeployLocation(UserScopeName, t.TempDir())
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "reserved")
+		assert.Empty(t, cfg.Locations)
+	})
+}
+
+func TestUserScope(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Run("user scope is the home directory", func(t *testing.T) {
+		user, err := UserScope()
+		require.NoError(t, err)
+		assert.Equal(t, UserScopeName, user.Name)
+		assert.Equal(t, home, user.Path)
+
+		assert.True(t, IsUserScope(home))
+		assert.True(t, IsUserScope(home+"/"))
+		assert.False(t, IsUserScope(t.TempDir()))
+	})
+
+	t.Run("expand user scope", func(t *testing.T) {
+		path, err := ExpandUserScope(UserScopeName)
+		require.NoError(t, err)
+		assert.Equal(t, home, path)
+
+		path, err = ExpandUserScope("/some/project")
+		require.NoError(t, err)
+		assert.Equal(t, "/some/project", path)
+	})
+
+	t.Run("deploy targets start with the user scope", func(t *testing.T) {
+		cfg := &Config{
+			Version:   "1",
+			Locations: []DeployLocation{{Name: "app", Path: "/projects/app"}},
+		}
+
+		targets := cfg.DeployTargets()
+		require.Len(t, targets, 2)
+		assert.Equal(t, UserScopeName, targets[0].Name)
+		assert.Equal(t, "app", targets[1].Name)
+		assert.Len(t, cfg.Locations, 1)
+	})
 }
 
 func TestRemoveDeployLocationByName(t *testing.T) {
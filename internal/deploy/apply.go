@@ -0,0 +1,217 @@
+package deploy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+)
+
+// ApplyAction describes what an apply will do with one agent
+type ApplyAction string
+
+const (
+	ApplyAdd          ApplyAction = "add"           // In the spec, not deployed
+	ApplyUpdate       ApplyAction = "update"        // Deployed content differs from the resolved source
+	ApplyRemove       ApplyAction = "remove"        // Tracked but no longer in the spec
+	ApplyUnchanged    ApplyAction = "unchanged"     // Deployed content matches the resolved source
+	ApplySkipCustom   ApplyAction = "skip-custom"   // Marked as a custom override
+	ApplySkipModified ApplyAction = "skip-modified" // Deployed file edited locally or not deployed by CAMI
+)
+
+// ApplyItem is the planned apply action for one agent
+type ApplyItem struct {
+	Name        string       `json:"name"`
+	Action      ApplyAction  `json:"action"`
+	FromVersion string       `json:"from_version,omitempty"`
+	ToVersion   string       `json:"to_version,omitempty"`
+	Reason      string       `json:"reason,omitempty"`
+	Agent       *agent.Agent `json:"-"` // Resolved source agent for adds and updates
+}
+
+// ApplyPlan is the set of actions that converge a project to its spec
+type ApplyPlan struct {
+	ProjectPath string       `json:"project_path"`
+	Items       []*ApplyItem `json:"items"`
+}
+
+// Changes returns the items that add, update or remove an agent
+func (p *ApplyPlan) Changes() []*ApplyItem {
+	var changes []*ApplyItem
+	for _, item := range p.Items {
+		switch item.Action {
+		case ApplyAdd, ApplyUpdate, ApplyRemove:
+			changes = append(changes, item)
+		}
+	}
+	return changes
+}
+
+// PlanApply compares a project's deployed agents with its spec
+// (.claude/cami.yaml). Every agent in the spec, and every agent they require,
+// is resolved and added or updated to match; agents the manifest tracks that
+// are neither listed nor required are removed. Custom overrides and files edited since deployment are left
+// alone, as are agents CAMI didn't deploy.
+func PlanApply(projectPath string, resolver *resolve.Resolver) (*ApplyPlan, error) {
+	projectSpec, err := spec.Read(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	projectManifest, err := LoadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	deployed, err := deployedAgentFiles(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requesting names alone resolves each to the constraint and source the
+	// spec declares
+	requests := make([]string, len(projectSpec.Agents))
+	for i, req := range projectSpec.Agents {
+		requests[i] = req.Name
+	}
+	resolved, err := resolver.ResolveProject(projectPath, requests)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", spec.Filename, err)
+	}
+
+	plan := &ApplyPlan{ProjectPath: projectPath}
+	wanted := make(map[string]bool, len(resolved))
+
+	for _, sourceAgent := range resolved {
+		id := sourceAgent.ID()
+		wanted[id] = true
+
+		item := &ApplyItem{
+			Name:      id,
+			ToVersion: sourceAgent.Version,
+			Agent:     sourceAgent,
+		}
+		plan.Items = append(plan.Items, item)
+
+		deployedPath, ok := deployed[id]
+		if !ok {
+			item.Action = ApplyAdd
+			continue
+		}
+
+		entry := projectManifest.FindAgent(id)
+		if entry != nil {
+			item.FromVersion = entry.Version
+		}
+
+		deployedHash, err := manifest.CalculateArtifactHash(sourceAgent.ArtifactKind(), deployedPath)
+		if err != nil {
+			return nil, err
+		}
+		if deployedHash == manifest.HashArtifact(sourceAgent) {
+			item.Action = ApplyUnchanged
+			continue
+		}
+
+		if reason := keepReason(entry, deployedHash); reason != "" {
+			item.Action = keepAction(entry)
+			item.Reason = reason
+			continue
+		}
+
+		item.Action = ApplyUpdate
+	}
+
+	for _, entry := range projectManifest.Agents {
+		id := entry.ID()
+		if wanted[id] {
+			continue
+		}
+
+		item := &ApplyItem{
+			Name:        id,
+			Action:      ApplyRemove,
+			FromVersion: entry.Version,
+		}
+		plan.Items = append(plan.Items, item)
+
+		if deployedPath, ok := deployed[id]; ok {
+			deployedHash, err := manifest.CalculateArtifactHash(entry.Kind, deployedPath)
+			if err != nil {
+				return nil, err
+			}
+			if reason := keepReason(&entry, deployedHash); reason != "" {
+				item.Action = keepAction(&entry)
+				item.Reason = reason
+			}
+		}
+	}
+
+	sort.Slice(plan.Items, func(i, j int) bool {
+		return plan.Items[i].Name < plan.Items[j].Name
+	})
+
+	return plan, nil
+}
+
+// keepReason explains why a deployed file must not be overwritten or
+// removed, or returns "" if apply may change it
+func keepReason(entry *manifest.DeployedAgent, deployedHash string) string {
+	switch {
+	case entry == nil:
+		return "deployed file is not tracked by CAMI"
+	case entry.CustomOverride:
+		return "marked as custom override"
+	case entry.ContentHash != "" && deployedHash != entry.ContentHash:
+		return "deployed file has local changes"
+	default:
+		return ""
+	}
+}
+
+// keepAction is the skip action matching keepReason
+func keepAction(entry *manifest.DeployedAgent) ApplyAction {
+	if entry != nil && entry.CustomOverride {
+		return ApplySkipCustom
+	}
+	return ApplySkipModified
+}
+
+// Apply carries out a plan: added and updated agents are deployed and
+// recorded in the manifests, and removed agents are undeployed. Sources
+// supply the priority recorded for each agent.
+func Apply(plan *ApplyPlan, sources []config.AgentSource) ([]*Result, []*RemoveResult, error) {
+	var agents []*agent.Agent
+	var removals []string
+	for _, item := range plan.Changes() {
+		if item.Action == ApplyRemove {
+			removals = append(removals, item.Name)
+		} else {
+			agents = append(agents, item.Agent)
+		}
+	}
+
+	var results []*Result
+	if len(agents) > 0 {
+		var err error
+		results, err = DeployAndRecord(agents, plan.ProjectPath, true, sources)
+		if err != nil {
+			return results, nil, err
+		}
+	}
+
+	var removed []*RemoveResult
+	if len(removals) > 0 {
+		var err error
+		removed, err = UndeployAgents(plan.ProjectPath, removals)
+		if err != nil {
+			return results, removed, err
+		}
+	}
+
+	return results, removed, nil
+}
@@ -0,0 +1,159 @@
+package deploy
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findApplyItem(plan *ApplyPlan, name string) *ApplyItem {
+	for _, item := range plan.Items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func writeSpec(t *testing.T, projectPath string, requirements ...string) {
+	t.Helper()
+
+	s := &spec.Spec{}
+	for _, r := range requirements {
+		req, err := spec.ParseRequirement(r)
+		require.NoError(t, err)
+		s.Set(req)
+	}
+	require.NoError(t, spec.Write(projectPath, s))
+}
+
+func TestPlanApply(t *testing.T) {
+	available := []*agent.Agent{
+		createTestAgent("frontend", "1.1.0"),
+		createTestAgent("backend", "1.0.0"),
+		createTestAgent("qa", "1.0.0"),
+	}
+
+	t.Run("adds, updates and removes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"), createTestAgent("backend", "1.0.0"), createTestAgent("legacy", "1.0.0"))
+		writeSpec(t, tmpDir, "frontend", "backend", "qa")
+
+		plan, err := PlanApply(tmpDir, resolve.New(nil, available))
+		require.NoError(t, err)
+		require.Len(t, plan.Items, 4)
+
+		assert.Equal(t, ApplyUpdate, findApplyItem(plan, "frontend").Action)
+		assert.Equal(t, "1.0.0", findApplyItem(plan, "frontend").FromVersion)
+		assert.Equal(t, "1.1.0", findApplyItem(plan, "frontend").ToVersion)
+		assert.Equal(t, ApplyUnchanged, findApplyItem(plan, "backend").Action)
+		assert.Equal(t, ApplyAdd, findApplyItem(plan, "qa").Action)
+		assert.Equal(t, ApplyRemove, findApplyItem(plan, "legacy").Action)
+		assert.Len(t, plan.Changes(), 3)
+	})
+
+	t.Run("leaves local edits and custom overrides alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		legacy := createTestAgent("legacy", "1.0.0")
+		deployTracked(t, tmpDir, frontend, legacy)
+		writeSpec(t, tmpDir, "frontend")
+
+		require.NoError(t, os.WriteFile(AgentPath(tmpDir, frontend), []byte(frontend.FullContent()+"\nLocal notes."), 0644))
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		pm.FindAgent("legacy").CustomOverride = true
+		require.NoError(t, manifest.WriteProjectManifest(tmpDir, pm))
+
+		plan, err := PlanApply(tmpDir, resolve.New(nil, available))
+		require.NoError(t, err)
+
+		assert.Equal(t, ApplySkipModified, findApplyItem(plan, "frontend").Action)
+		assert.Equal(t, ApplySkipCustom, findApplyItem(plan, "legacy").Action)
+		assert.Empty(t, plan.Changes())
+	})
+
+	t.Run("agent files CAMI didn't deploy", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_, err := DeployAgent(createTestAgent("frontend", "0.9.0"), tmpDir, false)
+		require.NoError(t, err)
+		writeSpec(t, tmpDir, "frontend")
+
+		plan, err := PlanApply(tmpDir, resolve.New(nil, available))
+		require.NoError(t, err)
+		assert.Equal(t, ApplySkipModified, findApplyItem(plan, "frontend").Action)
+	})
+
+	t.Run("adds and keeps required agents", func(t *testing.T) {
+		orchestrator, err := agent.ParseAgent([]byte("---\nname: orchestrator\nversion: 1.0.0\nrequires: frontend\n---\nDelegate.\n"), "/fake/path/orchestrator.md")
+		require.NoError(t, err)
+		frontend, err := agent.ParseAgent([]byte("---\nname: frontend\nversion: 1.1.0\nrequires:\n  - backend@^1\n---\nBuild.\n"), "/fake/path/frontend.md")
+		require.NoError(t, err)
+		withRequires := []*agent.Agent{orchestrator, frontend, createTestAgent("backend", "1.0.0")}
+
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("backend", "1.0.0"))
+		writeSpec(t, tmpDir, "orchestrator")
+
+		plan, err := PlanApply(tmpDir, resolve.New(nil, withRequires))
+		require.NoError(t, err)
+		require.Len(t, plan.Items, 3)
+
+		assert.Equal(t, ApplyAdd, findApplyItem(plan, "orchestrator").Action)
+		assert.Equal(t, ApplyAdd, findApplyItem(plan, "frontend").Action)
+		assert.Equal(t, ApplyUnchanged, findApplyItem(plan, "backend").Action, "a required agent is not removed")
+	})
+
+	t.Run("unresolvable requirement", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeSpec(t, tmpDir, "frontend@^2.0")
+
+		_, err := PlanApply(tmpDir, resolve.New(nil, available))
+		assert.Error(t, err)
+	})
+
+	t.Run("missing spec", func(t *testing.T) {
+		_, err := PlanApply(t.TempDir(), resolve.New(nil, available))
+		assert.Error(t, err)
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tmpDir := t.TempDir()
+	legacy := createTestAgent("legacy", "1.0.0")
+	deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"), legacy)
+	writeSpec(t, tmpDir, "frontend", "qa")
+
+	available := []*agent.Agent{createTestAgent("frontend", "1.1.0"), createTestAgent("qa", "1.0.0")}
+	plan, err := PlanApply(tmpDir, resolve.New(nil, available))
+	require.NoError(t, err)
+
+	results, removed, err := Apply(plan, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	require.Len(t, removed, 1)
+	assert.True(t, removed[0].Success)
+
+	_, err = os.Stat(AgentPath(tmpDir, legacy))
+	assert.True(t, os.IsNotExist(err))
+
+	pm, err := manifest.ReadProjectManifest(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, pm.Agents, 2)
+	assert.Equal(t, "1.1.0", pm.FindAgent("frontend").Version)
+	assert.NotNil(t, pm.FindAgent("qa"))
+
+	// A second plan has nothing left to do
+	plan, err = PlanApply(tmpDir, resolve.New(nil, available))
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes())
+}
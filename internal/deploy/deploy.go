@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
 )
 
 // Result represents the result of a deployment operation
@@ -14,63 +16,204 @@ type Result struct {
 	Success  bool
 	Message  string
 	Conflict bool
+	Merged   bool // Source changes were merged into local edits, possibly with conflict markers
 }
 
 // DeployAgent deploys a single agent to a target location
 func DeployAgent(ag *agent.Agent, targetPath string, overwrite bool) (*Result, error) {
-	// Ensure .claude/agents directory exists
-	agentsDir := filepath.Join(targetPath, ".claude", "agents")
-	if err := os.MkdirAll(agentsDir, 0755); err != nil {
-		return &Result{
-			Agent:   ag,
-			Success: false,
-			Message: fmt.Sprintf("Failed to create agents directory: %v", err),
-		}, nil
+	results, err := DeployAgents([]*agent.Agent{ag}, targetPath, overwrite)
+	if err != nil {
+		return nil, err
 	}
+	return results[0], nil
+}
+
+// DeployAgents deploys multiple agents to a target location as one
+// transaction: every file is staged first and then moved into place, and if
+// any of them fails, all of them are rolled back. Agents skipped because
+// their file exists are reported as conflicts and don't fail the rest.
+func DeployAgents(agents []*agent.Agent, targetPath string, overwrite bool) ([]*Result, error) {
+	return deployTransaction(agents, targetPath, overwrite, nil), nil
+}
 
-	// Determine target file path
-	targetFile := filepath.Join(agentsDir, ag.FileName())
+// deployTransaction deploys agents through a transaction. If update is set,
+// it is handed the project manifest and the deployment results to record;
+// the manifest is then committed with the agent files and recorded in the
+// central manifest. Any failure rolls back the whole deployment, which the
+// results reflect.
+func deployTransaction(agents []*agent.Agent, targetPath string, overwrite bool, update func(*manifest.ProjectManifest, []*Result) error) []*Result {
+	results := make([]*Result, 0, len(agents))
 
-	// Check for conflicts
-	if _, err := os.Stat(targetFile); err == nil && !overwrite {
-		return &Result{
-			Agent:    ag,
-			Success:  false,
-			Conflict: true,
-			Message:  "File already exists",
-		}, nil
+	tx, err := beginTransaction(targetPath)
+	if err != nil {
+		for _, ag := range agents {
+			results = append(results, &Result{
+				Agent:   ag,
+				Success: false,
+				Message: fmt.Sprintf("Failed to create agents directory: %v", err),
+			})
+		}
+		return results
 	}
+	defer tx.close()
 
-	// Write agent file
-	content := ag.FullContent()
-	if err := os.WriteFile(targetFile, []byte(content), 0644); err != nil {
-		return &Result{
+	var failure error
+	for _, ag := range agents {
+		// A skill's name is its directory; never let one reach outside
+		// .claude/skills or cover other skills
+		if ag.ArtifactKind() == agent.KindSkill {
+			if err := agent.ValidateSkillName(ag.Name); err != nil {
+				results = append(results, &Result{Agent: ag, Success: false, Message: err.Error()})
+				failure = err
+				continue
+			}
+		}
+
+		targetFile := AgentPath(targetPath, ag)
+
+		// Check for conflicts
+		if _, err := os.Stat(targetFile); err == nil && !overwrite {
+			results = append(results, &Result{
+				Agent:    ag,
+				Success:  false,
+				Conflict: true,
+				Message:  "File already exists",
+			})
+			continue
+		}
+
+		result := &Result{
 			Agent:   ag,
-			Success: false,
-			Message: fmt.Sprintf("Failed to write file: %v", err),
-		}, nil
+			Success: true,
+			Message: "Deployed successfully",
+		}
+		results = append(results, result)
+
+		if failure != nil {
+			continue
+		}
+		if err := stageArtifact(tx, targetFile, ag); err != nil {
+			result.Success = false
+			result.Message = fmt.Sprintf("Failed to write file: %v", err)
+			failure = fmt.Errorf("failed to write %s: %w", ag.Name, err)
+		}
+	}
+
+	var projectManifest *manifest.ProjectManifest
+	if failure == nil && update != nil {
+		projectManifest, failure = stageManifest(tx, targetPath, results, update)
+	}
+
+	if failure == nil {
+		failure = tx.commit()
 	}
 
-	return &Result{
-		Agent:   ag,
-		Success: true,
-		Message: "Deployed successfully",
-	}, nil
+	if failure == nil && update != nil {
+		if err := manifest.UpdateCentralDeployment(targetPath, projectManifest); err != nil {
+			failure = fmt.Errorf("failed to update central manifest: %w", err)
+			if rbErr := tx.rollback(); rbErr != nil {
+				failure = fmt.Errorf("%w (rollback failed: %v)", failure, rbErr)
+			}
+		}
+	}
+
+	if failure != nil {
+		for _, result := range results {
+			if result.Success {
+				result.Success = false
+				result.Message = fmt.Sprintf("Rolled back: %v", failure)
+			}
+		}
+	}
+
+	return results
 }
 
-// DeployAgents deploys multiple agents to a target location
-func DeployAgents(agents []*agent.Agent, targetPath string, overwrite bool) ([]*Result, error) {
-	var results []*Result
+// stageArtifact stages the files deploying an agent writes: its one file, or
+// a skill's whole directory. Files a skill's new version no longer has are
+// removed from its deployed directory.
+func stageArtifact(tx *transaction, targetFile string, ag *agent.Agent) error {
+	files := artifactFiles(targetFile, ag)
+	for _, path := range sortedPaths(files) {
+		if err := tx.stage(path, files[path]); err != nil {
+			return err
+		}
+	}
 
-	for _, ag := range agents {
-		result, err := DeployAgent(ag, targetPath, overwrite)
+	if ag.ArtifactKind() != agent.KindSkill {
+		return nil
+	}
+
+	return filepath.Walk(filepath.Dir(targetFile), func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
 		if err != nil {
-			return results, err
+			return err
 		}
-		results = append(results, result)
+		if _, ok := files[path]; !ok && !info.IsDir() {
+			tx.remove(path)
+		}
+		return nil
+	})
+}
+
+// artifactFiles maps each file deploying an agent writes to its content
+func artifactFiles(targetFile string, ag *agent.Agent) map[string][]byte {
+	dir := filepath.Dir(targetFile)
+
+	files := make(map[string][]byte)
+	for rel, data := range ag.Tree() {
+		files[filepath.Join(dir, filepath.FromSlash(rel))] = data
+	}
+	return files
+}
+
+// sortedPaths returns the paths of files in order
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// stageManifest lets update record results in the project manifest and
+// stages the updated manifest in the transaction, along with the project's
+// .mcp.json if the deployed agents change the MCP servers it needs
+func stageManifest(tx *transaction, projectPath string, results []*Result, update func(*manifest.ProjectManifest, []*Result) error) (*manifest.ProjectManifest, error) {
+	projectManifest, err := LoadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := managedMCPServers(projectManifest)
+	if err := update(projectManifest, results); err != nil {
+		return nil, err
+	}
+
+	mcp, err := planMCPServers(projectPath, projectManifest, deployedAgents(results), managed)
+	if err != nil {
+		return nil, err
+	}
+	if mcp.content != nil {
+		if err := tx.stage(filepath.Join(projectPath, mcpConfigFile), mcp.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", mcpConfigFile, err)
+		}
+	}
+	noteMCPServers(results, mcp)
+
+	data, err := manifest.MarshalProjectManifest(projectManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.stage(filepath.Join(projectPath, manifest.ProjectManifestFilename), data); err != nil {
+		return nil, fmt.Errorf("failed to write project manifest: %w", err)
 	}
 
-	return results, nil
+	return projectManifest, nil
 }
 
 // ValidateTargetPath ensures the target path is valid for deployment
@@ -95,11 +238,9 @@ func ValidateTargetPath(path string) error {
 // CheckConflicts checks for existing agent files that would conflict
 func CheckConflicts(agents []*agent.Agent, targetPath string) map[string]bool {
 	conflicts := make(map[string]bool)
-	agentsDir := filepath.Join(targetPath, ".claude", "agents")
 
 	for _, ag := range agents {
-		targetFile := filepath.Join(agentsDir, ag.FileName())
-		if _, err := os.Stat(targetFile); err == nil {
+		if _, err := os.Stat(AgentPath(targetPath, ag)); err == nil {
 			conflicts[ag.Name] = true
 		}
 	}
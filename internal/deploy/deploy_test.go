@@ -21,6 +21,15 @@ func createTestAgent(name, version string) *agent.Agent {
 	}
 }
 
+func createTestSkill(name, version string, files map[string]string) *agent.Agent {
+	skill, _ := agent.ParseSkill([]byte("---\nname: "+name+"\nversion: "+version+"\ndescription: Test skill\n---\nUse the scripts.\n"), "/fake/path/skills/"+name+"/SKILL.md")
+	skill.Files = make(map[string][]byte)
+	for path, content := range files {
+		skill.Files[path] = []byte(content)
+	}
+	return skill
+}
+
 func TestValidateTargetPath(t *testing.T) {
 	t.Run("valid directory", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -130,6 +139,26 @@ func TestDeployAgent(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, string(content), "version: 2.0.0")
 	})
+
+	t.Run("preserves frontmatter keys from the source file", func(t *testing.T) {
+		sourceDir := t.TempDir()
+		targetDir := t.TempDir()
+
+		source := "---\nname: restricted\nversion: 1.0.0\ndescription: Read-only agent\ntools: Read, Grep\nmodel: haiku\ncolor: green\n---\n\n# Restricted\n"
+		sourcePath := filepath.Join(sourceDir, "restricted.md")
+		require.NoError(t, os.WriteFile(sourcePath, []byte(source), 0644))
+
+		ag, err := agent.LoadAgent(sourcePath)
+		require.NoError(t, err)
+
+		result, err := DeployAgent(ag, targetDir, false)
+		require.NoError(t, err)
+		require.True(t, result.Success)
+
+		deployed, err := os.ReadFile(filepath.Join(targetDir, ".claude", "agents", "restricted.md"))
+		require.NoError(t, err)
+		assert.Equal(t, source, string(deployed))
+	})
 }
 
 func TestDeployAgents(t *testing.T) {
@@ -194,6 +223,30 @@ func TestDeployAgents(t *testing.T) {
 		assert.Len(t, results, 0)
 	})
 
+	t.Run("skills named outside their own directory are refused", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		project := filepath.Join(tmpDir, "project")
+		require.NoError(t, os.MkdirAll(project, 0755))
+
+		pdf := createTestSkill("pdf", "1.0.0", map[string]string{"scripts/extract.py": "print('extract')\n"})
+		_, err := DeployAgents([]*agent.Agent{pdf}, project, false)
+		require.NoError(t, err)
+
+		for _, name := range []string{".", "..", "../../../x", "nested/x"} {
+			skill := &agent.Agent{Name: name, Kind: agent.KindSkill, FilePath: "/fake/path/skills/x/SKILL.md", Content: "Body\n"}
+
+			results, err := DeployAgents([]*agent.Agent{skill}, project, true)
+			require.NoError(t, err)
+			assert.False(t, results[0].Success, name)
+			assert.Contains(t, results[0].Message, "invalid skill name", name)
+		}
+
+		assert.FileExists(t, filepath.Join(project, ".claude", "skills", "pdf", agent.SkillFile))
+		assert.FileExists(t, filepath.Join(project, ".claude", "skills", "pdf", "scripts", "extract.py"))
+		assert.NoFileExists(t, filepath.Join(tmpDir, "x", agent.SkillFile))
+		assert.NoFileExists(t, filepath.Join(project, ".claude", "skills", agent.SkillFile))
+	})
+
 	t.Run("deploy with overwrite", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
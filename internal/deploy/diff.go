@@ -0,0 +1,248 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/diff"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+)
+
+// AgentDiff compares a deployed agent file with the source version a sync
+// would deploy. Frontmatter and body changes are reported separately; both
+// diffs use line numbers of the whole file, so together they form one
+// unified diff.
+type AgentDiff struct {
+	Name               string `json:"name"`
+	DeployedPath       string `json:"deployed_path,omitempty"`
+	SourcePath         string `json:"source_path,omitempty"`
+	DeployedVersion    string `json:"deployed_version,omitempty"`
+	SourceVersion      string `json:"source_version,omitempty"`
+	FrontmatterChanged bool   `json:"frontmatter_changed"`
+	BodyChanged        bool   `json:"body_changed"`
+	FrontmatterDiff    string `json:"frontmatter_diff,omitempty"`
+	BodyDiff           string `json:"body_diff,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// Changed reports whether the deployed file differs from its source
+func (d *AgentDiff) Changed() bool {
+	return d.FrontmatterChanged || d.BodyChanged
+}
+
+// DiffProject diffs agents deployed to a project against the source versions
+// the resolver picks for them, honoring the project spec. Commands are named
+// /name. With no names, every agent in .claude/agents and every command in
+// .claude/commands is diffed. Agents that are not deployed or
+// have no source are reported through AgentDiff.Error.
+func DiffProject(projectPath string, names []string, resolver *resolve.Resolver) ([]*AgentDiff, error) {
+	projectSpec := &spec.Spec{}
+	if spec.Exists(projectPath) {
+		var err error
+		if projectSpec, err = spec.Read(projectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	deployed, err := deployedAgentFiles(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range deployed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var diffs []*AgentDiff
+	for _, name := range names {
+		d := &AgentDiff{Name: name}
+		diffs = append(diffs, d)
+
+		deployedPath, ok := deployed[name]
+		if !ok {
+			d.Error = "not deployed"
+			continue
+		}
+		d.DeployedPath = deployedPath
+
+		req := spec.Requirement{Name: name}
+		if pinned := projectSpec.Find(name); pinned != nil {
+			req = *pinned
+		}
+
+		sourceAgent, err := resolver.Resolve(req)
+		if errors.Is(err, resolve.ErrNotFound) {
+			d.Error = "no configured source provides this agent"
+			continue
+		}
+		if err != nil {
+			d.Error = err.Error()
+			continue
+		}
+
+		full, err := DiffAgent(deployedPath, sourceAgent)
+		if err != nil {
+			d.Error = err.Error()
+			continue
+		}
+		*d = *full
+	}
+
+	return diffs, nil
+}
+
+// DiffAgent diffs a deployed agent file against a source agent. Whether the
+// frontmatter and body changed is decided by their normalized hashes, the
+// same way the manifest tracks them, so whitespace-only edits don't count.
+// For a skill, its SKILL.md is diffed.
+func DiffAgent(deployedPath string, source *agent.Agent) (*AgentDiff, error) {
+	data, err := os.ReadFile(deployedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployed agent: %w", err)
+	}
+
+	d := &AgentDiff{
+		Name:          source.ID(),
+		DeployedPath:  deployedPath,
+		SourcePath:    source.FilePath,
+		SourceVersion: source.Version,
+	}
+	if deployedAgent, err := agent.Parse(source.ArtifactKind(), data, deployedPath); err == nil {
+		d.DeployedVersion = deployedAgent.Version
+	}
+
+	sourceContent := source.FullContent()
+	if manifest.HashContent(data) == manifest.HashContent([]byte(sourceContent)) {
+		return d, nil
+	}
+
+	deployedFrontmatter, deployedBody := splitAgentContent(string(data))
+	sourceFrontmatter, sourceBody := splitAgentContent(sourceContent)
+
+	deployedMeta, errA := manifest.HashMetadata(data)
+	sourceMeta, errB := manifest.HashMetadata([]byte(sourceContent))
+	if errA == nil && errB == nil {
+		d.FrontmatterChanged = deployedMeta != sourceMeta
+	} else {
+		d.FrontmatterChanged = manifest.HashContent([]byte(deployedFrontmatter)) != manifest.HashContent([]byte(sourceFrontmatter))
+	}
+	d.BodyChanged = manifest.HashContent([]byte(deployedBody)) != manifest.HashContent([]byte(sourceBody))
+
+	fromName := deployedPath
+	toName := source.FilePath
+	if source.Ref != "" {
+		toName += " @ " + source.Ref
+	}
+
+	deployedLines := diff.SplitLines(deployedFrontmatter)
+	sourceLines := diff.SplitLines(sourceFrontmatter)
+
+	if d.FrontmatterChanged {
+		d.FrontmatterDiff = diff.Format(fromName, toName, diff.Hunks(deployedLines, sourceLines, 3))
+	}
+
+	if d.BodyChanged {
+		hunks := diff.Hunks(diff.SplitLines(deployedBody), diff.SplitLines(sourceBody), 3)
+		// Number body lines from the top of the file
+		for i := range hunks {
+			hunks[i].OldStart += len(deployedLines)
+			hunks[i].NewStart += len(sourceLines)
+		}
+		d.BodyDiff = diff.Format(fromName, toName, hunks)
+	}
+
+	return d, nil
+}
+
+// splitAgentContent splits content into frontmatter and body, treating
+// content without frontmatter as all body
+func splitAgentContent(content string) (frontmatter, body string) {
+	frontmatter, body, err := agent.SplitFrontmatter(content)
+	if err != nil {
+		return "", content
+	}
+	return frontmatter, body
+}
+
+// deployedAgentFiles maps the agents, commands and skills deployed to a
+// project to their files, keyed by name for agents, /name for commands and
+// skill:name for skills, whose file is their SKILL.md. Files that don't parse
+// are keyed by file name.
+func deployedAgentFiles(projectPath string) (map[string]string, error) {
+	files, err := deployedSkillFiles(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kind := range []agent.Kind{agent.KindAgent, agent.KindCommand} {
+		dir := filepath.Join(projectPath, ".claude", kind.Dir())
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s directory: %w", kind.Dir(), err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			name := strings.TrimSuffix(entry.Name(), ".md")
+			if ag, err := agent.Load(kind, path); err == nil && ag.Name != "" {
+				name = ag.Name
+			}
+			files[agent.ID(kind, name)] = path
+		}
+	}
+
+	return files, nil
+}
+
+// deployedSkillFiles maps the skills deployed to a project's .claude/skills
+// to their SKILL.md, keyed by skill:name. Skills are named after their
+// directory unless their SKILL.md names them.
+func deployedSkillFiles(projectPath string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	dir := filepath.Join(projectPath, ".claude", agent.KindSkill.Dir())
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skills directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name(), agent.SkillFile)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		name := entry.Name()
+		if skill, err := agent.ParseSkill(data, path); err == nil {
+			name = skill.Name
+		}
+		files[agent.ID(agent.KindSkill, name)] = path
+	}
+
+	return files, nil
+}
@@ -0,0 +1,101 @@
+package deploy
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAgent(t *testing.T) {
+	t.Run("identical agent has no changes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, ag)
+
+		d, err := DiffAgent(AgentPath(tmpDir, ag), ag)
+		require.NoError(t, err)
+		assert.False(t, d.Changed())
+		assert.Empty(t, d.FrontmatterDiff)
+		assert.Empty(t, d.BodyDiff)
+		assert.Equal(t, "1.0.0", d.DeployedVersion)
+	})
+
+	t.Run("body edited without a version bump", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		source := createTestAgent("frontend", "1.0.0")
+		source.Content = "# Test Content\n\nThis is updated content."
+
+		d, err := DiffAgent(AgentPath(tmpDir, source), source)
+		require.NoError(t, err)
+		assert.True(t, d.Changed())
+		assert.False(t, d.FrontmatterChanged)
+		assert.True(t, d.BodyChanged)
+		assert.Empty(t, d.FrontmatterDiff)
+		assert.Contains(t, d.BodyDiff, "-This is test content.")
+		assert.Contains(t, d.BodyDiff, "+This is updated content.")
+		// Body hunks are numbered from the top of the file, past the frontmatter
+		assert.NotContains(t, d.BodyDiff, "@@ -1,")
+	})
+
+	t.Run("frontmatter change", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		source := createTestAgent("frontend", "1.1.0")
+
+		d, err := DiffAgent(AgentPath(tmpDir, source), source)
+		require.NoError(t, err)
+		assert.True(t, d.FrontmatterChanged)
+		assert.False(t, d.BodyChanged)
+		assert.Contains(t, d.FrontmatterDiff, "-version: 1.0.0")
+		assert.Contains(t, d.FrontmatterDiff, "+version: 1.1.0")
+		assert.Equal(t, "1.0.0", d.DeployedVersion)
+		assert.Equal(t, "1.1.0", d.SourceVersion)
+	})
+
+	t.Run("whitespace-only edits are ignored", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, ag)
+
+		path := AgentPath(tmpDir, ag)
+		require.NoError(t, os.WriteFile(path, []byte(ag.FullContent()+"\n\n\n"), 0644))
+
+		d, err := DiffAgent(path, ag)
+		require.NoError(t, err)
+		assert.False(t, d.Changed())
+	})
+}
+
+func TestDiffProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"), createTestAgent("backend", "1.0.0"))
+
+	available := []*agent.Agent{createTestAgent("frontend", "1.1.0")}
+
+	t.Run("all deployed agents", func(t *testing.T) {
+		diffs, err := DiffProject(tmpDir, nil, resolve.New(nil, available))
+		require.NoError(t, err)
+		require.Len(t, diffs, 2)
+
+		assert.Equal(t, "backend", diffs[0].Name)
+		assert.Equal(t, "no configured source provides this agent", diffs[0].Error)
+		assert.Equal(t, "frontend", diffs[1].Name)
+		assert.True(t, diffs[1].FrontmatterChanged)
+	})
+
+	t.Run("named agents", func(t *testing.T) {
+		diffs, err := DiffProject(tmpDir, []string{"frontend", "ghost"}, resolve.New(nil, available))
+		require.NoError(t, err)
+		require.Len(t, diffs, 2)
+
+		assert.Empty(t, diffs[0].Error)
+		assert.Equal(t, "not deployed", diffs[1].Error)
+	})
+}
@@ -0,0 +1,44 @@
+package deploy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lando/cami/internal/lock"
+	"github.com/lando/cami/internal/manifest"
+)
+
+// InstallLocked recreates a project's agents from its lock file: every agent
+// is fetched from its locked remote at its locked commit, verified against
+// its locked hash and deployed, overwriting what is there, in one
+// transaction with the project manifest, which records the locked commits.
+func InstallLocked(projectPath string, l *lock.Lock) ([]*Result, error) {
+	agents, err := lock.Fetch(l)
+	if err != nil {
+		return nil, err
+	}
+
+	results := deployTransaction(agents, projectPath, true, func(projectManifest *manifest.ProjectManifest, results []*Result) error {
+		now := time.Now()
+		for _, result := range results {
+			if !result.Success {
+				continue
+			}
+
+			entry := upsertEntry(projectManifest, result.Agent)
+			if err := recordDeployed(entry, result.Agent, now); err != nil {
+				return err
+			}
+			entry.Commit = l.Find(result.Agent.ID()).Commit
+		}
+		return nil
+	})
+
+	for _, result := range results {
+		if !result.Success {
+			return results, fmt.Errorf("failed to install %s: %s", result.Agent.ID(), result.Message)
+		}
+	}
+
+	return results, lock.Verify(projectPath, l)
+}
@@ -0,0 +1,93 @@
+package deploy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/lock"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallLocked(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	content := "---\nname: frontend\nversion: 1.0.0\ndescription: Frontend agent\n---\n# Frontend\n"
+
+	origin := t.TempDir()
+	_, err := git.Run(origin, "init", "-q")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(origin, "agents"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(origin, "agents", "frontend.md"), []byte(content), 0644))
+	_, err = git.Run(origin, "add", "-A")
+	require.NoError(t, err)
+	_, err = git.Run(origin, "commit", "-q", "-m", "add frontend")
+	require.NoError(t, err)
+
+	commit, err := git.Commit(origin, "HEAD")
+	require.NoError(t, err)
+
+	l := &lock.Lock{Version: lock.Version, Agents: []lock.Entry{{
+		Name:        "frontend",
+		Version:     "1.0.0",
+		Source:      "team",
+		Remote:      origin,
+		Commit:      commit,
+		Path:        "agents/frontend.md",
+		ContentHash: manifest.HashContent([]byte(content)),
+	}}}
+
+	t.Run("recreates agents and records commits", func(t *testing.T) {
+		project := t.TempDir()
+
+		results, err := InstallLocked(project, l)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Success)
+
+		deployed, err := os.ReadFile(filepath.Join(project, ".claude", "agents", "frontend.md"))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(deployed))
+
+		pm, err := manifest.ReadProjectManifest(project)
+		require.NoError(t, err)
+		entry := pm.FindAgent("frontend")
+		require.NotNil(t, entry)
+		assert.Equal(t, commit, entry.Commit)
+		assert.Equal(t, "team", entry.Source)
+		assert.Equal(t, l.Agents[0].ContentHash, entry.ContentHash)
+	})
+
+	t.Run("overwrites local edits", func(t *testing.T) {
+		project := t.TempDir()
+		agentsDir := filepath.Join(project, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "frontend.md"), []byte("local edits\n"), 0644))
+
+		_, err := InstallLocked(project, l)
+		require.NoError(t, err)
+		assert.NoError(t, lock.Verify(project, l))
+	})
+
+	t.Run("fails before deploying on hash mismatch", func(t *testing.T) {
+		project := t.TempDir()
+		tampered := *l
+		tampered.Agents = []lock.Entry{l.Agents[0]}
+		tampered.Agents[0].ContentHash = "sha256:0000"
+
+		_, err := InstallLocked(project, &tampered)
+		assert.ErrorContains(t, err, "content hash mismatch")
+		assert.NoFileExists(t, filepath.Join(project, ".claude", "agents", "frontend.md"))
+	})
+}
@@ -0,0 +1,277 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+)
+
+// mcpConfigFile is the project-scoped MCP configuration Claude Code reads,
+// relative to the project root
+const mcpConfigFile = ".mcp.json"
+
+// mcpConfig is a project's .mcp.json. Keys other than mcpServers are kept
+// as they are.
+type mcpConfig struct {
+	doc     map[string]json.RawMessage
+	servers map[string]json.RawMessage
+	changed bool
+}
+
+// mcpUpdate is what deploying or removing agents changes in a project's
+// .mcp.json
+type mcpUpdate struct {
+	removed   []string            // Servers no deployed agent requires anymore
+	kept      map[string][]string // Servers the user configured, left alone, by agent ID
+	conflicts map[string][]string // Servers another agent configures differently, by agent ID
+	content   []byte              // New .mcp.json content; nil if nothing changes
+}
+
+// readMCPConfig reads a project's .mcp.json, or starts an empty one
+func readMCPConfig(projectPath string) (*mcpConfig, error) {
+	c := &mcpConfig{
+		doc:     make(map[string]json.RawMessage),
+		servers: make(map[string]json.RawMessage),
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, mcpConfigFile))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", mcpConfigFile, err)
+	}
+
+	if err := json.Unmarshal(data, &c.doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", mcpConfigFile, err)
+	}
+	if raw, ok := c.doc["mcpServers"]; ok {
+		if err := json.Unmarshal(raw, &c.servers); err != nil {
+			return nil, fmt.Errorf("failed to parse mcpServers in %s: %w", mcpConfigFile, err)
+		}
+	}
+
+	return c, nil
+}
+
+// set writes a server's configuration, noting whether it changed
+func (c *mcpConfig) set(name string, server json.RawMessage) {
+	if existing, ok := c.servers[name]; ok && jsonEqual(existing, server) {
+		return
+	}
+	c.servers[name] = server
+	c.changed = true
+}
+
+// remove deletes a server, if it is configured
+func (c *mcpConfig) remove(name string) bool {
+	if _, ok := c.servers[name]; !ok {
+		return false
+	}
+	delete(c.servers, name)
+	c.changed = true
+	return true
+}
+
+func (c *mcpConfig) marshal() ([]byte, error) {
+	servers, err := json.Marshal(c.servers)
+	if err != nil {
+		return nil, err
+	}
+	c.doc["mcpServers"] = servers
+
+	data, err := json.MarshalIndent(c.doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", mcpConfigFile, err)
+	}
+	return append(data, '\n'), nil
+}
+
+// planMCPServers works out the .mcp.json changes once agents have been
+// deployed to, or removed from, a project manifest. Managed names the
+// servers CAMI managed before the change, see managedMCPServers. Each
+// deployed agent's servers are written and recorded in its manifest entry,
+// except servers the user configured themselves, which are kept as they are.
+// A server another deployed agent already declares with a different config
+// keeps the first config and is reported as a conflict. Managed servers no
+// tracked agent requires anymore are removed. The user scope takes no
+// servers, see checkUserScopeServers.
+func planMCPServers(projectPath string, projectManifest *manifest.ProjectManifest, agents []*agent.Agent, managed map[string]bool) (*mcpUpdate, error) {
+	if config.IsUserScope(projectPath) {
+		return checkUserScopeServers(agents)
+	}
+
+	current, err := readMCPConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]bool, len(managed))
+	for name := range managed {
+		owned[name] = true
+	}
+
+	// Agents outside this change keep the servers their entries record
+	deploying := make(map[string]bool, len(agents))
+	for _, ag := range agents {
+		deploying[ag.ID()] = true
+	}
+	claimedBy := make(map[string]string)
+	for _, entry := range projectManifest.Agents {
+		if deploying[entry.ID()] {
+			continue
+		}
+		for _, name := range entry.MCPServers {
+			claimedBy[name] = entry.ID()
+		}
+	}
+
+	update := &mcpUpdate{kept: make(map[string][]string), conflicts: make(map[string][]string)}
+	for _, ag := range agents {
+		entry := projectManifest.FindAgent(ag.ID())
+		if entry == nil {
+			continue
+		}
+
+		servers, err := ag.MCPServers()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ag.ID(), err)
+		}
+
+		entry.MCPServers = nil
+		for _, name := range sortedServerNames(servers) {
+			existing, configured := current.servers[name]
+			if configured && !owned[name] {
+				update.kept[ag.ID()] = append(update.kept[ag.ID()], name)
+				continue
+			}
+
+			entry.MCPServers = append(entry.MCPServers, name)
+			if other := claimedBy[name]; other != "" && other != ag.ID() {
+				if configured && !jsonEqual(existing, servers[name]) {
+					update.conflicts[ag.ID()] = append(update.conflicts[ag.ID()], name)
+				}
+				continue
+			}
+
+			current.set(name, servers[name])
+			claimedBy[name] = ag.ID()
+			owned[name] = true
+		}
+	}
+
+	required := managedMCPServers(projectManifest)
+	var stale []string
+	for name := range managed {
+		if !required[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		if current.remove(name) {
+			update.removed = append(update.removed, name)
+		}
+	}
+
+	if current.changed {
+		if update.content, err = current.marshal(); err != nil {
+			return nil, err
+		}
+	}
+
+	return update, nil
+}
+
+// checkUserScopeServers refuses agents that require MCP servers when
+// deploying to the user scope. Claude Code reads user-scope servers from
+// ~/.claude.json, which it manages itself, not from ~/.mcp.json.
+func checkUserScopeServers(agents []*agent.Agent) (*mcpUpdate, error) {
+	for _, ag := range agents {
+		servers, err := ag.MCPServers()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ag.ID(), err)
+		}
+		if len(servers) > 0 {
+			return nil, fmt.Errorf("%s requires MCP servers (%s), which CAMI can't configure for the user scope: deploy it to a project, or add them with 'claude mcp add --scope user'",
+				ag.ID(), strings.Join(sortedServerNames(servers), ", "))
+		}
+	}
+	return &mcpUpdate{kept: make(map[string][]string), conflicts: make(map[string][]string)}, nil
+}
+
+// managedMCPServers returns the servers CAMI added to a project's .mcp.json
+// for the agents its manifest tracks
+func managedMCPServers(projectManifest *manifest.ProjectManifest) map[string]bool {
+	managed := make(map[string]bool)
+	for _, entry := range projectManifest.Agents {
+		for _, name := range entry.MCPServers {
+			managed[name] = true
+		}
+	}
+	return managed
+}
+
+// writeMCPConfig writes the new .mcp.json of an update, if it changes
+func writeMCPConfig(projectPath string, update *mcpUpdate) error {
+	if update.content == nil {
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, mcpConfigFile), update.content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mcpConfigFile, err)
+	}
+	return nil
+}
+
+// deployedAgents returns the agents of the successful results
+func deployedAgents(results []*Result) []*agent.Agent {
+	var agents []*agent.Agent
+	for _, result := range results {
+		if result.Success || result.Merged {
+			agents = append(agents, result.Agent)
+		}
+	}
+	return agents
+}
+
+// noteMCPServers tells, in their results, which agents need MCP servers the
+// user already configured, or another agent declares, differently and were
+// left alone
+func noteMCPServers(results []*Result, update *mcpUpdate) {
+	for _, result := range results {
+		if names := update.kept[result.Agent.ID()]; len(names) > 0 {
+			result.Message += fmt.Sprintf(" (kept existing %s config for %s)", mcpConfigFile, strings.Join(names, ", "))
+		}
+		if names := update.conflicts[result.Agent.ID()]; len(names) > 0 {
+			result.Message += fmt.Sprintf(" (conflict: another deployed agent configures %s differently; kept its config)", strings.Join(names, ", "))
+		}
+	}
+}
+
+func sortedServerNames(servers map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonEqual reports whether two JSON values are the same, ignoring
+// formatting and key order
+func jsonEqual(a, b json.RawMessage) bool {
+	var valueA, valueB any
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
@@ -0,0 +1,174 @@
+package deploy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMCPAgent(t *testing.T, name string, servers string) *agent.Agent {
+	t.Helper()
+	ag, err := agent.ParseAgent([]byte("---\nname: "+name+"\nversion: 1.0.0\nmcpServers:\n"+servers+"---\nBody\n"), "/fake/path/"+name+".md")
+	require.NoError(t, err)
+	return ag
+}
+
+func readMCPServers(t *testing.T, projectPath string) map[string]json.RawMessage {
+	t.Helper()
+	config, err := readMCPConfig(projectPath)
+	require.NoError(t, err)
+	return config.servers
+}
+
+func TestMCPServerDeployment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	github := "  github:\n    command: npx\n"
+
+	t.Run("deploy merges servers into an existing .mcp.json", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		existing := `{"mcpServers": {"postgres": {"command": "pg-mcp"}}, "other": true}`
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, mcpConfigFile), []byte(existing), 0644))
+
+		results, err := DeployAndRecord([]*agent.Agent{createTestMCPAgent(t, "frontend", github)}, tmpDir, true, nil)
+		require.NoError(t, err)
+		require.True(t, results[0].Success, results[0].Message)
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, mcpConfigFile))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"mcpServers": {"postgres": {"command": "pg-mcp"}, "github": {"command": "npx"}}, "other": true}`, string(data))
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"github"}, pm.FindAgent("frontend").MCPServers)
+	})
+
+	t.Run("servers the user configured are kept", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		existing := `{"mcpServers": {"github": {"command": "my-github"}}}`
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, mcpConfigFile), []byte(existing), 0644))
+
+		results, err := DeployAndRecord([]*agent.Agent{createTestMCPAgent(t, "frontend", github)}, tmpDir, true, nil)
+		require.NoError(t, err)
+		require.True(t, results[0].Success)
+		assert.Contains(t, results[0].Message, "kept existing .mcp.json config for github")
+
+		assert.JSONEq(t, `{"command": "my-github"}`, string(readMCPServers(t, tmpDir)["github"]))
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, pm.FindAgent("frontend").MCPServers)
+
+		// Undeploying leaves the user's server alone
+		_, err = UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		assert.Contains(t, readMCPServers(t, tmpDir), "github")
+	})
+
+	t.Run("undeploy removes servers no remaining agent requires", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestMCPAgent(t, "frontend", github+"  figma:\n    url: http://localhost:3845/mcp\n")
+		backend := createTestMCPAgent(t, "backend", github)
+		_, err := DeployAndRecord([]*agent.Agent{frontend, backend}, tmpDir, true, nil)
+		require.NoError(t, err)
+		assert.Len(t, readMCPServers(t, tmpDir), 2)
+
+		_, err = UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		servers := readMCPServers(t, tmpDir)
+		assert.Contains(t, servers, "github")
+		assert.NotContains(t, servers, "figma")
+
+		_, err = UndeployAgents(tmpDir, []string{"backend"})
+		require.NoError(t, err)
+		assert.Empty(t, readMCPServers(t, tmpDir))
+	})
+
+	t.Run("undeploy keeps the entry and servers of a file it can't delete", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestMCPAgent(t, "frontend", github)
+		_, err := DeployAndRecord([]*agent.Agent{frontend}, tmpDir, true, nil)
+		require.NoError(t, err)
+
+		remove := removeArtifact
+		removeArtifact = func(id, path string) error { return os.ErrPermission }
+		t.Cleanup(func() { removeArtifact = remove })
+
+		results, err := UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		assert.False(t, results[0].Success)
+		assert.Contains(t, results[0].Message, "Failed to remove file")
+
+		assert.FileExists(t, AgentPath(tmpDir, frontend))
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		require.NotNil(t, pm.FindAgent("frontend"))
+		assert.Equal(t, []string{"github"}, pm.FindAgent("frontend").MCPServers)
+		assert.Contains(t, readMCPServers(t, tmpDir), "github")
+	})
+
+	t.Run("a second agent declaring a server differently is a conflict", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestMCPAgent(t, "frontend", github)
+		backend := createTestMCPAgent(t, "backend", "  github:\n    command: gh-mcp\n")
+
+		results, err := DeployAndRecord([]*agent.Agent{frontend, backend}, tmpDir, true, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, results[0].Message, "conflict")
+		assert.Contains(t, results[1].Message, "conflict: another deployed agent configures github differently")
+		assert.JSONEq(t, `{"command": "npx"}`, string(readMCPServers(t, tmpDir)["github"]))
+
+		// Deploying the second agent on its own doesn't overwrite the first's config
+		results, err = DeployAndRecord([]*agent.Agent{backend}, tmpDir, true, nil)
+		require.NoError(t, err)
+		assert.Contains(t, results[0].Message, "conflict")
+		assert.JSONEq(t, `{"command": "npx"}`, string(readMCPServers(t, tmpDir)["github"]))
+
+		// Both still require the server, so it stays until neither is deployed
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"github"}, pm.FindAgent("backend").MCPServers)
+		_, err = UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		assert.Contains(t, readMCPServers(t, tmpDir), "github")
+	})
+
+	t.Run("an agent updating its own server is not a conflict", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_, err := DeployAndRecord([]*agent.Agent{createTestMCPAgent(t, "frontend", github)}, tmpDir, true, nil)
+		require.NoError(t, err)
+
+		results, err := DeployAndRecord([]*agent.Agent{createTestMCPAgent(t, "frontend", "  github:\n    command: gh-mcp\n")}, tmpDir, true, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, results[0].Message, "conflict")
+		assert.JSONEq(t, `{"command": "gh-mcp"}`, string(readMCPServers(t, tmpDir)["github"]))
+	})
+
+	t.Run("the user scope refuses agents with servers", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		results, err := DeployAndRecord([]*agent.Agent{createTestMCPAgent(t, "frontend", github)}, home, true, nil)
+		require.NoError(t, err)
+		assert.False(t, results[0].Success)
+		assert.Contains(t, results[0].Message, "can't configure for the user scope")
+		assert.NoFileExists(t, filepath.Join(home, mcpConfigFile))
+		assert.NoFileExists(t, filepath.Join(home, ".claude", "agents", "frontend.md"))
+
+		_, err = DeployAndRecord([]*agent.Agent{createTestAgent("backend", "1.0.0")}, home, true, nil)
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(home, mcpConfigFile))
+	})
+
+	t.Run("agents without servers leave .mcp.json alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_, err := DeployAndRecord([]*agent.Agent{createTestAgent("frontend", "1.0.0")}, tmpDir, true, nil)
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(tmpDir, mcpConfigFile))
+	})
+}
@@ -0,0 +1,165 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/diff"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/store"
+)
+
+// MergeAgent deploys an agent over a possibly modified deployed copy. The
+// content recorded in the project manifest at the last deployment is used as
+// the base of a three-way merge between the local file and the new source
+// version. Conflicting hunks are written with conflict markers and reported
+// as a conflict. Manifest entries are not written; see MergeAgents.
+func MergeAgent(ag *agent.Agent, targetPath string, entry *manifest.DeployedAgent) (*Result, error) {
+	if ag.ArtifactKind() == agent.KindSkill {
+		return mergeSkill(ag, targetPath, entry)
+	}
+
+	targetFile := AgentPath(targetPath, ag)
+
+	local, err := os.ReadFile(targetFile)
+	if os.IsNotExist(err) {
+		return DeployAgent(ag, targetPath, false)
+	}
+	if err != nil {
+		return &Result{
+			Agent:   ag,
+			Success: false,
+			Message: fmt.Sprintf("Failed to read deployed file: %v", err),
+		}, nil
+	}
+
+	source := ag.FullContent()
+	sourceHash := manifest.HashContent([]byte(source))
+	localHash := manifest.HashContent(local)
+
+	if localHash == sourceHash {
+		return &Result{Agent: ag, Success: true, Message: "Already up to date"}, nil
+	}
+
+	if entry == nil || entry.ContentHash == "" || !store.Has(entry.ContentHash) {
+		return &Result{
+			Agent:    ag,
+			Success:  false,
+			Conflict: true,
+			Message:  "No deployed snapshot recorded to merge against",
+		}, nil
+	}
+
+	// Deployed file untouched since deployment: plain update
+	if localHash == entry.ContentHash {
+		return DeployAgent(ag, targetPath, true)
+	}
+
+	// Source unchanged since deployment: keep the local edits as they are
+	if sourceHash == entry.ContentHash {
+		return &Result{Agent: ag, Success: true, Message: "Kept local changes (source unchanged)"}, nil
+	}
+
+	base, err := store.Get(entry.ContentHash)
+	if err != nil {
+		return &Result{
+			Agent:   ag,
+			Success: false,
+			Message: fmt.Sprintf("Failed to read deployed snapshot: %v", err),
+		}, nil
+	}
+
+	merged := diff.Merge3(string(base), string(local), source)
+	if err := os.WriteFile(targetFile, []byte(merged.Text), 0644); err != nil {
+		return &Result{
+			Agent:   ag,
+			Success: false,
+			Message: fmt.Sprintf("Failed to write file: %v", err),
+		}, nil
+	}
+
+	if !merged.Clean() {
+		return &Result{
+			Agent:    ag,
+			Success:  false,
+			Conflict: true,
+			Merged:   true,
+			Message:  fmt.Sprintf("Merged with %d conflict(s); resolve the markers in %s", merged.Conflicts, targetFile),
+		}, nil
+	}
+
+	return &Result{Agent: ag, Success: true, Merged: true, Message: "Merged local changes"}, nil
+}
+
+// mergeSkill updates a deployed skill unless its directory was edited since
+// it was deployed. Skills span several files and aren't merged: a skill
+// edited both locally and in its source is reported as a conflict.
+func mergeSkill(ag *agent.Agent, targetPath string, entry *manifest.DeployedAgent) (*Result, error) {
+	localHash, err := manifest.CalculateArtifactHash(agent.KindSkill, AgentPath(targetPath, ag))
+	if err != nil {
+		return DeployAgent(ag, targetPath, false)
+	}
+
+	sourceHash := manifest.HashArtifact(ag)
+	switch {
+	case localHash == sourceHash:
+		return &Result{Agent: ag, Success: true, Message: "Already up to date"}, nil
+	case entry != nil && localHash == entry.ContentHash:
+		return DeployAgent(ag, targetPath, true)
+	case entry != nil && sourceHash == entry.ContentHash:
+		return &Result{Agent: ag, Success: true, Message: "Kept local changes (source unchanged)"}, nil
+	}
+
+	return &Result{
+		Agent:    ag,
+		Success:  false,
+		Conflict: true,
+		Message:  "Skill has local changes; skills can't be merged, redeploy with --overwrite to replace them",
+	}, nil
+}
+
+// MergeAgents merges multiple agents into a target location and records the
+// merged source versions in the project and central manifests, so the next
+// merge uses them as its base. Agents left with conflict markers are recorded
+// too: their markers already contain the new source content. The MCP servers
+// the agents require are merged into the project's .mcp.json.
+func MergeAgents(agents []*agent.Agent, targetPath string) ([]*Result, error) {
+	projectManifest, err := LoadProjectManifest(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*Result
+	now := time.Now()
+	managed := managedMCPServers(projectManifest)
+
+	for _, ag := range agents {
+		result, err := MergeAgent(ag, targetPath, projectManifest.FindAgent(ag.ID()))
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+
+		// Failures that never wrote the file leave the recorded base as it was
+		if !result.Success && !result.Merged {
+			continue
+		}
+
+		if err := recordDeployed(upsertEntry(projectManifest, ag), ag, now); err != nil {
+			return results, err
+		}
+	}
+
+	mcp, err := planMCPServers(targetPath, projectManifest, deployedAgents(results), managed)
+	if err != nil {
+		return results, err
+	}
+	if err := writeMCPConfig(targetPath, mcp); err != nil {
+		return results, err
+	}
+	noteMCPServers(results, mcp)
+
+	return results, WriteManifests(targetPath, projectManifest)
+}
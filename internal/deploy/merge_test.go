@@ -0,0 +1,154 @@
+package deploy
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeAgent creates a test agent with a multi-section body
+func mergeAgent(version, intro string) *agent.Agent {
+	ag := createTestAgent("frontend", version)
+	ag.Content = "# Frontend\n\n" + intro + "\n\n## Rules\n- Be accessible\n"
+	return ag
+}
+
+// editDeployed rewrites the deployed file with a string replacement
+func editDeployed(t *testing.T, projectPath string, ag *agent.Agent, old, new string) {
+	t.Helper()
+	path := AgentPath(projectPath, ag)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0644))
+}
+
+func TestMergeAgents(t *testing.T) {
+	t.Run("deploys and records new agents", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		ag := mergeAgent("1.0.0", "Build UIs.")
+
+		results, err := MergeAgents([]*agent.Agent{ag}, tmpDir)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Success)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry := pm.FindAgent("frontend")
+		require.NotNil(t, entry)
+		assert.Equal(t, manifest.HashContent([]byte(ag.FullContent())), entry.ContentHash)
+		assert.Equal(t, "cami", entry.Origin)
+	})
+
+	t.Run("updates unmodified files", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		_, err := MergeAgents([]*agent.Agent{mergeAgent("1.0.0", "Build UIs.")}, tmpDir)
+		require.NoError(t, err)
+
+		updated := mergeAgent("1.1.0", "Build great UIs.")
+		results, err := MergeAgents([]*agent.Agent{updated}, tmpDir)
+		require.NoError(t, err)
+
+		assert.True(t, results[0].Success)
+		assert.False(t, results[0].Merged)
+		content, err := os.ReadFile(AgentPath(tmpDir, updated))
+		require.NoError(t, err)
+		assert.Equal(t, updated.FullContent(), string(content))
+	})
+
+	t.Run("merges non-overlapping local edits", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		original := mergeAgent("1.0.0", "Build UIs.")
+		_, err := MergeAgents([]*agent.Agent{original}, tmpDir)
+		require.NoError(t, err)
+
+		editDeployed(t, tmpDir, original, "- Be accessible\n", "- Be accessible\n- Use our design system\n")
+
+		updated := mergeAgent("1.1.0", "Build great UIs.")
+		results, err := MergeAgents([]*agent.Agent{updated}, tmpDir)
+		require.NoError(t, err)
+
+		assert.True(t, results[0].Success)
+		assert.True(t, results[0].Merged)
+
+		content, err := os.ReadFile(AgentPath(tmpDir, updated))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "version: 1.1.0")
+		assert.Contains(t, string(content), "Build great UIs.")
+		assert.Contains(t, string(content), "- Use our design system")
+
+		// The new source version becomes the base for the next merge
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, manifest.HashContent([]byte(updated.FullContent())), pm.FindAgent("frontend").ContentHash)
+		assert.Equal(t, "1.1.0", pm.FindAgent("frontend").Version)
+	})
+
+	t.Run("writes conflict markers for overlapping edits", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		original := mergeAgent("1.0.0", "Build UIs.")
+		_, err := MergeAgents([]*agent.Agent{original}, tmpDir)
+		require.NoError(t, err)
+
+		editDeployed(t, tmpDir, original, "Build UIs.", "Build web UIs.")
+
+		updated := mergeAgent("1.0.0", "Build mobile UIs.")
+		results, err := MergeAgents([]*agent.Agent{updated}, tmpDir)
+		require.NoError(t, err)
+
+		assert.False(t, results[0].Success)
+		assert.True(t, results[0].Conflict)
+		assert.True(t, results[0].Merged)
+		assert.Contains(t, results[0].Message, "1 conflict")
+
+		content, err := os.ReadFile(AgentPath(tmpDir, updated))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "<<<<<<< local\nBuild web UIs.\n||||||| base\nBuild UIs.\n=======\nBuild mobile UIs.\n>>>>>>> source\n")
+	})
+
+	t.Run("keeps local edits when source unchanged", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		original := mergeAgent("1.0.0", "Build UIs.")
+		_, err := MergeAgents([]*agent.Agent{original}, tmpDir)
+		require.NoError(t, err)
+
+		editDeployed(t, tmpDir, original, "Build UIs.", "Build web UIs.")
+
+		results, err := MergeAgents([]*agent.Agent{original}, tmpDir)
+		require.NoError(t, err)
+
+		assert.True(t, results[0].Success)
+		content, err := os.ReadFile(AgentPath(tmpDir, original))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "Build web UIs.")
+	})
+
+	t.Run("refuses to merge without a recorded base", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		original := mergeAgent("1.0.0", "Build UIs.")
+		_, err := DeployAgent(original, tmpDir, false)
+		require.NoError(t, err)
+		editDeployed(t, tmpDir, original, "Build UIs.", "Build web UIs.")
+
+		results, err := MergeAgents([]*agent.Agent{mergeAgent("1.1.0", "Build UIs.")}, tmpDir)
+		require.NoError(t, err)
+
+		assert.True(t, results[0].Conflict)
+		assert.False(t, results[0].Merged)
+		content, err := os.ReadFile(AgentPath(tmpDir, original))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "Build web UIs.")
+		assert.NotContains(t, string(content), "<<<<<<<")
+	})
+}
@@ -0,0 +1,69 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/plan"
+)
+
+// PlanDeploy plans what DeployAndRecord would do without touching disk: the
+// agent files it would create or overwrite, those it would skip as
+// conflicts, the manifest entries it would add or update and whether the
+// project's .mcp.json would change
+func PlanDeploy(agents []*agent.Agent, projectPath string, overwrite bool, sources []config.AgentSource) (*plan.Plan, error) {
+	p := plan.New("deploy", projectPath)
+
+	before, err := LoadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	after, err := LoadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	managed := managedMCPServers(after)
+	var deploying []*agent.Agent
+	for _, ag := range agents {
+		targetFile := AgentPath(projectPath, ag)
+		if _, err := os.Stat(targetFile); err == nil && !overwrite {
+			p.Skip(targetFile, "file already exists")
+			continue
+		}
+		deploying = append(deploying, ag)
+
+		for _, path := range sortedPaths(artifactFiles(targetFile, ag)) {
+			p.Write(path, agentLabel(ag))
+		}
+		entry := upsertEntry(after, ag)
+		describeDeployed(entry, ag, []byte(ag.FullContent()), now)
+		recordSource(entry, ag, sources)
+	}
+
+	mcp, err := planMCPServers(projectPath, after, deploying, managed)
+	if err != nil {
+		return nil, err
+	}
+	if mcp.content != nil {
+		p.Write(filepath.Join(projectPath, mcpConfigFile), "MCP servers")
+	}
+
+	if err := p.WriteManifests(projectPath, before, after); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// agentLabel names an agent or command with its version, if it has one
+func agentLabel(ag *agent.Agent) string {
+	if ag.Version == "" {
+		return ag.ID()
+	}
+	return ag.ID() + " v" + ag.Version
+}
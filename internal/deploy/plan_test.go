@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanDeploy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	sources := []config.AgentSource{{Name: "team", Path: "/fake/path", Priority: 10}}
+
+	findFile := func(p *plan.Plan, path string) *plan.FileChange {
+		for i := range p.Files {
+			if p.Files[i].Path == path {
+				return &p.Files[i]
+			}
+		}
+		return nil
+	}
+
+	t.Run("new project creates files and adds entries", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+
+		p, err := PlanDeploy([]*agent.Agent{frontend}, tmpDir, false, sources)
+		require.NoError(t, err)
+
+		file := findFile(p, AgentPath(tmpDir, frontend))
+		require.NotNil(t, file)
+		assert.Equal(t, plan.FileCreate, file.Action)
+
+		manifestFile := findFile(p, filepath.Join(tmpDir, manifest.ProjectManifestFilename))
+		require.NotNil(t, manifestFile)
+		assert.Equal(t, plan.FileCreate, manifestFile.Action)
+
+		require.Len(t, p.Manifests, 1)
+		assert.Equal(t, plan.ManifestAdd, p.Manifests[0].Action)
+		assert.Equal(t, "frontend", p.Manifests[0].Agent)
+		assert.Equal(t, "1.0.0", p.Manifests[0].ToVersion)
+
+		// Nothing was written
+		_, err = os.Stat(filepath.Join(tmpDir, ".claude"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("existing files are skipped or overwritten", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_, err := DeployAndRecord([]*agent.Agent{createTestAgent("frontend", "1.0.0")}, tmpDir, false, sources)
+		require.NoError(t, err)
+
+		frontend := createTestAgent("frontend", "1.1.0")
+		backend := createTestAgent("backend", "1.0.0")
+
+		p, err := PlanDeploy([]*agent.Agent{frontend, backend}, tmpDir, false, sources)
+		require.NoError(t, err)
+		file := findFile(p, AgentPath(tmpDir, frontend))
+		require.NotNil(t, file)
+		assert.Equal(t, plan.FileSkip, file.Action)
+		require.Len(t, p.Manifests, 1)
+		assert.Equal(t, "backend", p.Manifests[0].Agent)
+
+		p, err = PlanDeploy([]*agent.Agent{frontend, backend}, tmpDir, true, sources)
+		require.NoError(t, err)
+		file = findFile(p, AgentPath(tmpDir, frontend))
+		require.NotNil(t, file)
+		assert.Equal(t, plan.FileOverwrite, file.Action)
+		require.Len(t, p.Manifests, 2)
+		assert.Equal(t, plan.ManifestUpdate, p.Manifests[0].Action)
+		assert.Equal(t, "1.0.0", p.Manifests[0].FromVersion)
+		assert.Equal(t, "1.1.0", p.Manifests[0].ToVersion)
+
+		content, err := os.ReadFile(AgentPath(tmpDir, frontend))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "version: 1.0.0")
+	})
+
+	t.Run("redeploying the same content leaves entries alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		_, err := DeployAndRecord([]*agent.Agent{frontend}, tmpDir, false, sources)
+		require.NoError(t, err)
+
+		p, err := PlanDeploy([]*agent.Agent{frontend}, tmpDir, true, sources)
+		require.NoError(t, err)
+		assert.Empty(t, p.Manifests)
+	})
+}
@@ -0,0 +1,134 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+)
+
+// defaultPriority is recorded for agents that don't come from a configured source
+const defaultPriority = 999
+
+// DeployAndRecord deploys agents and upserts their manifest entries, keyed by
+// agent name, as one transaction: the agent files and the project manifest
+// are committed together and the project is recorded in the central
+// manifest, and a failure at any step rolls all of it back. Entries for
+// other agents are left alone, and existing entries keep their
+// CustomOverride and Origin. Sources supply the priority recorded for each
+// agent.
+func DeployAndRecord(agents []*agent.Agent, projectPath string, overwrite bool, sources []config.AgentSource) ([]*Result, error) {
+	return deployTransaction(agents, projectPath, overwrite, func(projectManifest *manifest.ProjectManifest, results []*Result) error {
+		now := time.Now()
+		for _, result := range results {
+			if !result.Success {
+				continue
+			}
+
+			entry := upsertEntry(projectManifest, result.Agent)
+			if err := recordDeployed(entry, result.Agent, now); err != nil {
+				return err
+			}
+			recordSource(entry, result.Agent, sources)
+		}
+		return nil
+	}), nil
+}
+
+// recordSource records the source an agent was deployed from and its
+// priority, or defaultPriority if no configured source provides it
+func recordSource(entry *manifest.DeployedAgent, ag *agent.Agent, sources []config.AgentSource) {
+	entry.Priority = defaultPriority
+	for _, src := range sources {
+		if src.Name == ag.Source || (ag.Source == "" && strings.HasPrefix(ag.FilePath, filepath.Clean(src.Path)+string(filepath.Separator))) {
+			entry.Source = src.Name
+			entry.Priority = src.Priority
+			return
+		}
+	}
+}
+
+// entryKind is the kind recorded in a manifest entry: commands and skills are
+// marked, agents are left unmarked as manifests have always had them
+func entryKind(ag *agent.Agent) agent.Kind {
+	if ag.ArtifactKind() == agent.KindAgent {
+		return ""
+	}
+	return ag.Kind
+}
+
+// LoadProjectManifest reads a project's manifest, or starts a new one if the
+// project doesn't have one yet
+func LoadProjectManifest(projectPath string) (*manifest.ProjectManifest, error) {
+	if _, err := os.Stat(filepath.Join(projectPath, manifest.ProjectManifestFilename)); os.IsNotExist(err) {
+		return &manifest.ProjectManifest{
+			Version:      "1",
+			State:        manifest.StateCAMINative,
+			NormalizedAt: time.Now(),
+		}, nil
+	}
+
+	return manifest.ReadProjectManifest(projectPath)
+}
+
+// upsertEntry returns the manifest entry for an agent or command, adding one
+// deployed by CAMI if it isn't tracked yet
+func upsertEntry(projectManifest *manifest.ProjectManifest, ag *agent.Agent) *manifest.DeployedAgent {
+	if entry := projectManifest.FindAgent(ag.ID()); entry != nil {
+		return entry
+	}
+
+	projectManifest.Agents = append(projectManifest.Agents, manifest.DeployedAgent{Name: ag.Name, Kind: entryKind(ag), Origin: "cami"})
+	return &projectManifest.Agents[len(projectManifest.Agents)-1]
+}
+
+// RecordFound upserts entries for agents found already deployed in a project,
+// as import and normalize record them, and marks the project CAMI-native. A
+// found entry replaces a tracked one's version and hashes, and its source
+// when one was found; the tracked entry keeps its CustomOverride, Origin,
+// DeployedAt, Commit, MCPServers and Bundle. Entries for other agents,
+// commands and skills are left alone.
+func RecordFound(projectManifest *manifest.ProjectManifest, found ...manifest.DeployedAgent) {
+	for _, f := range found {
+		entry := projectManifest.FindAgent(f.ID())
+		if entry == nil {
+			projectManifest.Agents = append(projectManifest.Agents, f)
+			continue
+		}
+
+		entry.Version = f.Version
+		entry.ContentHash = f.ContentHash
+		entry.MetadataHash = f.MetadataHash
+		entry.NeedsUpgrade = f.NeedsUpgrade
+		if f.SourcePath != "" {
+			entry.Source = f.Source
+			entry.SourcePath = f.SourcePath
+			entry.Priority = f.Priority
+		}
+		if entry.Origin == "" {
+			entry.Origin = f.Origin
+		}
+	}
+
+	projectManifest.State = manifest.StateCAMINative
+	projectManifest.NormalizedAt = time.Now()
+}
+
+// WriteManifests writes a project's manifest and records it in the central
+// manifest
+func WriteManifests(projectPath string, projectManifest *manifest.ProjectManifest) error {
+	if err := manifest.WriteProjectManifest(projectPath, projectManifest); err != nil {
+		return err
+	}
+
+	if err := manifest.UpdateCentralDeployment(projectPath, projectManifest); err != nil {
+		return fmt.Errorf("failed to update central manifest: %w", err)
+	}
+
+	return nil
+}
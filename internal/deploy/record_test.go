@@ -0,0 +1,299 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployAndRecord(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	sources := []config.AgentSource{{Name: "team", Path: "/fake/path", Priority: 10}}
+
+	deployAndRecord := func(t *testing.T, projectPath string, agents ...*agent.Agent) {
+		t.Helper()
+		results, err := DeployAndRecord(agents, projectPath, true, sources)
+		require.NoError(t, err)
+		for _, result := range results {
+			require.True(t, result.Success, result.Message)
+		}
+	}
+
+	t.Run("later deployments keep earlier agents", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployAndRecord(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+		deployAndRecord(t, tmpDir, createTestAgent("backend", "1.0.0"))
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, pm.Agents, 2)
+
+		entry := pm.FindAgent("frontend")
+		require.NotNil(t, entry)
+		assert.Equal(t, "team", entry.Source)
+		assert.Equal(t, 10, entry.Priority)
+		assert.Equal(t, "cami", entry.Origin)
+		assert.Equal(t, manifest.HashContent([]byte(createTestAgent("frontend", "1.0.0").FullContent())), entry.ContentHash)
+
+		central, err := manifest.ReadCentralManifest()
+		require.NoError(t, err)
+		deployment, ok := central.Deployments[tmpDir]
+		require.True(t, ok)
+		assert.Len(t, deployment.Agents, 2)
+	})
+
+	t.Run("redeploying preserves entry metadata", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployAndRecord(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		deployedAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry := pm.FindAgent("frontend")
+		entry.CustomOverride = true
+		entry.Origin = "manual"
+		entry.DeployedAt = deployedAt
+		require.NoError(t, manifest.WriteProjectManifest(tmpDir, pm))
+
+		// Same content: the deployment time stands
+		deployAndRecord(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+		pm, err = manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry = pm.FindAgent("frontend")
+		assert.True(t, entry.CustomOverride)
+		assert.Equal(t, "manual", entry.Origin)
+		assert.True(t, entry.DeployedAt.Equal(deployedAt))
+
+		// New content moves it
+		deployAndRecord(t, tmpDir, createTestAgent("frontend", "1.1.0"))
+		pm, err = manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry = pm.FindAgent("frontend")
+		assert.Equal(t, "1.1.0", entry.Version)
+		assert.True(t, entry.CustomOverride)
+		assert.True(t, entry.DeployedAt.After(deployedAt))
+	})
+
+	t.Run("conflicts are not recorded", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_, err := DeployAgent(createTestAgent("frontend", "1.0.0"), tmpDir, false)
+		require.NoError(t, err)
+
+		results, err := DeployAndRecord([]*agent.Agent{createTestAgent("frontend", "1.1.0")}, tmpDir, false, sources)
+		require.NoError(t, err)
+		assert.True(t, results[0].Conflict)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, pm.Agents)
+	})
+
+	t.Run("user scope has its own manifest", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		userPath, err := config.ExpandUserScope(config.UserScopeName)
+		require.NoError(t, err)
+
+		deployAndRecord(t, userPath, createTestAgent("frontend", "1.0.0"))
+
+		assert.FileExists(t, filepath.Join(home, ".claude", "agents", "frontend.md"))
+		pm, err := manifest.ReadProjectManifest(userPath)
+		require.NoError(t, err)
+		assert.NotNil(t, pm.FindAgent("frontend"))
+
+		central, err := manifest.ReadCentralManifest()
+		require.NoError(t, err)
+		assert.Contains(t, central.Deployments, home)
+	})
+
+	t.Run("central manifest failure rolls back", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployAndRecord(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+		before, err := os.ReadFile(filepath.Join(tmpDir, manifest.ProjectManifestFilename))
+		require.NoError(t, err)
+
+		// A file where the workspace directory should be breaks the central manifest
+		home := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(home, "cami-workspace"), nil, 0644))
+		t.Setenv("HOME", home)
+
+		frontend := createTestAgent("frontend", "1.1.0")
+		backend := createTestAgent("backend", "1.0.0")
+		results, err := DeployAndRecord([]*agent.Agent{frontend, backend}, tmpDir, true, sources)
+		require.NoError(t, err)
+		for _, result := range results {
+			assert.False(t, result.Success)
+			assert.Contains(t, result.Message, "Rolled back")
+		}
+
+		content, err := os.ReadFile(AgentPath(tmpDir, frontend))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "version: 1.0.0")
+		_, err = os.Stat(AgentPath(tmpDir, backend))
+		assert.True(t, os.IsNotExist(err))
+
+		after, err := os.ReadFile(filepath.Join(tmpDir, manifest.ProjectManifestFilename))
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("commands deploy alongside agents of the same name", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		cmd, err := agent.ParseCommand([]byte("Review the staged changes.\n"), "/fake/path/commands/review.md")
+		require.NoError(t, err)
+		deployAndRecord(t, tmpDir, createTestAgent("review", "1.0.0"), cmd)
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, ".claude", "commands", "review.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "Review the staged changes.\n", string(content))
+		_, err = os.Stat(filepath.Join(tmpDir, ".claude", "agents", "review.md"))
+		require.NoError(t, err)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, pm.Agents, 2)
+		entry := pm.FindAgent("/review")
+		require.NotNil(t, entry)
+		assert.Equal(t, agent.KindCommand, entry.Kind)
+		assert.Equal(t, manifest.HashContent(content), entry.ContentHash)
+		assert.Empty(t, pm.FindAgent("review").Kind)
+	})
+
+	t.Run("records the bundle an agent was deployed from", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		bundled := *frontend
+		bundled.Bundle = "web-stack"
+		deployAndRecord(t, tmpDir, &bundled)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, "web-stack", pm.FindAgent("frontend").Bundle)
+
+		// Redeploying on its own keeps the bundle
+		deployAndRecord(t, tmpDir, frontend)
+		pm, err = manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, "web-stack", pm.FindAgent("frontend").Bundle)
+	})
+
+	t.Run("skills deploy as a directory hashed as a tree", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		skill := createTestSkill("pdf", "1.0.0", map[string]string{
+			"scripts/extract.py": "print('extract')\n",
+			"reference.md":       "# Reference\n",
+		})
+		deployAndRecord(t, tmpDir, skill)
+
+		skillDir := filepath.Join(tmpDir, ".claude", "skills", "pdf")
+		assert.FileExists(t, filepath.Join(skillDir, "SKILL.md"))
+		content, err := os.ReadFile(filepath.Join(skillDir, "scripts", "extract.py"))
+		require.NoError(t, err)
+		assert.Equal(t, "print('extract')\n", string(content))
+		assert.FileExists(t, filepath.Join(skillDir, "reference.md"))
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry := pm.FindAgent("skill:pdf")
+		require.NotNil(t, entry)
+		assert.Equal(t, agent.KindSkill, entry.Kind)
+		assert.Equal(t, manifest.HashArtifact(skill), entry.ContentHash)
+		treeHash, err := manifest.CalculateTreeHash(skillDir)
+		require.NoError(t, err)
+		assert.Equal(t, treeHash, entry.ContentHash)
+
+		// Redeploying a version without a file removes it
+		updated := createTestSkill("pdf", "1.1.0", map[string]string{"scripts/extract.py": "print('v2')\n"})
+		deployAndRecord(t, tmpDir, updated)
+
+		assert.NoFileExists(t, filepath.Join(skillDir, "reference.md"))
+		pm, err = manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, manifest.HashArtifact(updated), pm.FindAgent("skill:pdf").ContentHash)
+	})
+}
+
+func TestRecordSource(t *testing.T) {
+	sources := []config.AgentSource{
+		{Name: "team", Path: "/src/team", Priority: 10},
+		{Name: "team2", Path: "/src/team2", Priority: 20},
+		{Name: "guild", Path: "/src/guild/", Priority: 30},
+	}
+
+	for _, tt := range []struct {
+		file     string
+		source   string
+		priority int
+	}{
+		{file: "/src/team/frontend.md", source: "team", priority: 10},
+		{file: "/src/team2/frontend.md", source: "team2", priority: 20},
+		{file: "/src/guild/agents/frontend.md", source: "guild", priority: 30},
+		{file: "/src/teamwork/frontend.md", source: "", priority: defaultPriority},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			entry := &manifest.DeployedAgent{}
+			recordSource(entry, &agent.Agent{Name: "frontend", FilePath: tt.file}, sources)
+			assert.Equal(t, tt.source, entry.Source)
+			assert.Equal(t, tt.priority, entry.Priority)
+		})
+	}
+}
+
+func TestRecordFound(t *testing.T) {
+	deployedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tracked := func() *manifest.ProjectManifest {
+		return &manifest.ProjectManifest{
+			Version: "1",
+			State:   manifest.StateCAMIAware,
+			Agents: []manifest.DeployedAgent{
+				{Name: "frontend", Version: "1.0.0", Source: "team", SourcePath: "/src/team/frontend.md", Priority: 10, DeployedAt: deployedAt, ContentHash: "old", CustomOverride: true, Origin: "cami", MCPServers: []string{"github"}, Bundle: "web"},
+				{Name: "frontend", Kind: agent.KindCommand, Version: "1.0.0", Origin: "cami"},
+				{Name: "pdf", Kind: agent.KindSkill, Version: "1.0.0", Origin: "cami"},
+			},
+		}
+	}
+
+	t.Run("keeps what tracked entries record", func(t *testing.T) {
+		pm := tracked()
+		RecordFound(pm, manifest.DeployedAgent{Name: "frontend", Version: "1.1.0", Source: "unknown", DeployedAt: time.Now(), ContentHash: "new", Origin: "external"})
+
+		require.Len(t, pm.Agents, 3)
+		assert.Equal(t, manifest.StateCAMINative, pm.State)
+
+		entry := pm.FindAgent("frontend")
+		assert.Equal(t, "1.1.0", entry.Version)
+		assert.Equal(t, "new", entry.ContentHash)
+		assert.Equal(t, "team", entry.Source, "a found entry without a source keeps the tracked one")
+		assert.Equal(t, "/src/team/frontend.md", entry.SourcePath)
+		assert.Equal(t, 10, entry.Priority)
+		assert.True(t, entry.CustomOverride)
+		assert.Equal(t, "cami", entry.Origin)
+		assert.Equal(t, deployedAt, entry.DeployedAt)
+		assert.Equal(t, []string{"github"}, entry.MCPServers)
+		assert.Equal(t, "web", entry.Bundle)
+
+		assert.NotNil(t, pm.FindAgent("/frontend"))
+		assert.NotNil(t, pm.FindAgent("skill:pdf"))
+	})
+
+	t.Run("relinks sources and adds new entries", func(t *testing.T) {
+		pm := tracked()
+		RecordFound(pm,
+			manifest.DeployedAgent{Name: "frontend", Version: "1.0.0", Source: "guild", SourcePath: "/src/guild/frontend.md", Priority: 30},
+			manifest.DeployedAgent{Name: "backend", Version: "2.0.0", Origin: "external"},
+		)
+
+		require.Len(t, pm.Agents, 4)
+		assert.Equal(t, "guild", pm.FindAgent("frontend").Source)
+		assert.Equal(t, 30, pm.FindAgent("frontend").Priority)
+		assert.Equal(t, "external", pm.FindAgent("backend").Origin)
+	})
+}
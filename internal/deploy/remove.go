@@ -0,0 +1,193 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/spec"
+)
+
+// RemoveResult represents the result of undeploying a single agent
+type RemoveResult struct {
+	Name       string
+	Path       string
+	Success    bool
+	Message    string
+	Dependents []string // Agents still deployed that require this one
+}
+
+// UndeployAgents deletes agents from a project's .claude/agents, commands
+// given as /name from .claude/commands and skills given as skill:name, with
+// their whole directory, from .claude/skills, and drops their entries from
+// the project and central manifests. An agent whose file is already gone
+// still has its manifest entry removed; one whose file can't be deleted
+// keeps its entry, and its MCP servers, so it stays tracked. MCP servers CAMI added to .mcp.json
+// that no remaining agent requires are removed with them. Removing an agent
+// that another deployed agent requires goes ahead, with a warning in its
+// result.
+func UndeployAgents(projectPath string, names []string) ([]*RemoveResult, error) {
+	deployed, err := deployedAgentFiles(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectManifest *manifest.ProjectManifest
+	if _, err := os.Stat(filepath.Join(projectPath, manifest.ProjectManifestFilename)); err == nil {
+		projectManifest, err = manifest.ReadProjectManifest(projectPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var managed map[string]bool
+	if projectManifest != nil {
+		managed = managedMCPServers(projectManifest)
+	}
+
+	var results []*RemoveResult
+	for _, name := range names {
+		result := &RemoveResult{Name: name}
+		results = append(results, result)
+
+		tracked := projectManifest != nil && projectManifest.FindAgent(name) != nil
+
+		path, ok := deployed[name]
+		if !ok {
+			if tracked {
+				projectManifest.RemoveAgent(name)
+				result.Success = true
+				result.Message = "File already removed; dropped manifest entry"
+			} else {
+				result.Message = "Not deployed"
+			}
+			continue
+		}
+		result.Path = path
+
+		if err := removeArtifact(name, path); err != nil {
+			result.Message = fmt.Sprintf("Failed to remove file: %v", err)
+			continue
+		}
+		if tracked {
+			projectManifest.RemoveAgent(name)
+		}
+
+		result.Success = true
+		result.Message = "Removed"
+	}
+
+	warnDependents(results, deployed)
+
+	if projectManifest == nil {
+		return results, nil
+	}
+
+	mcp, err := planMCPServers(projectPath, projectManifest, nil, managed)
+	if err != nil {
+		return results, err
+	}
+	if err := writeMCPConfig(projectPath, mcp); err != nil {
+		return results, err
+	}
+
+	if err := manifest.WriteProjectManifest(projectPath, projectManifest); err != nil {
+		return results, err
+	}
+
+	if err := manifest.UpdateCentralDeployment(projectPath, projectManifest); err != nil {
+		return results, fmt.Errorf("failed to update central manifest: %w", err)
+	}
+
+	return results, nil
+}
+
+// warnDependents notes, on each successful removal, the agents left deployed
+// whose requires frontmatter still names the removed one
+func warnDependents(results []*RemoveResult, deployed map[string]string) {
+	removed := make(map[string]bool)
+	for _, result := range results {
+		if result.Success {
+			removed[result.Name] = true
+		}
+	}
+
+	var remaining []string
+	for id := range deployed {
+		if !removed[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	sort.Strings(remaining)
+
+	for _, id := range remaining {
+		kind, _ := agent.ParseID(id)
+		ag, err := agent.Load(kind, deployed[id])
+		if err != nil {
+			continue
+		}
+		requires, err := ag.Requires()
+		if err != nil {
+			continue
+		}
+		for _, requirement := range requires {
+			req, err := spec.ParseRequirement(requirement)
+			if err != nil || !removed[req.Name] {
+				continue
+			}
+			for _, result := range results {
+				if result.Name == req.Name {
+					result.Dependents = append(result.Dependents, id)
+				}
+			}
+		}
+	}
+
+	for _, result := range results {
+		if len(result.Dependents) > 0 {
+			result.Message += fmt.Sprintf(" (warning: still required by %s)", strings.Join(result.Dependents, ", "))
+		}
+	}
+}
+
+// removeArtifact deletes a deployed artifact's file, or a skill's directory.
+// It is a variable so tests can make a delete fail.
+var removeArtifact = func(id, path string) error {
+	if kind, _ := agent.ParseID(id); kind == agent.KindSkill {
+		return os.RemoveAll(filepath.Dir(path))
+	}
+	return os.Remove(path)
+}
+
+// OrphanedAgents returns the agents a project's manifest tracks that none of
+// the available agents provide any more, sorted by name. Projects without a
+// manifest have no tracked agents.
+func OrphanedAgents(projectPath string, availableAgents []*agent.Agent) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(projectPath, manifest.ProjectManifestFilename)); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	projectManifest, err := manifest.ReadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]bool, len(availableAgents))
+	for _, ag := range availableAgents {
+		available[ag.ID()] = true
+	}
+
+	var orphaned []string
+	for _, entry := range projectManifest.Agents {
+		if id := entry.ID(); !available[id] {
+			orphaned = append(orphaned, id)
+		}
+	}
+	sort.Strings(orphaned)
+
+	return orphaned, nil
+}
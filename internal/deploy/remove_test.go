@@ -0,0 +1,144 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndeployAgents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	t.Run("removes files and manifest entries", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		backend := createTestAgent("backend", "1.0.0")
+		deployTracked(t, tmpDir, frontend, backend)
+
+		results, err := UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Success)
+
+		_, err = os.Stat(AgentPath(tmpDir, frontend))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(AgentPath(tmpDir, backend))
+		assert.NoError(t, err)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Nil(t, pm.FindAgent("frontend"))
+		assert.NotNil(t, pm.FindAgent("backend"))
+
+		central, err := manifest.ReadCentralManifest()
+		require.NoError(t, err)
+		var names []string
+		for _, deployment := range central.Deployments {
+			for _, entry := range deployment.Agents {
+				names = append(names, entry.Name)
+			}
+		}
+		assert.Equal(t, []string{"backend"}, names)
+	})
+
+	t.Run("drops entry when file is already gone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, frontend)
+		require.NoError(t, os.Remove(AgentPath(tmpDir, frontend)))
+
+		results, err := UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		assert.True(t, results[0].Success)
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, pm.Agents)
+	})
+
+	t.Run("removes a skill's whole directory", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		skill := createTestSkill("pdf", "1.0.0", map[string]string{"scripts/extract.py": "print('extract')\n"})
+		deployTracked(t, tmpDir, skill)
+
+		results, err := UndeployAgents(tmpDir, []string{"skill:pdf"})
+		require.NoError(t, err)
+		require.True(t, results[0].Success, results[0].Message)
+
+		assert.NoDirExists(t, filepath.Join(tmpDir, ".claude", "skills", "pdf"))
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, pm.Agents)
+	})
+
+	t.Run("warns when a deployed agent still requires the removed one", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		orchestrator, err := agent.ParseAgent([]byte("---\nname: orchestrator\nrequires:\n  - frontend@^1\n---\nDelegate.\n"), "/fake/path/orchestrator.md")
+		require.NoError(t, err)
+		frontend := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, orchestrator, frontend)
+
+		results, err := UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		require.True(t, results[0].Success)
+		assert.Equal(t, []string{"orchestrator"}, results[0].Dependents)
+		assert.Contains(t, results[0].Message, "still required by orchestrator")
+
+		// Removing both together needs no warning
+		deployTracked(t, tmpDir, orchestrator, frontend)
+		results, err = UndeployAgents(tmpDir, []string{"frontend", "orchestrator"})
+		require.NoError(t, err)
+		assert.Empty(t, results[0].Dependents)
+	})
+
+	t.Run("agent that is not deployed", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		results, err := UndeployAgents(tmpDir, []string{"ghost"})
+		require.NoError(t, err)
+		assert.False(t, results[0].Success)
+		assert.Equal(t, "Not deployed", results[0].Message)
+	})
+
+	t.Run("project without a manifest", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		frontend := createTestAgent("frontend", "1.0.0")
+		_, err := DeployAgent(frontend, tmpDir, false)
+		require.NoError(t, err)
+
+		results, err := UndeployAgents(tmpDir, []string{"frontend"})
+		require.NoError(t, err)
+		assert.True(t, results[0].Success)
+
+		_, err = os.Stat(AgentPath(tmpDir, frontend))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(tmpDir, manifest.ProjectManifestFilename))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestOrphanedAgents(t *testing.T) {
+	t.Run("tracked agents no source provides", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir,
+			createTestAgent("frontend", "1.0.0"),
+			createTestAgent("retired", "1.0.0"),
+			createTestAgent("legacy", "1.0.0"))
+
+		orphaned, err := OrphanedAgents(tmpDir, []*agent.Agent{createTestAgent("frontend", "2.0.0")})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy", "retired"}, orphaned)
+	})
+
+	t.Run("project without a manifest", func(t *testing.T) {
+		orphaned, err := OrphanedAgents(t.TempDir(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, orphaned)
+	})
+}
@@ -0,0 +1,303 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+	"github.com/lando/cami/internal/store"
+)
+
+// SyncAction describes what a sync will do with a tracked agent
+type SyncAction string
+
+const (
+	SyncUpdate         SyncAction = "update"          // Source changed, redeploy
+	SyncUpToDate       SyncAction = "up-to-date"      // Source unchanged
+	SyncSkipCustom     SyncAction = "skip-custom"     // Marked as a custom override
+	SyncSkipModified   SyncAction = "skip-modified"   // Deployed file edited locally
+	SyncSkipNotSource  SyncAction = "skip-no-source"  // No source provides the agent anymore
+	SyncSkipConstraint SyncAction = "skip-constraint" // No available version satisfies the project spec
+)
+
+// SyncItem is the planned sync action for one tracked agent
+type SyncItem struct {
+	Name        string       `json:"name"`
+	Action      SyncAction   `json:"action"`
+	FromVersion string       `json:"from_version,omitempty"`
+	ToVersion   string       `json:"to_version,omitempty"`
+	Reason      string       `json:"reason,omitempty"`
+	Agent       *agent.Agent `json:"-"` // Resolved source agent
+}
+
+// SyncPlan is the set of actions that bring one project up to date
+type SyncPlan struct {
+	ProjectPath string      `json:"project_path"`
+	Items       []*SyncItem `json:"items"`
+}
+
+// Updates returns the items that will be redeployed
+func (p *SyncPlan) Updates() []*SyncItem {
+	var updates []*SyncItem
+	for _, item := range p.Items {
+		if item.Action == SyncUpdate {
+			updates = append(updates, item)
+		}
+	}
+	return updates
+}
+
+// AgentPath returns the path an agent or command is deployed to within a
+// project. For a skill it is the SKILL.md in the skill's directory.
+func AgentPath(targetPath string, ag *agent.Agent) string {
+	dir := filepath.Join(targetPath, ".claude", ag.ArtifactKind().Dir())
+	if ag.ArtifactKind() == agent.KindSkill {
+		return filepath.Join(dir, ag.Name, agent.SkillFile)
+	}
+	return filepath.Join(dir, ag.FileName())
+}
+
+// PlanSync compares every agent in a project's manifest against the versions
+// the resolver offers and decides which ones need redeploying. Constraints in
+// the project spec are honored, so sync never moves an agent outside them.
+func PlanSync(projectPath string, resolver *resolve.Resolver) (*SyncPlan, error) {
+	plan := &SyncPlan{ProjectPath: projectPath}
+
+	// Projects that have never been deployed to have nothing to sync
+	if _, err := os.Stat(filepath.Join(projectPath, manifest.ProjectManifestFilename)); os.IsNotExist(err) {
+		return plan, nil
+	}
+
+	projectManifest, err := manifest.ReadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	projectSpec := &spec.Spec{}
+	if spec.Exists(projectPath) {
+		if projectSpec, err = spec.Read(projectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range projectManifest.Agents {
+		id := entry.ID()
+		item := &SyncItem{
+			Name:        id,
+			FromVersion: entry.Version,
+		}
+		plan.Items = append(plan.Items, item)
+
+		if entry.CustomOverride {
+			item.Action = SyncSkipCustom
+			item.Reason = "marked as custom override"
+			continue
+		}
+
+		req := spec.Requirement{Name: id}
+		if pinned := projectSpec.Find(id); pinned != nil {
+			req = *pinned
+		}
+
+		sourceAgent, err := resolver.Resolve(req)
+		if errors.Is(err, resolve.ErrNotFound) {
+			item.Action = SyncSkipNotSource
+			item.Reason = "no configured source provides this agent"
+			continue
+		}
+		if err != nil {
+			item.Action = SyncSkipConstraint
+			item.Reason = err.Error()
+			continue
+		}
+		item.Agent = sourceAgent
+		item.ToVersion = sourceAgent.Version
+
+		sourceHash := manifest.HashArtifact(sourceAgent)
+		if entry.ContentHash == sourceHash {
+			item.Action = SyncUpToDate
+			continue
+		}
+
+		// Refuse to clobber edits made to the deployed file since it was deployed
+		if entry.ContentHash != "" {
+			deployedHash, err := manifest.CalculateArtifactHash(sourceAgent.ArtifactKind(), AgentPath(projectPath, sourceAgent))
+			if err == nil && deployedHash != entry.ContentHash && deployedHash != sourceHash {
+				item.Action = SyncSkipModified
+				item.Reason = "deployed file has local changes"
+				continue
+			}
+		}
+
+		item.Action = SyncUpdate
+	}
+
+	sort.Slice(plan.Items, func(i, j int) bool {
+		return plan.Items[i].Name < plan.Items[j].Name
+	})
+
+	return plan, nil
+}
+
+// ApplySync redeploys the agents a plan marks for update and records the new
+// versions and hashes in the project and central manifests, all in one
+// transaction
+func ApplySync(plan *SyncPlan) ([]*Result, error) {
+	var agents []*agent.Agent
+	for _, item := range plan.Updates() {
+		agents = append(agents, item.Agent)
+	}
+
+	if len(agents) == 0 {
+		return nil, nil
+	}
+
+	results := deployTransaction(agents, plan.ProjectPath, true, func(projectManifest *manifest.ProjectManifest, results []*Result) error {
+		now := time.Now()
+		for _, result := range results {
+			if !result.Success {
+				continue
+			}
+
+			entry := projectManifest.FindAgent(result.Agent.ID())
+			if entry == nil {
+				continue
+			}
+
+			if err := recordDeployed(entry, result.Agent, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return results, nil
+}
+
+// recordDeployed updates a manifest entry to describe the source content just
+// deployed for an agent, and snapshots that content as the base for future
+// three-way merges. Skills aren't merged, so they aren't snapshotted.
+// DeployedAt only moves when the deployed content changes.
+func recordDeployed(entry *manifest.DeployedAgent, ag *agent.Agent, now time.Time) error {
+	content := []byte(ag.FullContent())
+
+	if ag.ArtifactKind() != agent.KindSkill {
+		if _, err := store.Put(content); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", ag.Name, err)
+		}
+	}
+
+	describeDeployed(entry, ag, content, now)
+	return nil
+}
+
+// describeDeployed fills in a manifest entry for an agent deployed with
+// content, without snapshotting it. A skill's content is its SKILL.md, and
+// its content hash covers its whole directory. The bundle an agent was
+// deployed from is kept when it is later deployed on its own.
+func describeDeployed(entry *manifest.DeployedAgent, ag *agent.Agent, content []byte, now time.Time) {
+	contentHash := manifest.HashContent(content)
+	if ag.ArtifactKind() == agent.KindSkill {
+		contentHash = manifest.HashArtifact(ag)
+	}
+	metadataHash, _ := manifest.HashMetadata(content)
+
+	entry.Name = ag.Name
+	entry.Kind = entryKind(ag)
+	entry.Version = ag.Version
+	entry.SourcePath = ag.FilePath
+	if entry.ContentHash != contentHash || entry.DeployedAt.IsZero() {
+		entry.DeployedAt = now
+	}
+	entry.ContentHash = contentHash
+	entry.MetadataHash = metadataHash
+	entry.NeedsUpgrade = false
+	entry.Commit = SourceCommit(ag)
+	if ag.Source != "" {
+		entry.Source = ag.Source
+	}
+	if ag.Bundle != "" {
+		entry.Bundle = ag.Bundle
+	}
+}
+
+// SourceCommit returns the commit an agent was loaded from: its git tag if
+// it came from one, otherwise the HEAD of the source it lives in. It returns
+// "" for agents outside a git repository.
+func SourceCommit(ag *agent.Agent) string {
+	ref := ag.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	commit, err := git.Commit(filepath.Dir(ag.FilePath), ref)
+	if err != nil {
+		return ""
+	}
+	return commit
+}
+
+// TrackedProjects returns the projects CAMI deploys to: configured locations
+// plus any project recorded in the central manifest, deduplicated by path
+func TrackedProjects(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	var projects []string
+
+	add := func(path string) {
+		absPath, err := filepath.Abs(path)
+		if err != nil || seen[absPath] {
+			return
+		}
+		seen[absPath] = true
+		projects = append(projects, absPath)
+	}
+
+	for _, loc := range cfg.Locations {
+		add(loc.Path)
+	}
+
+	if central, err := manifest.ReadCentralManifest(); err == nil {
+		var paths []string
+		for path := range central.Deployments {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if _, err := os.Stat(path); err == nil {
+				add(path)
+			}
+		}
+	}
+
+	return projects
+}
+
+// ResolveProjectPath resolves a location name or path to a project path.
+// The name "user" resolves to the user scope, the home directory whose
+// .claude/agents Claude Code loads in every project.
+func ResolveProjectPath(cfg *config.Config, nameOrPath string) (string, error) {
+	if nameOrPath == config.UserScopeName {
+		return config.ExpandUserScope(nameOrPath)
+	}
+
+	for _, loc := range cfg.Locations {
+		if loc.Name == nameOrPath {
+			return loc.Path, nil
+		}
+	}
+
+	if err := ValidateTargetPath(nameOrPath); err != nil {
+		return "", fmt.Errorf("%s is not a configured location or valid path: %w", nameOrPath, err)
+	}
+
+	return filepath.Abs(nameOrPath)
+}
@@ -0,0 +1,314 @@
+package deploy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deployTracked deploys agents to a project and records them in its manifest
+func deployTracked(t *testing.T, projectPath string, agents ...*agent.Agent) {
+	t.Helper()
+
+	_, err := DeployAgents(agents, projectPath, true)
+	require.NoError(t, err)
+
+	pm := &manifest.ProjectManifest{
+		Version: "2",
+		State:   manifest.StateCAMINative,
+	}
+	for _, ag := range agents {
+		pm.Agents = append(pm.Agents, manifest.DeployedAgent{
+			Name:        ag.Name,
+			Kind:        entryKind(ag),
+			Version:     ag.Version,
+			SourcePath:  ag.FilePath,
+			DeployedAt:  time.Now(),
+			ContentHash: manifest.HashArtifact(ag),
+		})
+	}
+	require.NoError(t, manifest.WriteProjectManifest(projectPath, pm))
+}
+
+func findItem(plan *SyncPlan, name string) *SyncItem {
+	for _, item := range plan.Items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func TestPlanSync(t *testing.T) {
+	t.Run("plans update when source changed", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		updated := createTestAgent("frontend", "1.1.0")
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{updated}))
+		require.NoError(t, err)
+
+		require.Len(t, plan.Items, 1)
+		item := plan.Items[0]
+		assert.Equal(t, SyncUpdate, item.Action)
+		assert.Equal(t, "1.0.0", item.FromVersion)
+		assert.Equal(t, "1.1.0", item.ToVersion)
+		assert.Len(t, plan.Updates(), 1)
+	})
+
+	t.Run("up to date when source unchanged", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, ag)
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{ag}))
+		require.NoError(t, err)
+
+		require.Len(t, plan.Items, 1)
+		assert.Equal(t, SyncUpToDate, plan.Items[0].Action)
+		assert.Empty(t, plan.Updates())
+	})
+
+	t.Run("skips custom overrides", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		pm.Agents[0].CustomOverride = true
+		require.NoError(t, manifest.WriteProjectManifest(tmpDir, pm))
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{createTestAgent("frontend", "2.0.0")}))
+		require.NoError(t, err)
+
+		assert.Equal(t, SyncSkipCustom, plan.Items[0].Action)
+	})
+
+	t.Run("skips locally modified files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := createTestAgent("frontend", "1.0.0")
+		deployTracked(t, tmpDir, ag)
+
+		agentPath := AgentPath(tmpDir, ag)
+		require.NoError(t, os.WriteFile(agentPath, []byte("---\nname: frontend\n---\nlocal edits\n"), 0644))
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{createTestAgent("frontend", "2.0.0")}))
+		require.NoError(t, err)
+
+		assert.Equal(t, SyncSkipModified, plan.Items[0].Action)
+	})
+
+	t.Run("skills with edited supporting files are locally modified", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		skill := createTestSkill("pdf", "1.0.0", map[string]string{"scripts/run.sh": "echo run\n"})
+		deployTracked(t, tmpDir, skill)
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{skill}))
+		require.NoError(t, err)
+		require.Len(t, plan.Items, 1)
+		assert.Equal(t, "skill:pdf", plan.Items[0].Name)
+		assert.Equal(t, SyncUpToDate, plan.Items[0].Action)
+
+		runPath := filepath.Join(filepath.Dir(AgentPath(tmpDir, skill)), "scripts", "run.sh")
+		require.NoError(t, os.WriteFile(runPath, []byte("echo local\n"), 0644))
+
+		updated := createTestSkill("pdf", "1.1.0", map[string]string{"scripts/run.sh": "echo v2\n"})
+		plan, err = PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{updated}))
+		require.NoError(t, err)
+		assert.Equal(t, SyncSkipModified, plan.Items[0].Action)
+	})
+
+	t.Run("skips agents with no source", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"))
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, SyncSkipNotSource, plan.Items[0].Action)
+		assert.Empty(t, plan.Updates())
+	})
+
+	t.Run("honors project spec constraints", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"), createTestAgent("backend", "1.0.0"))
+		require.NoError(t, spec.Write(tmpDir, &spec.Spec{Agents: []spec.Requirement{
+			{Name: "frontend", Version: "^1"},
+			{Name: "backend", Version: "^1"},
+		}}))
+
+		available := []*agent.Agent{createTestAgent("frontend", "2.0.0"), createTestAgent("backend", "1.1.0")}
+		plan, err := PlanSync(tmpDir, resolve.New(nil, available))
+		require.NoError(t, err)
+
+		frontend := findItem(plan, "frontend")
+		assert.Equal(t, SyncSkipConstraint, frontend.Action)
+		assert.Contains(t, frontend.Reason, "^1")
+		assert.Equal(t, SyncUpdate, findItem(plan, "backend").Action)
+	})
+
+	t.Run("project without manifest has nothing to sync", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{createTestAgent("frontend", "1.0.0")}))
+		require.NoError(t, err)
+		assert.Empty(t, plan.Items)
+	})
+
+	t.Run("items sorted by name", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("zeta", "1.0.0"), createTestAgent("alpha", "1.0.0"))
+
+		plan, err := PlanSync(tmpDir, resolve.New(nil, nil))
+		require.NoError(t, err)
+
+		require.Len(t, plan.Items, 2)
+		assert.Equal(t, "alpha", plan.Items[0].Name)
+		assert.Equal(t, "zeta", plan.Items[1].Name)
+	})
+}
+
+func TestApplySync(t *testing.T) {
+	t.Run("redeploys and records new version", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		deployTracked(t, tmpDir, createTestAgent("frontend", "1.0.0"), createTestAgent("backend", "1.0.0"))
+
+		updated := createTestAgent("frontend", "1.1.0")
+		updated.Source = "team-agents"
+		plan, err := PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{updated, createTestAgent("backend", "1.0.0")}))
+		require.NoError(t, err)
+
+		results, err := ApplySync(plan)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Success)
+
+		content, err := os.ReadFile(AgentPath(tmpDir, updated))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "version: 1.1.0")
+
+		pm, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		entry := pm.FindAgent("frontend")
+		require.NotNil(t, entry)
+		assert.Equal(t, "1.1.0", entry.Version)
+		assert.Equal(t, "team-agents", entry.Source)
+		assert.Equal(t, manifest.HashContent([]byte(updated.FullContent())), entry.ContentHash)
+		assert.Equal(t, "1.0.0", pm.FindAgent("backend").Version)
+
+		// A second plan finds nothing left to do
+		plan, err = PlanSync(tmpDir, resolve.New(nil, []*agent.Agent{updated, createTestAgent("backend", "1.0.0")}))
+		require.NoError(t, err)
+		assert.Empty(t, plan.Updates())
+
+		central, err := manifest.ReadCentralManifest()
+		require.NoError(t, err)
+		absPath, err := filepath.Abs(tmpDir)
+		require.NoError(t, err)
+		assert.Contains(t, central.Deployments, absPath)
+	})
+
+	t.Run("nothing to apply", func(t *testing.T) {
+		plan := &SyncPlan{ProjectPath: t.TempDir()}
+
+		results, err := ApplySync(plan)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestTrackedProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	configured := t.TempDir()
+	recorded := t.TempDir()
+	missing := filepath.Join(t.TempDir(), "gone")
+
+	central := &manifest.CentralManifest{
+		Deployments: map[string]manifest.ProjectDeployment{
+			recorded:   {},
+			configured: {},
+			missing:    {},
+		},
+	}
+	require.NoError(t, manifest.WriteCentralManifest(central))
+
+	cfg := &config.Config{
+		Locations: []config.DeployLocation{{Name: "app", Path: configured}},
+	}
+
+	projects := TrackedProjects(cfg)
+	assert.Equal(t, []string{configured, recorded}, projects)
+
+	path, err := ResolveProjectPath(cfg, "app")
+	require.NoError(t, err)
+	assert.Equal(t, configured, path)
+
+	_, err = ResolveProjectPath(cfg, missing)
+	assert.Error(t, err)
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	path, err = ResolveProjectPath(cfg, config.UserScopeName)
+	require.NoError(t, err)
+	assert.Equal(t, home, path)
+}
+
+func TestSourceCommit(t *testing.T) {
+	t.Run("outside a git repository", func(t *testing.T) {
+		ag := createTestAgent("frontend", "1.0.0")
+		ag.FilePath = filepath.Join(t.TempDir(), "frontend.md")
+
+		assert.Equal(t, "", SourceCommit(ag))
+	})
+
+	t.Run("head or tag of the source", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git not installed")
+		}
+		t.Setenv("GIT_AUTHOR_NAME", "Test")
+		t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+		t.Setenv("GIT_COMMITTER_NAME", "Test")
+		t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+		repo := t.TempDir()
+		commit := func(content, tag string) string {
+			require.NoError(t, os.WriteFile(filepath.Join(repo, "frontend.md"), []byte(content), 0644))
+			_, err := git.Run(repo, "add", "-A")
+			require.NoError(t, err)
+			_, err = git.Run(repo, "commit", "-q", "-m", "update")
+			require.NoError(t, err)
+			if tag != "" {
+				_, err = git.Run(repo, "tag", tag)
+				require.NoError(t, err)
+			}
+			sha, err := git.Commit(repo, "HEAD")
+			require.NoError(t, err)
+			return sha
+		}
+
+		_, err := git.Run(repo, "init", "-q")
+		require.NoError(t, err)
+		tagged := commit("v1\n", "v1.0.0")
+		head := commit("v2\n", "")
+
+		ag := createTestAgent("frontend", "2.0.0")
+		ag.FilePath = filepath.Join(repo, "frontend.md")
+		assert.Equal(t, head, SourceCommit(ag))
+
+		ag.Ref = "v1.0.0"
+		assert.Equal(t, tagged, SourceCommit(ag))
+	})
+}
@@ -0,0 +1,135 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// transaction stages files in a temporary directory inside a project's
+// .claude directory and moves them into place together by rename. Files it
+// replaces are set aside rather than deleted, so a failed commit, or a
+// failure after it, can be rolled back until the transaction is closed.
+type transaction struct {
+	dir   string
+	files []*stagedFile
+}
+
+// stagedFile is one file written, or removed, by a transaction
+type stagedFile struct {
+	target    string
+	staged    string
+	removal   bool   // Target is removed; nothing is staged in its place
+	backup    string // Where the replaced file was set aside; "" if there was none
+	committed bool   // Staged file has been moved to target
+}
+
+// beginTransaction starts a transaction for files within a project
+func beginTransaction(projectPath string) (*transaction, error) {
+	claudeDir := filepath.Join(projectPath, ".claude")
+	if err := os.MkdirAll(claudeDir, 0755); err != nil {
+		return nil, err
+	}
+
+	// Staging next to the targets keeps every rename on one filesystem
+	dir, err := os.MkdirTemp(claudeDir, ".cami-staging-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{dir: dir}, nil
+}
+
+// stage writes content that commit will move to target
+func (tx *transaction) stage(target string, content []byte) error {
+	staged := filepath.Join(tx.dir, fmt.Sprintf("%d.staged", len(tx.files)))
+	if err := os.WriteFile(staged, content, 0644); err != nil {
+		return err
+	}
+
+	tx.files = append(tx.files, &stagedFile{target: target, staged: staged})
+	return nil
+}
+
+// remove stages the removal of target, which commit sets aside with the
+// files it replaces
+func (tx *transaction) remove(target string) {
+	staged := filepath.Join(tx.dir, fmt.Sprintf("%d.removed", len(tx.files)))
+	tx.files = append(tx.files, &stagedFile{target: target, staged: staged, removal: true})
+}
+
+// commit moves every staged file into place. If any move fails, the files
+// already moved are rolled back before the error is returned.
+func (tx *transaction) commit() error {
+	for _, f := range tx.files {
+		if err := tx.commitFile(f); err != nil {
+			if rbErr := tx.rollback(); rbErr != nil {
+				return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *transaction) commitFile(f *stagedFile) error {
+	if err := os.MkdirAll(filepath.Dir(f.target), 0755); err != nil {
+		return err
+	}
+
+	if info, err := os.Lstat(f.target); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", f.target)
+		}
+
+		backup := f.staged + ".orig"
+		if err := os.Rename(f.target, backup); err != nil {
+			return err
+		}
+		f.backup = backup
+	}
+
+	if f.removal {
+		f.committed = true
+		return nil
+	}
+	if err := os.Rename(f.staged, f.target); err != nil {
+		return err
+	}
+	f.committed = true
+
+	return nil
+}
+
+// rollback restores every file the transaction replaced and removes the
+// ones it created, newest first
+func (tx *transaction) rollback() error {
+	var errs []error
+	for i := len(tx.files) - 1; i >= 0; i-- {
+		f := tx.files[i]
+
+		if f.committed {
+			if err := os.Remove(f.target); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+				continue
+			}
+			f.committed = false
+		}
+
+		if f.backup != "" {
+			if err := os.Rename(f.backup, f.target); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			f.backup = ""
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close removes the staging directory, along with the replaced files set
+// aside by a commit
+func (tx *transaction) close() {
+	os.RemoveAll(tx.dir)
+}
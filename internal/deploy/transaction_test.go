@@ -0,0 +1,140 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	t.Run("commit moves staged files into place", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		existing := filepath.Join(tmpDir, "existing.md")
+		created := filepath.Join(tmpDir, "nested", "created.md")
+		require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+
+		tx, err := beginTransaction(tmpDir)
+		require.NoError(t, err)
+		require.NoError(t, tx.stage(existing, []byte("new")))
+		require.NoError(t, tx.stage(created, []byte("created")))
+
+		// Nothing changes until commit
+		content, err := os.ReadFile(existing)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+
+		require.NoError(t, tx.commit())
+		tx.close()
+
+		content, err = os.ReadFile(existing)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+		content, err = os.ReadFile(created)
+		require.NoError(t, err)
+		assert.Equal(t, "created", string(content))
+
+		// The staging directory is cleaned up
+		entries, err := os.ReadDir(filepath.Join(tmpDir, ".claude"))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("rollback after commit restores replaced files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		existing := filepath.Join(tmpDir, "existing.md")
+		created := filepath.Join(tmpDir, "created.md")
+		require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+
+		tx, err := beginTransaction(tmpDir)
+		require.NoError(t, err)
+		defer tx.close()
+		require.NoError(t, tx.stage(existing, []byte("new")))
+		require.NoError(t, tx.stage(created, []byte("created")))
+		require.NoError(t, tx.commit())
+
+		require.NoError(t, tx.rollback())
+
+		content, err := os.ReadFile(existing)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		_, err = os.Stat(created)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("failed commit rolls back files already moved", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		first := filepath.Join(tmpDir, "first.md")
+		blocked := filepath.Join(tmpDir, "blocked.md")
+		require.NoError(t, os.WriteFile(first, []byte("old"), 0644))
+		require.NoError(t, os.Mkdir(blocked, 0755))
+
+		tx, err := beginTransaction(tmpDir)
+		require.NoError(t, err)
+		defer tx.close()
+		require.NoError(t, tx.stage(first, []byte("new")))
+		require.NoError(t, tx.stage(blocked, []byte("new")))
+
+		assert.Error(t, tx.commit())
+
+		content, err := os.ReadFile(first)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		assert.DirExists(t, blocked)
+	})
+
+	t.Run("removed files are restored by rollback", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		stale := filepath.Join(tmpDir, "stale.md")
+		require.NoError(t, os.WriteFile(stale, []byte("stale"), 0644))
+
+		tx, err := beginTransaction(tmpDir)
+		require.NoError(t, err)
+		defer tx.close()
+		tx.remove(stale)
+		require.NoError(t, tx.commit())
+
+		_, err = os.Stat(stale)
+		assert.True(t, os.IsNotExist(err))
+
+		require.NoError(t, tx.rollback())
+
+		content, err := os.ReadFile(stale)
+		require.NoError(t, err)
+		assert.Equal(t, "stale", string(content))
+	})
+}
+
+func TestDeployAgentsRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	existing := createTestAgent("existing", "1.0.0")
+	_, err := DeployAgent(existing, tmpDir, false)
+	require.NoError(t, err)
+
+	// A directory in place of an agent file makes its commit fail
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, ".claude", "agents", "blocked.md"), 0755))
+
+	agents := []*agent.Agent{
+		createTestAgent("existing", "2.0.0"),
+		createTestAgent("new", "1.0.0"),
+		createTestAgent("blocked", "1.0.0"),
+	}
+
+	results, err := DeployAgents(agents, tmpDir, true)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Message, "Rolled back")
+	}
+
+	content, err := os.ReadFile(AgentPath(tmpDir, existing))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "version: 1.0.0")
+	_, err = os.Stat(filepath.Join(tmpDir, ".claude", "agents", "new.md"))
+	assert.True(t, os.IsNotExist(err))
+}
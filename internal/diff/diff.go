@@ -0,0 +1,130 @@
+package diff
+
+import "strings"
+
+// Op is the kind of change a line represents
+type Op int
+
+const (
+	Equal  Op = iota // Line present in both versions
+	Delete           // Line only in the old version
+	Insert           // Line only in the new version
+)
+
+// Edit is a single line of a line-based diff
+type Edit struct {
+	Op   Op
+	Line string // Line including its terminator, if any
+}
+
+// SplitLines splits text into lines, keeping each line's terminator so the
+// text can be reassembled exactly
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Lines returns the edits that turn a into b
+func Lines(a, b []string) []Edit {
+	var edits []Edit
+	i, j := 0, 0
+
+	for _, m := range Match(a, b) {
+		for ; i < m[0]; i++ {
+			edits = append(edits, Edit{Op: Delete, Line: a[i]})
+		}
+		for ; j < m[1]; j++ {
+			edits = append(edits, Edit{Op: Insert, Line: b[j]})
+		}
+		edits = append(edits, Edit{Op: Equal, Line: a[i]})
+		i++
+		j++
+	}
+
+	for ; i < len(a); i++ {
+		edits = append(edits, Edit{Op: Delete, Line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, Edit{Op: Insert, Line: b[j]})
+	}
+
+	return edits
+}
+
+// Match returns the index pairs of a longest common subsequence of a and b,
+// in increasing order
+func Match(a, b []string) [][2]int {
+	// Common prefix and suffix match trivially; only diff the middle
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var matches [][2]int
+	for i := 0; i < prefix; i++ {
+		matches = append(matches, [2]int{i, i})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	for _, m := range lcs(midA, midB) {
+		matches = append(matches, [2]int{m[0] + prefix, m[1] + prefix})
+	}
+
+	for s := suffix; s > 0; s-- {
+		matches = append(matches, [2]int{len(a) - s, len(b) - s})
+	}
+
+	return matches
+}
+
+// lcs computes a longest common subsequence with the classic dynamic
+// programming table. Agent files are small, so quadratic space is fine.
+func lcs(a, b []string) [][2]int {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+
+	// table[i][j] is the LCS length of a[i:] and b[j:]
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	var matches [][2]int
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			matches = append(matches, [2]int{i, j})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLines(t *testing.T) {
+	t.Run("keeps terminators", func(t *testing.T) {
+		assert.Equal(t, []string{"a\n", "b\n"}, SplitLines("a\nb\n"))
+	})
+
+	t.Run("final line without newline", func(t *testing.T) {
+		assert.Equal(t, []string{"a\n", "b"}, SplitLines("a\nb"))
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		assert.Nil(t, SplitLines(""))
+	})
+
+	t.Run("reassembles exactly", func(t *testing.T) {
+		text := "one\r\ntwo\n\nthree"
+		assert.Equal(t, text, strings.Join(SplitLines(text), ""))
+	})
+}
+
+func TestLines(t *testing.T) {
+	t.Run("identical", func(t *testing.T) {
+		a := SplitLines("a\nb\n")
+		edits := Lines(a, a)
+
+		assert.Len(t, edits, 2)
+		for _, e := range edits {
+			assert.Equal(t, Equal, e.Op)
+		}
+	})
+
+	t.Run("insert and delete", func(t *testing.T) {
+		a := SplitLines("a\nb\nc\n")
+		b := SplitLines("a\nc\nd\n")
+
+		edits := Lines(a, b)
+
+		assert.Equal(t, []Edit{
+			{Op: Equal, Line: "a\n"},
+			{Op: Delete, Line: "b\n"},
+			{Op: Equal, Line: "c\n"},
+			{Op: Insert, Line: "d\n"},
+		}, edits)
+	})
+
+	t.Run("replacement", func(t *testing.T) {
+		edits := Lines(SplitLines("x\n"), SplitLines("y\n"))
+
+		assert.Equal(t, []Edit{
+			{Op: Delete, Line: "x\n"},
+			{Op: Insert, Line: "y\n"},
+		}, edits)
+	})
+}
+
+func TestMatch(t *testing.T) {
+	a := SplitLines("a\nb\nc\nd\ne\n")
+	b := SplitLines("a\nc\nx\nd\ne\n")
+
+	assert.Equal(t, [][2]int{{0, 0}, {2, 1}, {3, 3}, {4, 4}}, Match(a, b))
+}
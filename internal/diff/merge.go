@@ -0,0 +1,132 @@
+package diff
+
+import "strings"
+
+// Conflict marker lines written around unresolved hunks
+const (
+	MarkerLocal  = "<<<<<<< local"
+	MarkerBase   = "||||||| base"
+	MarkerSep    = "======="
+	MarkerSource = ">>>>>>> source"
+)
+
+// MergeResult is the outcome of a three-way merge
+type MergeResult struct {
+	Text      string // Merged text, with conflict markers if Conflicts > 0
+	Conflicts int    // Number of conflicting hunks
+}
+
+// Clean reports whether the merge finished without conflicts
+func (r *MergeResult) Clean() bool {
+	return r.Conflicts == 0
+}
+
+// Merge3 merges the changes made in local and source since base. Hunks
+// changed on only one side are taken from that side; hunks changed
+// identically on both sides are taken once; anything else is a conflict
+// wrapped in diff3-style markers.
+func Merge3(base, local, source string) *MergeResult {
+	baseLines := SplitLines(base)
+	localLines := SplitLines(local)
+	sourceLines := SplitLines(source)
+
+	localMatch := matchIndex(Match(baseLines, localLines))
+	sourceMatch := matchIndex(Match(baseLines, sourceLines))
+
+	var out strings.Builder
+	result := &MergeResult{}
+
+	b, l, s := 0, 0, 0
+	for {
+		// Find the next base line both sides kept; everything before it is a chunk
+		next := b
+		for next < len(baseLines) {
+			lm, lok := localMatch[next]
+			sm, sok := sourceMatch[next]
+			if lok && sok && lm >= l && sm >= s {
+				break
+			}
+			next++
+		}
+
+		localEnd, sourceEnd := len(localLines), len(sourceLines)
+		if next < len(baseLines) {
+			localEnd, sourceEnd = localMatch[next], sourceMatch[next]
+		}
+
+		if next > b || localEnd > l || sourceEnd > s {
+			mergeChunk(&out, result, baseLines[b:next], localLines[l:localEnd], sourceLines[s:sourceEnd])
+		}
+
+		if next >= len(baseLines) {
+			break
+		}
+
+		// Stable line, unchanged on both sides
+		out.WriteString(baseLines[next])
+		b, l, s = next+1, localEnd+1, sourceEnd+1
+	}
+
+	result.Text = out.String()
+	return result
+}
+
+// mergeChunk resolves one unstable region between stable lines
+func mergeChunk(out *strings.Builder, result *MergeResult, base, local, source []string) {
+	switch {
+	case equalLines(local, base):
+		writeLines(out, source)
+	case equalLines(source, base), equalLines(local, source):
+		writeLines(out, local)
+	default:
+		result.Conflicts++
+		writeMarker(out, MarkerLocal)
+		writeTerminated(out, local)
+		writeMarker(out, MarkerBase)
+		writeTerminated(out, base)
+		writeMarker(out, MarkerSep)
+		writeTerminated(out, source)
+		writeMarker(out, MarkerSource)
+	}
+}
+
+// matchIndex maps the first index of each match pair to the second
+func matchIndex(matches [][2]int) map[int]int {
+	index := make(map[int]int, len(matches))
+	for _, m := range matches {
+		index[m[0]] = m[1]
+	}
+	return index
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeTerminated writes lines, making sure the last one ends in a newline so
+// a following marker starts on its own line
+func writeTerminated(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
+
+func writeMarker(out *strings.Builder, marker string) {
+	out.WriteString(marker)
+	out.WriteString("\n")
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "---\nname: frontend\nversion: 1.0.0\n---\n# Frontend\n\nBuild UIs.\n\n## Rules\n- Be accessible\n"
+
+	t.Run("no changes", func(t *testing.T) {
+		result := Merge3(base, base, base)
+
+		assert.True(t, result.Clean())
+		assert.Equal(t, base, result.Text)
+	})
+
+	t.Run("only source changed", func(t *testing.T) {
+		source := "---\nname: frontend\nversion: 1.1.0\n---\n# Frontend\n\nBuild UIs.\n\n## Rules\n- Be accessible\n"
+
+		result := Merge3(base, base, source)
+
+		assert.True(t, result.Clean())
+		assert.Equal(t, source, result.Text)
+	})
+
+	t.Run("only local changed", func(t *testing.T) {
+		local := base + "- Use our design system\n"
+
+		result := Merge3(base, local, base)
+
+		assert.True(t, result.Clean())
+		assert.Equal(t, local, result.Text)
+	})
+
+	t.Run("non-overlapping changes merge cleanly", func(t *testing.T) {
+		local := base + "- Use our design system\n"
+		source := "---\nname: frontend\nversion: 1.1.0\n---\n# Frontend\n\nBuild UIs.\n\n## Rules\n- Be accessible\n"
+
+		result := Merge3(base, local, source)
+
+		assert.True(t, result.Clean())
+		assert.Equal(t, "---\nname: frontend\nversion: 1.1.0\n---\n# Frontend\n\nBuild UIs.\n\n## Rules\n- Be accessible\n- Use our design system\n", result.Text)
+	})
+
+	t.Run("identical changes on both sides", func(t *testing.T) {
+		changed := "---\nname: frontend\nversion: 2.0.0\n---\n# Frontend\n\nBuild UIs.\n\n## Rules\n- Be accessible\n"
+
+		result := Merge3(base, changed, changed)
+
+		assert.True(t, result.Clean())
+		assert.Equal(t, changed, result.Text)
+	})
+
+	t.Run("overlapping changes conflict", func(t *testing.T) {
+		local := "---\nname: frontend\nversion: 1.0.0\n---\n# Frontend\n\nBuild web UIs.\n\n## Rules\n- Be accessible\n"
+		source := "---\nname: frontend\nversion: 1.0.0\n---\n# Frontend\n\nBuild mobile UIs.\n\n## Rules\n- Be accessible\n"
+
+		result := Merge3(base, local, source)
+
+		assert.False(t, result.Clean())
+		assert.Equal(t, 1, result.Conflicts)
+		assert.Equal(t, "---\nname: frontend\nversion: 1.0.0\n---\n# Frontend\n\n"+
+			"<<<<<<< local\nBuild web UIs.\n"+
+			"||||||| base\nBuild UIs.\n"+
+			"=======\nBuild mobile UIs.\n"+
+			">>>>>>> source\n"+
+			"\n## Rules\n- Be accessible\n", result.Text)
+	})
+
+	t.Run("conflict at end without trailing newline", func(t *testing.T) {
+		result := Merge3("a\nb", "a\nc", "a\nd")
+
+		assert.Equal(t, 1, result.Conflicts)
+		assert.Equal(t, "a\n<<<<<<< local\nc\n||||||| base\nb\n=======\nd\n>>>>>>> source\n", result.Text)
+	})
+
+	t.Run("both sides append different lines", func(t *testing.T) {
+		result := Merge3("a\n", "a\nlocal\n", "a\nsource\n")
+
+		assert.Equal(t, 1, result.Conflicts)
+		assert.Contains(t, result.Text, "<<<<<<< local\nlocal\n||||||| base\n=======\nsource\n>>>>>>> source\n")
+	})
+}
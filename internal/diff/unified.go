@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hunk is one group of nearby changes with surrounding context. Start lines
+// are 1-based; a hunk that adds to or removes everything from an empty side
+// starts at the line before, as in unified diff output.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Edits              []Edit
+}
+
+// Hunks groups the edits that turn a into b into hunks, keeping context
+// unchanged lines around each change
+func Hunks(a, b []string, context int) []Hunk {
+	edits := Lines(a, b)
+
+	// Line positions in a and b before each edit
+	oldPos := make([]int, len(edits))
+	newPos := make([]int, len(edits))
+	var ranges [][2]int
+	i, j := 0, 0
+
+	for k, edit := range edits {
+		oldPos[k], newPos[k] = i, j
+
+		switch edit.Op {
+		case Equal:
+			i++
+			j++
+			continue
+		case Delete:
+			i++
+		case Insert:
+			j++
+		}
+
+		start, end := max(k-context, 0), min(k+context+1, len(edits))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	var hunks []Hunk
+	for _, r := range ranges {
+		h := Hunk{
+			OldStart: oldPos[r[0]] + 1,
+			NewStart: newPos[r[0]] + 1,
+			Edits:    edits[r[0]:r[1]],
+		}
+		for _, edit := range h.Edits {
+			if edit.Op != Insert {
+				h.OldLines++
+			}
+			if edit.Op != Delete {
+				h.NewLines++
+			}
+		}
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		hunks = append(hunks, h)
+	}
+
+	return hunks
+}
+
+// String renders the hunk in unified diff format
+func (h Hunk) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+
+	for _, edit := range h.Edits {
+		switch edit.Op {
+		case Equal:
+			b.WriteByte(' ')
+		case Delete:
+			b.WriteByte('-')
+		case Insert:
+			b.WriteByte('+')
+		}
+		b.WriteString(edit.Line)
+		if !strings.HasSuffix(edit.Line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+
+	return b.String()
+}
+
+// Unified renders a unified diff of a and b with three lines of context, or
+// "" if they are equal
+func Unified(fromName, toName, a, b string) string {
+	return Format(fromName, toName, Hunks(SplitLines(a), SplitLines(b), 3))
+}
+
+// Format renders hunks as a unified diff between two named files, or "" if
+// there are no hunks
+func Format(fromName, toName string, hunks []Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks {
+		b.WriteString(h.String())
+	}
+	return b.String()
+}
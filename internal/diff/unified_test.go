@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHunks(t *testing.T) {
+	t.Run("equal text has no hunks", func(t *testing.T) {
+		a := SplitLines("a\nb\n")
+		assert.Empty(t, Hunks(a, a, 3))
+	})
+
+	t.Run("change with context", func(t *testing.T) {
+		a := SplitLines("1\n2\n3\n4\n5\n6\n7\n8\n")
+		b := SplitLines("1\n2\n3\n4\nfive\n6\n7\n8\n")
+
+		hunks := Hunks(a, b, 2)
+		require.Len(t, hunks, 1)
+		h := hunks[0]
+		assert.Equal(t, 3, h.OldStart)
+		assert.Equal(t, 5, h.OldLines)
+		assert.Equal(t, 3, h.NewStart)
+		assert.Equal(t, 5, h.NewLines)
+	})
+
+	t.Run("distant changes split into hunks", func(t *testing.T) {
+		a := SplitLines("a\n1\n2\n3\n4\n5\n6\n7\nb\n")
+		b := SplitLines("A\n1\n2\n3\n4\n5\n6\n7\nB\n")
+
+		assert.Len(t, Hunks(a, b, 3), 2)
+		assert.Len(t, Hunks(a, b, 4), 1)
+	})
+
+	t.Run("insert into empty text", func(t *testing.T) {
+		hunks := Hunks(nil, SplitLines("a\n"), 3)
+		require.Len(t, hunks, 1)
+		assert.Equal(t, 0, hunks[0].OldStart)
+		assert.Equal(t, 0, hunks[0].OldLines)
+		assert.Equal(t, 1, hunks[0].NewStart)
+		assert.Equal(t, 1, hunks[0].NewLines)
+	})
+}
+
+func TestUnified(t *testing.T) {
+	t.Run("renders headers and hunks", func(t *testing.T) {
+		out := Unified("deployed", "source", "a\nb\nc\n", "a\nB\nc\n")
+
+		expected := "--- deployed\n" +
+			"+++ source\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" a\n" +
+			"-b\n" +
+			"+B\n" +
+			" c\n"
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("marks missing final newline", func(t *testing.T) {
+		out := Unified("a", "b", "x\n", "x\ny")
+		assert.True(t, strings.HasSuffix(out, "+y\n\\ No newline at end of file\n"))
+	})
+
+	t.Run("equal text renders nothing", func(t *testing.T) {
+		assert.Equal(t, "", Unified("a", "b", "same\n", "same\n"))
+	})
+}
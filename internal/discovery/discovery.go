@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
 )
 
 // DeploymentStatus represents the status of an agent at a location
@@ -19,6 +21,13 @@ const (
 	StatusUpdateAvailable DeploymentStatus = "update-available"
 	StatusNotDeployed     DeploymentStatus = "not-deployed"
 	StatusUnknown         DeploymentStatus = "unknown"
+
+	// Drift statuses, computed from the content hashes in a project manifest
+	StatusLocallyModified DeploymentStatus = "locally-modified" // Deployed file edited since deployment
+	StatusSourceModified  DeploymentStatus = "source-modified"  // Source changed since deployment
+	StatusBothModified    DeploymentStatus = "both-modified"    // Deployed file and source both changed
+	StatusOrphaned        DeploymentStatus = "orphaned"         // Deployed by CAMI but no longer in any source
+	StatusUntracked       DeploymentStatus = "untracked"        // Deployed file not recorded in the manifest
 )
 
 // AgentStatus represents an agent's deployment status at a location
@@ -28,6 +37,8 @@ type AgentStatus struct {
 	AvailableVersion string
 	Status           DeploymentStatus
 	Location         *config.DeployLocation
+	OverridesUser    bool     // Deployed to a project and to the user scope; Claude Code uses the project's copy
+	ShadowedBy       []string // User scope only: locations whose own copy takes precedence, filled in by ScanAllLocations
 }
 
 // LocationStatus represents all agent statuses for a single location
@@ -43,7 +54,16 @@ type DiscoveryResult struct {
 	AvailableAgents  []*agent.Agent
 }
 
-// ScanLocation scans a deployment location for deployed agents
+// deployedFile is an agent file found in a project's .claude/agents
+type deployedFile struct {
+	agent *agent.Agent
+	path  string
+}
+
+// ScanLocation scans a deployment location for deployed agents. Projects
+// with a CAMI manifest get drift statuses from its content hashes, and
+// deployed agents that no source provides or the manifest doesn't know
+// about are reported too; projects without one are compared by version.
 func ScanLocation(location *config.DeployLocation, availableAgents []*agent.Agent) (*LocationStatus, error) {
 	agentsDir := filepath.Join(location.Path, ".claude", "agents")
 
@@ -69,40 +89,38 @@ func ScanLocation(location *config.DeployLocation, availableAgents []*agent.Agen
 	}
 
 	// Read deployed agent files
-	deployedAgents := make(map[string]*agent.Agent)
-	files, err := os.ReadDir(agentsDir)
+	deployedAgents, err := readDeployedAgents(agentsDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read agents directory: %w", err)
+		return nil, err
 	}
 
-	for _, file := range files {
-		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
-			continue
-		}
-
-		agentPath := filepath.Join(agentsDir, file.Name())
-		deployedAgent, err := agent.LoadAgent(agentPath)
+	// Drift can only be measured against a manifest
+	var projectManifest *manifest.ProjectManifest
+	if _, err := os.Stat(filepath.Join(location.Path, manifest.ProjectManifestFilename)); err == nil {
+		projectManifest, err = manifest.ReadProjectManifest(location.Path)
 		if err != nil {
-			// Skip agents we can't parse
-			continue
+			return nil, err
 		}
-
-		deployedAgents[deployedAgent.Name] = deployedAgent
 	}
 
 	// Compare with available agents
 	statuses := make([]*AgentStatus, 0, len(availableAgents))
+	available := make(map[string]bool, len(availableAgents))
 	for _, availableAgent := range availableAgents {
+		available[availableAgent.Name] = true
+
 		status := &AgentStatus{
 			Agent:            availableAgent,
 			AvailableVersion: availableAgent.Version,
 			Location:         location,
 		}
 
-		if deployedAgent, exists := deployedAgents[availableAgent.Name]; exists {
-			status.DeployedVersion = deployedAgent.Version
+		if deployed, exists := deployedAgents[availableAgent.Name]; exists {
+			status.DeployedVersion = deployed.agent.Version
 
-			if deployedAgent.Version == availableAgent.Version {
+			if projectManifest != nil {
+				status.Status = driftStatus(projectManifest.FindAgent(availableAgent.Name), deployed.path, availableAgent)
+			} else if deployed.agent.Version == availableAgent.Version {
 				status.Status = StatusUpToDate
 			} else {
 				status.Status = StatusUpdateAvailable
@@ -115,6 +133,39 @@ func ScanLocation(location *config.DeployLocation, availableAgents []*agent.Agen
 		statuses = append(statuses, status)
 	}
 
+	if projectManifest == nil {
+		markUserOverrides(location, statuses)
+		return &LocationStatus{
+			Location:      location,
+			AgentStatuses: statuses,
+			LastScanned:   time.Now(),
+		}, nil
+	}
+
+	// Deployed agents no source provides
+	var names []string
+	for name := range deployedAgents {
+		if !available[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		deployed := deployedAgents[name]
+		status := &AgentStatus{
+			Agent:           deployed.agent,
+			DeployedVersion: deployed.agent.Version,
+			Status:          StatusOrphaned,
+			Location:        location,
+		}
+		if projectManifest.FindAgent(name) == nil {
+			status.Status = StatusUntracked
+		}
+		statuses = append(statuses, status)
+	}
+
+	markUserOverrides(location, statuses)
 	return &LocationStatus{
 		Location:      location,
 		AgentStatuses: statuses,
@@ -122,6 +173,93 @@ func ScanLocation(location *config.DeployLocation, availableAgents []*agent.Agen
 	}, nil
 }
 
+// readDeployedAgents loads the agent files in an agents directory, keyed by
+// agent name
+func readDeployedAgents(agentsDir string) (map[string]*deployedFile, error) {
+	deployedAgents := make(map[string]*deployedFile)
+	files, err := os.ReadDir(agentsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agents directory: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
+			continue
+		}
+
+		agentPath := filepath.Join(agentsDir, file.Name())
+		deployedAgent, err := agent.LoadAgent(agentPath)
+		if err != nil {
+			// Skip agents we can't parse
+			continue
+		}
+
+		deployedAgents[deployedAgent.Name] = &deployedFile{agent: deployedAgent, path: agentPath}
+	}
+
+	return deployedAgents, nil
+}
+
+// markUserOverrides flags the agents deployed to a project that are also
+// deployed to the user scope. Claude Code prefers the project's copy.
+func markUserOverrides(location *config.DeployLocation, statuses []*AgentStatus) {
+	if config.IsUserScope(location.Path) {
+		return
+	}
+
+	user, err := config.UserScope()
+	if err != nil {
+		return
+	}
+	userAgents, err := readDeployedAgents(filepath.Join(user.Path, ".claude", "agents"))
+	if err != nil {
+		return
+	}
+
+	for _, status := range statuses {
+		if status.Status == StatusNotDeployed {
+			continue
+		}
+		if _, ok := userAgents[status.Agent.Name]; ok {
+			status.OverridesUser = true
+		}
+	}
+}
+
+// driftStatus compares a deployed file and its source against the content
+// hash recorded when the agent was deployed
+func driftStatus(entry *manifest.DeployedAgent, deployedPath string, source *agent.Agent) DeploymentStatus {
+	if entry == nil {
+		return StatusUntracked
+	}
+
+	deployedHash, err := manifest.CalculateContentHash(deployedPath)
+	if err != nil {
+		return StatusUnknown
+	}
+	sourceHash := manifest.HashContent([]byte(source.FullContent()))
+
+	// Identical content is current however it got there
+	if deployedHash == sourceHash {
+		return StatusUpToDate
+	}
+	if entry.ContentHash == "" {
+		return StatusUnknown
+	}
+
+	localChanged := deployedHash != entry.ContentHash
+	sourceChanged := sourceHash != entry.ContentHash
+
+	switch {
+	case localChanged && sourceChanged:
+		return StatusBothModified
+	case localChanged:
+		return StatusLocallyModified
+	default:
+		return StatusSourceModified
+	}
+}
+
 // ScanAllLocations scans all configured locations
 func ScanAllLocations(locations []config.DeployLocation, availableAgents []*agent.Agent) (*DiscoveryResult, error) {
 	locationStatuses := make([]*LocationStatus, 0, len(locations))
@@ -135,12 +273,42 @@ func ScanAllLocations(locations []config.DeployLocation, availableAgents []*agen
 		locationStatuses = append(locationStatuses, status)
 	}
 
+	markShadowed(locationStatuses)
+
 	return &DiscoveryResult{
 		LocationStatuses: locationStatuses,
 		AvailableAgents:  availableAgents,
 	}, nil
 }
 
+// markShadowed records on the user scope's agents which of the scanned
+// projects override them with their own copy
+func markShadowed(locationStatuses []*LocationStatus) {
+	var userStatuses map[string]*AgentStatus
+	for _, locStatus := range locationStatuses {
+		if config.IsUserScope(locStatus.Location.Path) {
+			userStatuses = make(map[string]*AgentStatus)
+			for _, status := range locStatus.AgentStatuses {
+				userStatuses[status.Agent.Name] = status
+			}
+		}
+	}
+	if userStatuses == nil {
+		return
+	}
+
+	for _, locStatus := range locationStatuses {
+		for _, status := range locStatus.AgentStatuses {
+			if !status.OverridesUser {
+				continue
+			}
+			if userStatus, ok := userStatuses[status.Agent.Name]; ok {
+				userStatus.ShadowedBy = append(userStatus.ShadowedBy, locStatus.Location.Name)
+			}
+		}
+	}
+}
+
 // GetStatusSymbol returns the symbol to display for a status
 func GetStatusSymbol(status DeploymentStatus) string {
 	switch status {
@@ -150,6 +318,16 @@ func GetStatusSymbol(status DeploymentStatus) string {
 		return "⚠"
 	case StatusNotDeployed:
 		return "○"
+	case StatusSourceModified:
+		return "↑"
+	case StatusLocallyModified:
+		return "✎"
+	case StatusBothModified:
+		return "⇅"
+	case StatusOrphaned:
+		return "✗"
+	case StatusUntracked:
+		return "+"
 	default:
 		return "?"
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -285,6 +286,101 @@ func TestScanLocation(t *testing.T) {
 	})
 }
 
+func TestScanLocationDrift(t *testing.T) {
+	sourceAgent := func(name, version, body string) *agent.Agent {
+		return &agent.Agent{Name: name, Version: version, Description: "Test agent " + name, Content: body}
+	}
+
+	// deploy writes an agent file and records it in the project manifest
+	deploy := func(t *testing.T, projectPath string, agents ...*agent.Agent) {
+		t.Helper()
+		agentsDir := filepath.Join(projectPath, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+
+		pm := &manifest.ProjectManifest{Version: "2", State: manifest.StateCAMINative}
+		for _, ag := range agents {
+			content := []byte(ag.FullContent())
+			require.NoError(t, os.WriteFile(filepath.Join(agentsDir, ag.Name+".md"), content, 0644))
+			pm.Agents = append(pm.Agents, manifest.DeployedAgent{
+				Name:        ag.Name,
+				Version:     ag.Version,
+				ContentHash: manifest.HashContent(content),
+			})
+		}
+		require.NoError(t, manifest.WriteProjectManifest(projectPath, pm))
+	}
+
+	scan := func(t *testing.T, projectPath string, available ...*agent.Agent) map[string]*AgentStatus {
+		t.Helper()
+		status, err := ScanLocation(&config.DeployLocation{Name: "test", Path: projectPath}, available)
+		require.NoError(t, err)
+
+		statusMap := make(map[string]*AgentStatus)
+		for _, s := range status.AgentStatuses {
+			statusMap[s.Agent.Name] = s
+		}
+		return statusMap
+	}
+
+	editDeployed := func(t *testing.T, projectPath, name string) {
+		t.Helper()
+		path := filepath.Join(projectPath, ".claude", "agents", name+".md")
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, append(data, []byte("\nLocal notes.\n")...), 0644))
+	}
+
+	t.Run("unchanged agent is up to date", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := sourceAgent("frontend", "1.0.0", "# Frontend")
+		deploy(t, tmpDir, ag)
+
+		assert.Equal(t, StatusUpToDate, scan(t, tmpDir, ag)["frontend"].Status)
+	})
+
+	t.Run("source edited without a version bump", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deploy(t, tmpDir, sourceAgent("frontend", "1.0.0", "# Frontend"))
+
+		statuses := scan(t, tmpDir, sourceAgent("frontend", "1.0.0", "# Frontend, revised"))
+		assert.Equal(t, StatusSourceModified, statuses["frontend"].Status)
+	})
+
+	t.Run("deployed file edited", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		ag := sourceAgent("frontend", "1.0.0", "# Frontend")
+		deploy(t, tmpDir, ag)
+		editDeployed(t, tmpDir, "frontend")
+
+		assert.Equal(t, StatusLocallyModified, scan(t, tmpDir, ag)["frontend"].Status)
+	})
+
+	t.Run("both edited", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deploy(t, tmpDir, sourceAgent("frontend", "1.0.0", "# Frontend"))
+		editDeployed(t, tmpDir, "frontend")
+
+		statuses := scan(t, tmpDir, sourceAgent("frontend", "1.1.0", "# Frontend"))
+		assert.Equal(t, StatusBothModified, statuses["frontend"].Status)
+		assert.Equal(t, "1.0.0", statuses["frontend"].DeployedVersion)
+		assert.Equal(t, "1.1.0", statuses["frontend"].AvailableVersion)
+	})
+
+	t.Run("orphaned and untracked agents", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		deploy(t, tmpDir, sourceAgent("legacy", "1.0.0", "# Legacy"))
+		createTestAgentFile(t, filepath.Join(tmpDir, ".claude", "agents"), "handmade", "0.1.0")
+		createTestAgentFile(t, filepath.Join(tmpDir, ".claude", "agents"), "frontend", "1.0.0")
+
+		statuses := scan(t, tmpDir, sourceAgent("frontend", "1.0.0", "# Frontend"))
+		assert.Len(t, statuses, 3)
+		assert.Equal(t, StatusUntracked, statuses["frontend"].Status)
+		assert.Equal(t, StatusOrphaned, statuses["legacy"].Status)
+		assert.Equal(t, "1.0.0", statuses["legacy"].DeployedVersion)
+		assert.Equal(t, StatusUntracked, statuses["handmade"].Status)
+	})
+}
+
 func TestScanAllLocations(t *testing.T) {
 	t.Run("scan multiple locations", func(t *testing.T) {
 		tmpDir1 := t.TempDir()
@@ -333,6 +429,48 @@ func TestScanAllLocations(t *testing.T) {
 		assert.Len(t, result.LocationStatuses, 2)
 		// Both locations scanned, just one had no agents dir
 	})
+
+	t.Run("project agents override the user scope", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		user, err := config.UserScope()
+		require.NoError(t, err)
+		userAgentsDir := filepath.Join(home, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(userAgentsDir, 0755))
+		createTestAgentFile(t, userAgentsDir, "frontend", "1.0.0")
+		createTestAgentFile(t, userAgentsDir, "devops", "1.0.0")
+
+		project := createTestProject(t, t.TempDir(), "app", "frontend", "backend")
+
+		locations := []config.DeployLocation{user, {Name: "app", Path: project}}
+		availableAgents := []*agent.Agent{
+			{Name: "frontend", Version: "1.0.0"},
+			{Name: "backend", Version: "1.0.0"},
+			{Name: "devops", Version: "1.0.0"},
+		}
+
+		result, err := ScanAllLocations(locations, availableAgents)
+		require.NoError(t, err)
+		require.Len(t, result.LocationStatuses, 2)
+
+		statuses := func(locStatus *LocationStatus) map[string]*AgentStatus {
+			m := make(map[string]*AgentStatus)
+			for _, status := range locStatus.AgentStatuses {
+				m[status.Agent.Name] = status
+			}
+			return m
+		}
+		userStatuses := statuses(result.LocationStatuses[0])
+		projectStatuses := statuses(result.LocationStatuses[1])
+
+		assert.True(t, projectStatuses["frontend"].OverridesUser)
+		assert.False(t, projectStatuses["backend"].OverridesUser)
+		assert.False(t, projectStatuses["devops"].OverridesUser)
+
+		assert.Equal(t, []string{"app"}, userStatuses["frontend"].ShadowedBy)
+		assert.Empty(t, userStatuses["devops"].ShadowedBy)
+		assert.False(t, userStatuses["frontend"].OverridesUser)
+	})
 }
 
 func TestGetStatusSymbol(t *testing.T) {
@@ -343,6 +481,11 @@ func TestGetStatusSymbol(t *testing.T) {
 		{StatusUpToDate, "✓"},
 		{StatusUpdateAvailable, "⚠"},
 		{StatusNotDeployed, "○"},
+		{StatusSourceModified, "↑"},
+		{StatusLocallyModified, "✎"},
+		{StatusBothModified, "⇅"},
+		{StatusOrphaned, "✗"},
+		{StatusUntracked, "+"},
 		{StatusUnknown, "?"},
 	}
 
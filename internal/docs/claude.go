@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
 )
 
 const (
@@ -77,6 +78,52 @@ func UpdateCLAUDEmd(projectPath, sectionName string, dryRun bool) (string, error
 	return newContent, nil
 }
 
+// RefreshCLAUDEmd brings the CLAUDE.md managed section in line with the
+// agents deployed to a project. Unlike UpdateCLAUDEmd it tolerates a project
+// with no agents left, removing the section instead. The user scope is left
+// alone: its project path is the home directory, and ~/CLAUDE.md isn't the
+// user memory Claude Code reads.
+func RefreshCLAUDEmd(projectPath, sectionName string) error {
+	if config.IsUserScope(projectPath) {
+		return nil
+	}
+
+	agentsDir := filepath.Join(projectPath, ".claude", "agents")
+
+	var deployedAgents []*agent.Agent
+	if _, err := os.Stat(agentsDir); err == nil {
+		deployedAgents, err = scanDeployedAgents(agentsDir)
+		if err != nil {
+			return fmt.Errorf("failed to scan agents: %w", err)
+		}
+	}
+
+	if len(deployedAgents) > 0 {
+		_, err := UpdateCLAUDEmd(projectPath, sectionName, false)
+		return err
+	}
+
+	claudePath := filepath.Join(projectPath, "CLAUDE.md")
+	data, err := os.ReadFile(claudePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+
+	newContent := removeSection(string(data))
+	if newContent == string(data) {
+		return nil
+	}
+
+	if err := os.WriteFile(claudePath, []byte(newContent), 0644); err != nil {
+		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+
+	return nil
+}
+
 // scanDeployedAgents scans the .claude/agents directory and returns agent info
 func scanDeployedAgents(agentsDir string) ([]*agent.Agent, error) {
 	files, err := os.ReadDir(agentsDir)
@@ -99,6 +146,7 @@ func scanDeployedAgents(agentsDir string) ([]*agent.Agent, error) {
 		agents = append(agents, ag)
 	}
 
+	agent.Sort(agents)
 	return agents, nil
 }
 
@@ -176,6 +224,35 @@ func mergeContent(existing, newSection string) string {
 	return sb.String()
 }
 
+// removeSection strips the managed section from CLAUDE.md content, along with
+// the blank line mergeContent puts before it
+func removeSection(existing string) string {
+	startMatch := sectionMarkerPattern.FindStringIndex(existing)
+	endIdx := strings.Index(existing, sectionMarkerEnd)
+
+	if startMatch == nil || endIdx == -1 {
+		return existing
+	}
+
+	endOfMarker := endIdx + len(sectionMarkerEnd)
+	nextNewline := strings.Index(existing[endOfMarker:], "\n")
+	if nextNewline != -1 {
+		endOfMarker += nextNewline + 1
+	}
+
+	before := strings.TrimRight(existing[:startMatch[0]], "\n")
+	after := strings.TrimLeft(existing[endOfMarker:], "\n")
+
+	switch {
+	case before == "":
+		return after
+	case after == "":
+		return before + "\n"
+	default:
+		return before + "\n\n" + after
+	}
+}
+
 // ScanDeployedAgentsInfo returns information about deployed agents
 func ScanDeployedAgentsInfo(projectPath string) ([]*agent.Agent, error) {
 	agentsDir := filepath.Join(projectPath, ".claude", "agents")
@@ -1,6 +1,8 @@
 package docs
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -126,3 +128,82 @@ Updated content
 
 	t.Logf("Merged content:\n%s", result)
 }
+
+func TestRemoveSection(t *testing.T) {
+	content := `# Project Documentation
+
+<!-- CAMI-MANAGED: DEPLOYED-AGENTS | Last Updated: 2025-10-08T10:00:00-05:00 -->
+## Deployed Agents
+
+Previous content
+
+<!-- /CAMI-MANAGED: DEPLOYED-AGENTS -->
+
+Some other content
+`
+
+	expected := `# Project Documentation
+
+Some other content
+`
+	if result := removeSection(content); result != expected {
+		t.Errorf("Unexpected content after removing section:\n%s", result)
+	}
+
+	// Content without a managed section is left alone
+	if result := removeSection(expected); result != expected {
+		t.Error("Content without a managed section was changed")
+	}
+
+	// A section appended by mergeContent leaves the original content behind
+	original := "# Project\n"
+	merged := mergeContent(original, generateAgentSection("Deployed Agents", []*agent.Agent{{Name: "test-agent"}}))
+	if result := removeSection(merged); result != original {
+		t.Errorf("Expected %q after removing appended section, got %q", original, result)
+	}
+}
+
+func TestRefreshCLAUDEmdWithoutAgents(t *testing.T) {
+	projectPath := t.TempDir()
+	claudePath := filepath.Join(projectPath, "CLAUDE.md")
+
+	original := "# Project\n"
+	merged := mergeContent(original, generateAgentSection("Deployed Agents", []*agent.Agent{{Name: "test-agent"}}))
+	if err := os.WriteFile(claudePath, []byte(merged), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RefreshCLAUDEmd(projectPath, "Deployed Agents"); err != nil {
+		t.Fatalf("RefreshCLAUDEmd failed: %v", err)
+	}
+
+	data, err := os.ReadFile(claudePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("Expected managed section to be removed, got:\n%s", data)
+	}
+}
+
+func TestRefreshCLAUDEmdSkipsUserScope(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	agentsDir := filepath.Join(home, ".claude", "agents")
+	if err := os.MkdirAll(agentsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: reviewer\nversion: 1.0.0\ndescription: Reviews code\n---\nReview.\n"
+	if err := os.WriteFile(filepath.Join(agentsDir, "reviewer.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RefreshCLAUDEmd(home, "Deployed Agents"); err != nil {
+		t.Fatalf("RefreshCLAUDEmd failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected no CLAUDE.md in the home directory, got err %v", err)
+	}
+}
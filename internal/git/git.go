@@ -0,0 +1,209 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Run runs a git command in dir and returns its trimmed output
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsRepo reports whether dir is inside a git work tree
+func IsRepo(dir string) bool {
+	out, err := Run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// Tags returns the repository's tag names
+func Tags(dir string) ([]string, error) {
+	out, err := Run(dir, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// ListFiles returns the paths of all files in the tree at ref, relative to
+// the repository root
+func ListFiles(dir, ref string) ([]string, error) {
+	out, err := Run(dir, "ls-tree", "-r", "--name-only", "--full-tree", ref)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// Prefix returns the path of dir relative to the repository root, with a
+// trailing slash, or "" at the root
+func Prefix(dir string) (string, error) {
+	return Run(dir, "rev-parse", "--show-prefix")
+}
+
+// Commit resolves a ref to its commit SHA
+func Commit(dir, ref string) (string, error) {
+	return Run(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+}
+
+// Checkout checks out a branch, tag or commit. Branches that exist on origin
+// are checked out as tracking branches; tags and commits are detached.
+func Checkout(dir, ref string) error {
+	if isRemoteBranch(dir, ref) {
+		_, err := Run(dir, "checkout", "-q", ref)
+		return err
+	}
+	_, err := Run(dir, "checkout", "-q", "--detach", ref)
+	return err
+}
+
+// Update fetches from origin and moves the work tree to the latest state of
+// ref: a branch is fast-forwarded to its remote, while a tag or commit stays
+// where it points. With no ref, the current branch is pulled. It reports
+// whether HEAD moved.
+func Update(dir, ref string) (bool, error) {
+	before, err := Commit(dir, "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	if ref == "" {
+		if _, err := Run(dir, "pull"); err != nil {
+			return false, err
+		}
+	} else {
+		if _, err := Run(dir, "fetch", "--tags", "origin"); err != nil {
+			return false, err
+		}
+		if err := Checkout(dir, ref); err != nil {
+			return false, err
+		}
+		if isRemoteBranch(dir, ref) {
+			if _, err := Run(dir, "merge", "--ff-only", "-q", "origin/"+ref); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	after, err := Commit(dir, "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	return before != after, nil
+}
+
+func isRemoteBranch(dir, ref string) bool {
+	_, err := Run(dir, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+ref)
+	return err == nil
+}
+
+// ReadFiles reads the contents of paths at ref in one git process. Paths
+// missing at ref are left out of the result.
+func ReadFiles(dir, ref string, paths []string) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(paths))
+	if len(paths) == 0 {
+		return files, nil
+	}
+
+	var input bytes.Buffer
+	for _, path := range paths {
+		fmt.Fprintf(&input, "%s:%s\n", ref, path)
+	}
+
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	cmd.Stdin = &input
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run git cat-file: %w", err)
+	}
+
+	reader := bufio.NewReader(stdout)
+	for _, path := range paths {
+		data, err := readBatchObject(reader)
+		if err != nil {
+			_ = cmd.Wait()
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path, ref, err)
+		}
+		if data != nil {
+			files[path] = data
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git cat-file: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return files, nil
+}
+
+// readBatchObject reads one object from git cat-file --batch output,
+// returning nil data for missing objects
+func readBatchObject(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	// "<sha> <type> <size>" or "<object> missing"
+	fields := strings.Fields(header)
+	if len(fields) == 2 && fields[1] == "missing" {
+		return nil, nil
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected cat-file header %q", strings.TrimSpace(header))
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected cat-file header %q", strings.TrimSpace(header))
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	// Each object is followed by a newline
+	if _, err := r.ReadByte(); err != nil {
+		return nil, err
+	}
+
+	if fields[1] != "blob" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,185 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepo creates a repository with one commit per entry, tagging each
+func initRepo(t *testing.T, commits []map[string]string, tags []string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	_, err := Run(dir, "init", "-q")
+	require.NoError(t, err)
+
+	for i, files := range commits {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+		_, err := Run(dir, "add", "-A")
+		require.NoError(t, err)
+		_, err = Run(dir, "commit", "-q", "-m", "commit")
+		require.NoError(t, err)
+		if i < len(tags) && tags[i] != "" {
+			_, err = Run(dir, "tag", tags[i])
+			require.NoError(t, err)
+		}
+	}
+
+	return dir
+}
+
+func TestRepository(t *testing.T) {
+	dir := initRepo(t, []map[string]string{
+		{"agents/frontend.md": "v1\n", "README.md": "readme\n"},
+		{"agents/frontend.md": "v2\n", "agents/backend.md": "backend\n"},
+	}, []string{"v1.0.0", "v2.0.0"})
+
+	t.Run("is repo", func(t *testing.T) {
+		assert.True(t, IsRepo(dir))
+		assert.False(t, IsRepo(t.TempDir()))
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		tags, err := Tags(dir)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"v1.0.0", "v2.0.0"}, tags)
+	})
+
+	t.Run("list files at ref", func(t *testing.T) {
+		files, err := ListFiles(dir, "v1.0.0")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"README.md", "agents/frontend.md"}, files)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		prefix, err := Prefix(filepath.Join(dir, "agents"))
+		require.NoError(t, err)
+		assert.Equal(t, "agents/", prefix)
+
+		prefix, err = Prefix(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "", prefix)
+	})
+
+	t.Run("read files at ref", func(t *testing.T) {
+		files, err := ReadFiles(dir, "v1.0.0", []string{"agents/frontend.md", "agents/backend.md", "README.md"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "v1\n", string(files["agents/frontend.md"]))
+		assert.Equal(t, "readme\n", string(files["README.md"]))
+		assert.NotContains(t, files, "agents/backend.md")
+
+		files, err = ReadFiles(dir, "v2.0.0", []string{"agents/frontend.md", "agents/backend.md"})
+		require.NoError(t, err)
+		assert.Equal(t, "v2\n", string(files["agents/frontend.md"]))
+		assert.Equal(t, "backend\n", string(files["agents/backend.md"]))
+	})
+
+	t.Run("run reports git errors", func(t *testing.T) {
+		_, err := Run(dir, "rev-parse", "no-such-ref")
+		assert.Error(t, err)
+	})
+}
+
+func TestRefs(t *testing.T) {
+	origin := initRepo(t, []map[string]string{
+		{"agent.md": "v1\n"},
+		{"agent.md": "v2\n"},
+	}, []string{"v1.0.0", "v2.0.0"})
+
+	branch, err := Run(origin, "rev-parse", "--abbrev-ref", "HEAD")
+	require.NoError(t, err)
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	_, err = Run(filepath.Dir(clone), "clone", "-q", origin, clone)
+	require.NoError(t, err)
+
+	readAgent := func() string {
+		data, err := os.ReadFile(filepath.Join(clone, "agent.md"))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	commitOrigin := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(origin, "agent.md"), []byte(content), 0644))
+		_, err := Run(origin, "commit", "-q", "-am", "update")
+		require.NoError(t, err)
+	}
+
+	t.Run("commit resolves refs", func(t *testing.T) {
+		tagCommit, err := Commit(origin, "v1.0.0")
+		require.NoError(t, err)
+		assert.Len(t, tagCommit, 40)
+
+		_, err = Commit(origin, "no-such-ref")
+		assert.Error(t, err)
+	})
+
+	t.Run("checkout tag detaches", func(t *testing.T) {
+		require.NoError(t, Checkout(clone, "v1.0.0"))
+		assert.Equal(t, "v1\n", readAgent())
+
+		head, err := Run(clone, "rev-parse", "--abbrev-ref", "HEAD")
+		require.NoError(t, err)
+		assert.Equal(t, "HEAD", head)
+	})
+
+	t.Run("update keeps tag pinned", func(t *testing.T) {
+		commitOrigin("v3\n")
+
+		moved, err := Update(clone, "v1.0.0")
+		require.NoError(t, err)
+		assert.False(t, moved)
+		assert.Equal(t, "v1\n", readAgent())
+	})
+
+	t.Run("update follows branch", func(t *testing.T) {
+		moved, err := Update(clone, branch)
+		require.NoError(t, err)
+		assert.True(t, moved)
+		assert.Equal(t, "v3\n", readAgent())
+
+		commitOrigin("v4\n")
+		moved, err = Update(clone, branch)
+		require.NoError(t, err)
+		assert.True(t, moved)
+		assert.Equal(t, "v4\n", readAgent())
+	})
+
+	t.Run("update pinned to commit", func(t *testing.T) {
+		sha, err := Commit(origin, "v2.0.0")
+		require.NoError(t, err)
+
+		moved, err := Update(clone, sha)
+		require.NoError(t, err)
+		assert.True(t, moved)
+		assert.Equal(t, "v2\n", readAgent())
+	})
+
+	t.Run("update without ref pulls", func(t *testing.T) {
+		require.NoError(t, Checkout(clone, branch))
+		commitOrigin("v5\n")
+
+		moved, err := Update(clone, "")
+		require.NoError(t, err)
+		assert.True(t, moved)
+		assert.Equal(t, "v5\n", readAgent())
+	})
+}
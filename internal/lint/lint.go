@@ -0,0 +1,225 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lando/cami/internal/agent"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Breaks deployment or the agent itself
+	SeverityWarning Severity = "warning" // Likely mistake
+	SeverityInfo    Severity = "info"    // Worth knowing, nothing to fix
+)
+
+// rank orders severities from least to most serious
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as serious as other or more
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+// ParseSeverity parses "error", "warning" or "info"
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(s)
+	if severity.rank() == 0 {
+		return "", fmt.Errorf("invalid severity: %s (must be 'error', 'warning' or 'info')", s)
+	}
+	return severity, nil
+}
+
+// Finding is one problem a rule found
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Source   string   `json:"source,omitempty"`
+	Agent    string   `json:"agent,omitempty"` // ID: name, /name or skill:name
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`   // 1-based; 0 when the finding concerns the whole file
+	Column   int      `json:"column,omitempty"` // 1-based; 0 when the finding concerns the whole line
+	Message  string   `json:"message"`
+}
+
+// Source is a set of agents to lint, loaded from one source directory
+type Source struct {
+	Name   string
+	Path   string
+	Agents []*agent.Agent    // Agents, commands and skills, every file included
+	Report *agent.LoadReport // Files that failed to load
+}
+
+// Set is what a lint run looks at: the sources being linted, plus every
+// agent available from the configured sources, which references may name
+type Set struct {
+	Sources   []*Source
+	Available map[string]bool // IDs of the agents the configured sources provide
+}
+
+// Rule checks a set for one kind of problem. Rules report findings at their
+// default severity unless they set one.
+type Rule interface {
+	ID() string
+	Description() string
+	Severity() Severity
+	Check(set *Set, report func(Finding))
+}
+
+// Report is the outcome of linting a set
+type Report struct {
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Infos    int       `json:"infos"`
+}
+
+// LoadSource loads every agent, command and skill in a source directory,
+// without the priority deduplication deployment uses
+func LoadSource(name, path string) (*Source, error) {
+	report := &agent.LoadReport{}
+	agents, agentReport, err := agent.LoadAgents(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agents: %w", err)
+	}
+	report.Merge(agentReport)
+	commands, commandReport, err := agent.LoadCommands(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commands: %w", err)
+	}
+	report.Merge(commandReport)
+	skills, skillReport, err := agent.LoadSkills(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skills: %w", err)
+	}
+	report.Merge(skillReport)
+
+	all := append(append(agents, commands...), skills...)
+	for _, ag := range all {
+		ag.Source = name
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].FilePath < all[j].FilePath
+	})
+
+	return &Source{Name: name, Path: path, Agents: all, Report: report}, nil
+}
+
+// Run checks a set against rules, returning findings sorted by file, line
+// and rule
+func Run(set *Set, rules []Rule) *Report {
+	report := &Report{Findings: []Finding{}}
+
+	for _, rule := range rules {
+		rule.Check(set, func(f Finding) {
+			f.Rule = rule.ID()
+			if f.Severity == "" {
+				f.Severity = rule.Severity()
+			}
+			report.Findings = append(report.Findings, f)
+		})
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Rule < b.Rule
+	})
+
+	for _, f := range report.Findings {
+		switch f.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		default:
+			report.Infos++
+		}
+	}
+
+	return report
+}
+
+// Failed reports whether any finding is at least as serious as threshold
+func (r *Report) Failed(threshold Severity) bool {
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRule returns the rule with an ID from rules, or nil
+func FindRule(rules []Rule, id string) Rule {
+	for _, rule := range rules {
+		if rule.ID() == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// agentRule is a rule that looks at each agent on its own
+type agentRule struct {
+	id          string
+	description string
+	severity    Severity
+	check       func(ag *agent.Agent) []Finding
+}
+
+func (r *agentRule) ID() string          { return r.id }
+func (r *agentRule) Description() string { return r.description }
+func (r *agentRule) Severity() Severity  { return r.severity }
+
+func (r *agentRule) Check(set *Set, report func(Finding)) {
+	for _, source := range set.Sources {
+		for _, ag := range source.Agents {
+			for _, f := range r.check(ag) {
+				report(at(ag, f))
+			}
+		}
+	}
+}
+
+// at fills in the agent a finding is about
+func at(ag *agent.Agent, f Finding) Finding {
+	f.Source = ag.Source
+	f.Agent = ag.ID()
+	f.File = ag.FilePath
+	return f
+}
+
+// keyLine returns the file line of a frontmatter key, or 0 if the agent
+// doesn't set it. Frontmatter starts on the line after the opening ---.
+func keyLine(ag *agent.Agent, key string) int {
+	if ag.Frontmatter == nil {
+		return 0
+	}
+
+	mapping := ag.Frontmatter.Node()
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i].Line + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, rel, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// findingsFor returns the findings of one rule, by file base name
+func findingsFor(report *Report, rule string) map[string][]Finding {
+	byFile := make(map[string][]Finding)
+	for _, f := range report.Findings {
+		if f.Rule == rule {
+			byFile[filepath.Base(f.File)] = append(byFile[filepath.Base(f.File)], f)
+		}
+	}
+	return byFile
+}
+
+func lintDir(t *testing.T, dir string) *Report {
+	t.Helper()
+	source, err := LoadSource("team", dir)
+	require.NoError(t, err)
+	return Run(&Set{Sources: []*Source{source}}, DefaultRules())
+}
+
+func TestRules(t *testing.T) {
+	const valid = "---\nname: frontend\nversion: 1.0.0\ndescription: Builds React user interfaces\nclass: technology-implementer\ntools: Read, Write, Bash(git:*), mcp__github__search\n---\nYou build frontends.\n"
+
+	t.Run("a valid agent has no findings", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "frontend.md", valid)
+
+		report := lintDir(t, dir)
+		assert.Empty(t, report.Findings)
+		assert.False(t, report.Failed(SeverityInfo))
+	})
+
+	t.Run("each rule reports its problem", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "frontend.md", valid)
+		writeFile(t, dir, "semver.md", "---\nname: semver\nversion: 1.x\ndescription: Has a broken version string\n---\nBody\n")
+		writeFile(t, dir, "renamed.md", "---\nname: other-name\nversion: 1.0.0\ndescription: Name differs from the file\n---\nBody\n")
+		writeFile(t, dir, "classy.md", "---\nname: classy\nversion: 1.0.0\ndescription: Declares a made up class\nclass: wizard\n---\nBody\n")
+		writeFile(t, dir, "terse.md", "---\nname: terse\nversion: 1.0.0\ndescription: Short\n---\nBody\n")
+		writeFile(t, dir, "tooled.md", "---\nname: tooled\nversion: 1.0.0\ndescription: Uses a tool that does not exist\ntools: [Read, Teleport]\n---\nBody\n")
+		writeFile(t, dir, "orchestrator.md", "---\nname: orchestrator\nversion: 1.0.0\ndescription: Delegates to the specialists\nrequires:\n  - frontend\n  - ghost@^1\n---\nBody\n")
+		writeFile(t, dir, "hollow.md", "---\nname: hollow\nversion: 1.0.0\ndescription: Has nothing after its frontmatter\n---\n\n")
+		writeFile(t, dir, "bare.md", "---\ndescription: No name or version here\n---\nBody\n")
+		writeFile(t, dir, "nested/frontend.md", valid)
+		writeFile(t, dir, "broken.md", "---\nname: broken\ndescription: a: b\n---\nBody\n")
+		writeFile(t, dir, "colorful.md", "---\nname: colorful\nversion: 1.0.0\ndescription: Picks a color Claude Code lacks\ncolor: magenta\n---\nBody\n")
+
+		report := lintDir(t, dir)
+
+		semver := findingsFor(report, "invalid-semver")["semver.md"]
+		require.Len(t, semver, 1)
+		assert.Equal(t, SeverityError, semver[0].Severity)
+		assert.Equal(t, 3, semver[0].Line)
+
+		renamed := findingsFor(report, "name-mismatch")["renamed.md"]
+		require.Len(t, renamed, 1)
+		assert.Equal(t, 2, renamed[0].Line)
+
+		classy := findingsFor(report, "unknown-class")["classy.md"]
+		require.Len(t, classy, 1)
+		assert.Contains(t, classy[0].Message, "strategic-planner")
+
+		assert.Len(t, findingsFor(report, "description-length")["terse.md"], 1)
+
+		tooled := findingsFor(report, "unknown-tool")["tooled.md"]
+		require.Len(t, tooled, 1)
+		assert.Contains(t, tooled[0].Message, "Teleport")
+
+		references := findingsFor(report, "broken-reference")["orchestrator.md"]
+		require.Len(t, references, 1)
+		assert.Contains(t, references[0].Message, "ghost")
+
+		assert.Len(t, findingsFor(report, "empty-body")["hollow.md"], 1)
+
+		bare := findingsFor(report, "missing-metadata")["bare.md"]
+		require.Len(t, bare, 2)
+		assert.Equal(t, SeverityError, bare[0].Severity)
+
+		duplicates := findingsFor(report, "duplicate-name")["frontend.md"]
+		require.Len(t, duplicates, 2)
+		assert.Equal(t, SeverityError, duplicates[0].Severity)
+
+		broken := findingsFor(report, "load-error")["broken.md"]
+		require.Len(t, broken, 1)
+		assert.Equal(t, 3, broken[0].Line)
+		assert.Equal(t, "team", broken[0].Source)
+
+		colorful := findingsFor(report, "schema")["colorful.md"]
+		require.Len(t, colorful, 1)
+		assert.Equal(t, 5, colorful[0].Line)
+		assert.Equal(t, 8, colorful[0].Column)
+		// Keys with their own rule aren't reported twice
+		assert.Empty(t, findingsFor(report, "schema")["semver.md"])
+		assert.Empty(t, findingsFor(report, "schema")["bare.md"])
+
+		assert.True(t, report.Failed(SeverityError))
+		assert.Equal(t, report.Errors+report.Warnings+report.Infos, len(report.Findings))
+	})
+
+	t.Run("duplicates across sources are warnings", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		writeFile(t, first, "frontend.md", valid)
+		writeFile(t, second, "frontend.md", valid)
+
+		a, err := LoadSource("first", first)
+		require.NoError(t, err)
+		b, err := LoadSource("second", second)
+		require.NoError(t, err)
+
+		report := Run(&Set{Sources: []*Source{a, b}}, DefaultRules())
+		require.Len(t, report.Findings, 2)
+		for _, f := range report.Findings {
+			assert.Equal(t, "duplicate-name", f.Rule)
+			assert.Equal(t, SeverityWarning, f.Severity)
+		}
+		assert.False(t, report.Failed(SeverityError))
+		assert.True(t, report.Failed(SeverityWarning))
+	})
+
+	t.Run("references may name agents other sources provide", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "orchestrator.md", "---\nname: orchestrator\nversion: 1.0.0\ndescription: Delegates to the specialists\nrequires: [backend]\n---\nBody\n")
+
+		source, err := LoadSource("team", dir)
+		require.NoError(t, err)
+		report := Run(&Set{Sources: []*Source{source}, Available: map[string]bool{"backend": true}}, DefaultRules())
+		assert.Empty(t, report.Findings)
+	})
+}
+
+func TestSARIF(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "agents/semver.md", "---\nname: semver\nversion: 1.x\ndescription: Has a broken version string\n---\nBody\n")
+
+	rules := DefaultRules()
+	log := SARIF(lintDir(t, dir), rules, "1.2.3", dir)
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(rules))
+
+	require.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "invalid-semver", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	location := result.Locations[0].PhysicalLocation
+	assert.Equal(t, "agents/semver.md", location.ArtifactLocation.URI)
+	assert.Equal(t, 3, location.Region.StartLine)
+}
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity("warning")
+	require.NoError(t, err)
+	assert.True(t, SeverityError.AtLeast(severity))
+	assert.False(t, SeverityInfo.AtLeast(severity))
+
+	_, err = ParseSeverity("fatal")
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/semver"
+	"github.com/lando/cami/internal/spec"
+)
+
+// Description length bounds. Claude Code picks agents by their description,
+// so a very short one rarely gets an agent chosen; 1024 characters is the
+// most Claude Code accepts for a skill's description.
+const (
+	MinDescriptionLength = 20
+	MaxDescriptionLength = 1024
+)
+
+// knownTools are the tools Claude Code offers agents. MCP tools, named
+// mcp__server__tool, are accepted as well.
+var knownTools = map[string]bool{
+	"Bash":         true,
+	"BashOutput":   true,
+	"Edit":         true,
+	"ExitPlanMode": true,
+	"Glob":         true,
+	"Grep":         true,
+	"KillShell":    true,
+	"LS":           true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+	"NotebookRead": true,
+	"Read":         true,
+	"SlashCommand": true,
+	"Skill":        true,
+	"Task":         true,
+	"TodoWrite":    true,
+	"WebFetch":     true,
+	"WebSearch":    true,
+	"Write":        true,
+}
+
+// DefaultRules returns every built-in rule
+func DefaultRules() []Rule {
+	return []Rule{
+		loadError{},
+		missingMetadata,
+		invalidSemver,
+		nameMismatch,
+		duplicateName{},
+		unknownClass,
+		descriptionLength,
+		unknownTool,
+		brokenReference{},
+		emptyBody,
+		schema,
+	}
+}
+
+var missingMetadata = &agentRule{
+	id:          "missing-metadata",
+	description: "Agents declare a name, version and description",
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		// Commands and skills may go without frontmatter
+		if ag.ArtifactKind() != agent.KindAgent {
+			return nil
+		}
+
+		var findings []Finding
+		if ag.Frontmatter == nil || !ag.Frontmatter.Has("name") {
+			findings = append(findings, Finding{Severity: SeverityError, Message: "missing name"})
+		}
+		if ag.Version == "" {
+			findings = append(findings, Finding{Message: "missing version"})
+		}
+		if ag.Description == "" {
+			findings = append(findings, Finding{Message: "missing description"})
+		}
+		return findings
+	},
+}
+
+var invalidSemver = &agentRule{
+	id:          "invalid-semver",
+	description: "Versions are valid semantic versions",
+	severity:    SeverityError,
+	check: func(ag *agent.Agent) []Finding {
+		if ag.Version == "" {
+			return nil
+		}
+		if _, err := semver.Parse(ag.Version); err != nil {
+			return []Finding{{Line: keyLine(ag, "version"), Message: fmt.Sprintf("version %q is not a valid semantic version", ag.Version)}}
+		}
+		return nil
+	},
+}
+
+var nameMismatch = &agentRule{
+	id:          "name-mismatch",
+	description: "Names match the file name, or a skill's directory name",
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		if ag.Name == "" {
+			return nil
+		}
+
+		expected := strings.TrimSuffix(ag.FileName(), ".md")
+		what := "file name"
+		if ag.ArtifactKind() == agent.KindSkill {
+			expected = filepath.Base(filepath.Dir(ag.FilePath))
+			what = "directory name"
+		}
+
+		if ag.Name != expected {
+			return []Finding{{Line: keyLine(ag, "name"), Message: fmt.Sprintf("name %q does not match %s %q", ag.Name, what, expected)}}
+		}
+		return nil
+	},
+}
+
+var unknownClass = &agentRule{
+	id:          "unknown-class",
+	description: "Classes are one of the known agent classes",
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		if ag.Class == "" || agent.IsKnownClass(ag.Class) {
+			return nil
+		}
+		return []Finding{{
+			Line:    keyLine(ag, "class"),
+			Message: fmt.Sprintf("unknown class %q (known: %s)", ag.Class, strings.Join(agent.Classes(), ", ")),
+		}}
+	},
+}
+
+var descriptionLength = &agentRule{
+	id:          "description-length",
+	description: fmt.Sprintf("Descriptions are between %d and %d characters", MinDescriptionLength, MaxDescriptionLength),
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		length := len([]rune(ag.Description))
+		switch {
+		case length == 0:
+			return nil
+		case length < MinDescriptionLength:
+			return []Finding{{Line: keyLine(ag, "description"), Message: fmt.Sprintf("description is %d characters, shorter than %d", length, MinDescriptionLength)}}
+		case length > MaxDescriptionLength:
+			return []Finding{{Line: keyLine(ag, "description"), Message: fmt.Sprintf("description is %d characters, longer than %d", length, MaxDescriptionLength)}}
+		}
+		return nil
+	},
+}
+
+var unknownTool = &agentRule{
+	id:          "unknown-tool",
+	description: "Tools are ones Claude Code offers, or MCP tools",
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		var findings []Finding
+		for _, tool := range ag.Tools() {
+			// Permission patterns such as Bash(git:*) name their tool first
+			name, _, _ := strings.Cut(tool, "(")
+			if knownTools[name] || strings.HasPrefix(name, "mcp__") {
+				continue
+			}
+			findings = append(findings, Finding{Line: keyLine(ag, "tools"), Message: fmt.Sprintf("unknown tool %q", tool)})
+		}
+		return findings
+	},
+}
+
+var emptyBody = &agentRule{
+	id:          "empty-body",
+	description: "Agents have instructions after their frontmatter",
+	severity:    SeverityError,
+	check: func(ag *agent.Agent) []Finding {
+		if strings.TrimSpace(ag.Content) == "" {
+			return []Finding{{Message: "body is empty"}}
+		}
+		return nil
+	},
+}
+
+// schemaKeys are frontmatter keys a more specific rule checks, whose schema
+// diagnostics would repeat that rule's findings
+var schemaKeys = map[string]bool{
+	"version":     true, // invalid-semver
+	"class":       true, // unknown-class
+	"description": true, // description-length
+}
+
+var schema = &agentRule{
+	id:          "schema",
+	description: fmt.Sprintf("Frontmatter matches the agent schema (version %d)", agent.SchemaVersion),
+	severity:    SeverityWarning,
+	check: func(ag *agent.Agent) []Finding {
+		var findings []Finding
+		for _, d := range ag.Diagnostics {
+			// Missing keys are reported by missing-metadata
+			if schemaKeys[d.Key] || (ag.Frontmatter != nil && !ag.Frontmatter.Has(d.Key)) {
+				continue
+			}
+			findings = append(findings, Finding{Line: d.Line, Column: d.Column, Message: d.Message})
+		}
+		return findings
+	},
+}
+
+// loadError flags files that couldn't be loaded at all, such as frontmatter
+// that isn't valid YAML
+type loadError struct{}
+
+func (loadError) ID() string          { return "load-error" }
+func (loadError) Description() string { return "Files load" }
+func (loadError) Severity() Severity  { return SeverityError }
+
+func (r loadError) Check(set *Set, report func(Finding)) {
+	for _, source := range set.Sources {
+		if source.Report == nil {
+			continue
+		}
+		for _, e := range source.Report.Errors {
+			report(Finding{
+				Source:  source.Name,
+				File:    e.File,
+				Line:    e.Line,
+				Column:  e.Column,
+				Message: e.Message,
+			})
+		}
+	}
+}
+
+// duplicateName flags agents that share an ID. Within one source only one
+// of them can ever be deployed, which is an error; across sources the
+// higher priority source wins, which is worth a warning.
+type duplicateName struct{}
+
+func (duplicateName) ID() string { return "duplicate-name" }
+func (duplicateName) Description() string {
+	return "Names are unique within a source and across sources"
+}
+func (duplicateName) Severity() Severity { return SeverityError }
+
+func (r duplicateName) Check(set *Set, report func(Finding)) {
+	type seen struct {
+		source *Source
+		agent  *agent.Agent
+	}
+	byID := make(map[string][]seen)
+	var ids []string
+
+	for _, source := range set.Sources {
+		for _, ag := range source.Agents {
+			if ag.Name == "" {
+				continue
+			}
+			id := ag.ID()
+			if _, ok := byID[id]; !ok {
+				ids = append(ids, id)
+			}
+			byID[id] = append(byID[id], seen{source: source, agent: ag})
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		copies := byID[id]
+		if len(copies) < 2 {
+			continue
+		}
+
+		for i, c := range copies {
+			for j, other := range copies {
+				if i == j {
+					continue
+				}
+				f := Finding{Line: keyLine(c.agent, "name")}
+				if c.source == other.source {
+					f.Message = fmt.Sprintf("%s is also defined in %s", id, other.agent.FilePath)
+				} else {
+					f.Severity = SeverityWarning
+					f.Message = fmt.Sprintf("%s is also defined in source %s (%s)", id, other.source.Name, other.agent.FilePath)
+				}
+				report(at(c.agent, f))
+			}
+		}
+	}
+}
+
+// brokenReference flags requires entries that name agents no source
+// provides
+type brokenReference struct{}
+
+func (brokenReference) ID() string          { return "broken-reference" }
+func (brokenReference) Description() string { return "Required agents exist in a source" }
+func (brokenReference) Severity() Severity  { return SeverityError }
+
+func (r brokenReference) Check(set *Set, report func(Finding)) {
+	known := make(map[string]bool, len(set.Available))
+	for id := range set.Available {
+		known[id] = true
+	}
+	for _, source := range set.Sources {
+		for _, ag := range source.Agents {
+			known[ag.ID()] = true
+		}
+	}
+
+	for _, source := range set.Sources {
+		for _, ag := range source.Agents {
+			requires, err := ag.Requires()
+			if err != nil {
+				report(at(ag, Finding{Line: keyLine(ag, "requires"), Message: err.Error()}))
+				continue
+			}
+
+			for _, requirement := range requires {
+				req, err := spec.ParseRequirement(requirement)
+				if err != nil {
+					report(at(ag, Finding{Line: keyLine(ag, "requires"), Message: err.Error()}))
+					continue
+				}
+				if !known[req.Name] {
+					report(at(ag, Finding{Line: keyLine(ag, "requires"), Message: fmt.Sprintf("requires %s, which no source provides", req.Name)}))
+				}
+			}
+		}
+	}
+}
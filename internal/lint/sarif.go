@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// sarifSchema is the SARIF 2.1.0 schema reports point at
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog is a SARIF 2.1.0 log, the format code scanning tools read
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is one run of a tool in a SARIF log
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool and the rules it ran
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a rule
+type SARIFRule struct {
+	ID                   string           `json:"id"`
+	ShortDescription     SARIFMessage     `json:"shortDescription"`
+	DefaultConfiguration SARIFRuleDefault `json:"defaultConfiguration"`
+}
+
+// SARIFRuleDefault is a rule's default configuration
+type SARIFRuleDefault struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is one finding
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation is where a finding was made
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file, and optionally a line in it
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is a file URI, relative to the linted root when
+// possible
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a line in a file, and optionally a column in it
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIF converts a report to a SARIF log. File paths are made relative to
+// root when they are inside it, so code scanning can match them to the
+// repository.
+func SARIF(report *Report, rules []Rule, version, root string) *SARIFLog {
+	driver := SARIFDriver{
+		Name:           "cami",
+		Version:        version,
+		InformationURI: "https://github.com/lando-labs/cami",
+		Rules:          make([]SARIFRule, len(rules)),
+	}
+	for i, rule := range rules {
+		driver.Rules[i] = SARIFRule{
+			ID:                   rule.ID(),
+			ShortDescription:     SARIFMessage{Text: rule.Description()},
+			DefaultConfiguration: SARIFRuleDefault{Level: sarifLevel(rule.Severity())},
+		}
+	}
+
+	results := make([]SARIFResult, len(report.Findings))
+	for i, f := range report.Findings {
+		location := SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: sarifURI(f.File, root)},
+		}
+		if f.Line > 0 {
+			location.Region = &SARIFRegion{StartLine: f.Line, StartColumn: f.Column}
+		}
+
+		results[i] = SARIFResult{
+			RuleID:    f.Rule,
+			Level:     sarifLevel(f.Severity),
+			Message:   SARIFMessage{Text: f.Message},
+			Locations: []SARIFLocation{{PhysicalLocation: location}},
+		}
+	}
+
+	return &SARIFLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifLevel maps a severity to a SARIF level
+func sarifLevel(s Severity) string {
+	if s == SeverityInfo {
+		return "note"
+	}
+	return string(s)
+}
+
+// sarifURI returns a file's path relative to root, slash-separated, or a
+// file URI if it lies outside root
+func sarifURI(file, root string) string {
+	if root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	if filepath.IsAbs(file) {
+		return "file://" + filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(file)
+}
@@ -0,0 +1,375 @@
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/resolve"
+	"github.com/lando/cami/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Filename is the lock file path relative to the project root
+const Filename = ".claude/cami-lock.yaml"
+
+// Version is the lock file schema version
+const Version = "1"
+
+// Lock pins every agent deployed to a project to an exact source revision,
+// so the project can be reinstalled without the CAMI workspace it was
+// deployed from
+type Lock struct {
+	Version string  `yaml:"version" json:"version"`
+	Agents  []Entry `yaml:"agents" json:"agents"`
+}
+
+// Entry is one locked agent or command
+type Entry struct {
+	Name        string `yaml:"name" json:"name"` // Agent name, or /name for a command
+	Version     string `yaml:"version,omitempty" json:"version,omitempty"`
+	Source      string `yaml:"source,omitempty" json:"source,omitempty"` // Source name
+	Remote      string `yaml:"remote" json:"remote"`                     // Git remote the source was cloned from
+	Commit      string `yaml:"commit" json:"commit"`                     // Resolved commit SHA
+	Path        string `yaml:"path" json:"path"`                         // Slash-separated file path within the repository
+	ContentHash string `yaml:"content_hash" json:"content_hash"`         // Same hash as the project manifest
+}
+
+// Find returns the entry for an agent, or nil
+func (l *Lock) Find(name string) *Entry {
+	for i := range l.Agents {
+		if l.Agents[i].Name == name {
+			return &l.Agents[i]
+		}
+	}
+	return nil
+}
+
+// CheckSpec reports whether the lock still satisfies a project spec: every
+// declared agent is locked, at a version inside its constraint
+func (l *Lock) CheckSpec(s *spec.Spec) error {
+	var problems []string
+
+	for _, req := range s.Agents {
+		entry := l.Find(req.Name)
+		if entry == nil {
+			problems = append(problems, fmt.Sprintf("%s is not locked", req.Name))
+			continue
+		}
+
+		ag := &agent.Agent{Name: entry.Name, Version: entry.Version}
+		if err := resolve.CheckRequirement(req, ag); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("lock file is out of date with %s: %s", spec.Filename, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Exists reports whether a project has a lock file
+func Exists(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, Filename))
+	return err == nil
+}
+
+// Read reads a project's lock file
+func Read(projectPath string) (*Lock, error) {
+	lockPath := filepath.Join(projectPath, Filename)
+
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("lock file not found at %s", lockPath)
+		}
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var l Lock
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+
+	return &l, nil
+}
+
+// Write writes a project's lock file
+func Write(projectPath string, l *Lock) error {
+	lockPath := filepath.Join(projectPath, Filename)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create .claude directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	return nil
+}
+
+// Generate builds a lock from a project's manifest. Each agent is pinned to
+// the commit it was deployed from, and the file at that commit is checked
+// against the deployed content hash so the lock never records a revision
+// that would install something different.
+func Generate(projectPath string, sources []config.AgentSource) (*Lock, error) {
+	pm, err := manifest.ReadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lock{Version: Version}
+	var unlockable []string
+
+	for _, deployed := range pm.Agents {
+		if deployed.SourcePath == "" {
+			unlockable = append(unlockable, deployed.ID())
+			continue
+		}
+
+		entry, err := lockEntry(deployed, sources)
+		if err != nil {
+			return nil, fmt.Errorf("cannot lock %s: %w", deployed.ID(), err)
+		}
+		if entry == nil {
+			unlockable = append(unlockable, deployed.ID())
+			continue
+		}
+
+		l.Agents = append(l.Agents, *entry)
+	}
+
+	if len(unlockable) > 0 {
+		return nil, fmt.Errorf("agents without a git source cannot be locked: %s", strings.Join(unlockable, ", "))
+	}
+
+	sort.Slice(l.Agents, func(i, j int) bool { return l.Agents[i].Name < l.Agents[j].Name })
+
+	return l, nil
+}
+
+// lockEntry pins one deployed agent, returning nil if its source is not a
+// git repository with a remote
+func lockEntry(deployed manifest.DeployedAgent, sources []config.AgentSource) (*Entry, error) {
+	dir := filepath.Dir(deployed.SourcePath)
+	if !git.IsRepo(dir) {
+		return nil, nil
+	}
+
+	remote := sourceRemote(deployed, sources, dir)
+	if remote == "" {
+		return nil, nil
+	}
+
+	commit := deployed.Commit
+	if commit == "" {
+		var err error
+		if commit, err = git.Commit(dir, "HEAD"); err != nil {
+			return nil, err
+		}
+	}
+
+	prefix, err := git.Prefix(dir)
+	if err != nil {
+		return nil, err
+	}
+	filePath := path.Join(prefix, filepath.Base(deployed.SourcePath))
+
+	ag, err := readAgent(dir, commit, filePath, deployed.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if hash := manifest.HashArtifact(ag); hash != deployed.ContentHash {
+		return nil, fmt.Errorf("deployed content does not match %s at %s; redeploy or sync before locking", filePath, shortCommit(commit))
+	}
+
+	return &Entry{
+		Name:        deployed.ID(),
+		Version:     deployed.Version,
+		Source:      deployed.Source,
+		Remote:      remote,
+		Commit:      commit,
+		Path:        filePath,
+		ContentHash: deployed.ContentHash,
+	}, nil
+}
+
+// sourceRemote finds the remote of the source an agent was deployed from:
+// the configured remote if the source is known, otherwise the repository's
+// origin
+func sourceRemote(deployed manifest.DeployedAgent, sources []config.AgentSource, dir string) string {
+	for _, source := range sources {
+		if source.Git == nil || source.Git.Remote == "" {
+			continue
+		}
+		if source.Name == deployed.Source || strings.HasPrefix(deployed.SourcePath, source.Path+string(filepath.Separator)) {
+			return source.Git.Remote
+		}
+	}
+
+	remote, err := git.Run(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	return remote
+}
+
+// Fetch clones each locked remote into a temporary directory and reads every
+// agent at its locked commit. Content that does not match its locked hash is
+// an error: the lock is only useful if it installs exactly what it records.
+func Fetch(l *Lock) ([]*agent.Agent, error) {
+	byRemote := make(map[string][]Entry)
+	var remotes []string
+	for _, entry := range l.Agents {
+		if entry.Remote == "" || entry.Commit == "" {
+			return nil, fmt.Errorf("%s has no locked remote and commit", entry.Name)
+		}
+		if _, ok := byRemote[entry.Remote]; !ok {
+			remotes = append(remotes, entry.Remote)
+		}
+		byRemote[entry.Remote] = append(byRemote[entry.Remote], entry)
+	}
+
+	fetched := make(map[string]*agent.Agent)
+	for _, remote := range remotes {
+		if err := fetchRemote(remote, byRemote[remote], fetched); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the lock's order
+	agents := make([]*agent.Agent, 0, len(l.Agents))
+	for _, entry := range l.Agents {
+		agents = append(agents, fetched[entry.Name])
+	}
+	return agents, nil
+}
+
+func fetchRemote(remote string, entries []Entry, fetched map[string]*agent.Agent) error {
+	tmpDir, err := os.MkdirTemp("", "cami-lock-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := git.Run(tmpDir, "clone", "-q", "--no-checkout", remote, "."); err != nil {
+		return fmt.Errorf("failed to clone %s: %w", remote, err)
+	}
+
+	for _, entry := range entries {
+		kind, _ := agent.ParseID(entry.Name)
+		ag, err := readAgent(tmpDir, entry.Commit, entry.Path, kind)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+
+		if hash := manifest.HashArtifact(ag); hash != entry.ContentHash {
+			return fmt.Errorf("%s: content hash mismatch at %s (locked %s, got %s)", entry.Name, shortCommit(entry.Commit), entry.ContentHash, hash)
+		}
+
+		// The clone is temporary; record where the agent came from instead
+		ag.FilePath = entry.Path
+		ag.Source = entry.Source
+		fetched[entry.Name] = ag
+	}
+
+	return nil
+}
+
+// Verify confirms the agent files deployed to a project match the lock
+func Verify(projectPath string, l *Lock) error {
+	var mismatched []string
+
+	for _, entry := range l.Agents {
+		kind, name := agent.ParseID(entry.Name)
+		agentPath := filepath.Join(projectPath, ".claude", kind.Dir(), path.Base(entry.Path))
+		if kind == agent.KindSkill {
+			agentPath = filepath.Join(projectPath, ".claude", kind.Dir(), name, agent.SkillFile)
+		}
+		hash, err := manifest.CalculateArtifactHash(kind, agentPath)
+		if err != nil || hash != entry.ContentHash {
+			mismatched = append(mismatched, entry.Name)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return fmt.Errorf("deployed agents do not match the lock file: %s", strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+// readAgent parses the agent, command or skill at filePath as of commit. For a
+// skill, filePath is its SKILL.md and the rest of its directory is read too;
+// for an agent, the sibling .mcp.json declaring its MCP servers, if any.
+func readAgent(dir, commit, filePath string, kind agent.Kind) (*agent.Agent, error) {
+	paths := []string{filePath}
+	switch kind {
+	case agent.KindAgent:
+		paths = append(paths, agent.MCPSiblingPath(filePath))
+	case agent.KindSkill:
+		var err error
+		if paths, err = skillPaths(dir, commit, filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := git.ReadFiles(dir, commit, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("%s not found at %s", filePath, shortCommit(commit))
+	}
+
+	ag, err := agent.Parse(kind, data, filepath.Join(dir, filepath.FromSlash(filePath)))
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case agent.KindAgent:
+		ag.MCPConfig = files[agent.MCPSiblingPath(filePath)]
+	case agent.KindSkill:
+		ag.Files = agent.SkillFiles(files, filePath)
+	}
+	return ag, nil
+}
+
+// skillPaths lists the files in the directory of the skill defined at
+// skillFile as of commit
+func skillPaths(dir, commit, skillFile string) ([]string, error) {
+	all, err := git.ListFiles(dir, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := path.Dir(skillFile) + "/"
+	var paths []string
+	for _, p := range all {
+		if strings.HasPrefix(p, prefix) {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
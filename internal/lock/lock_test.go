@@ -0,0 +1,261 @@
+package lock
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const frontendV1 = "---\nname: frontend\nversion: 1.0.0\ndescription: Frontend agent\n---\n# Frontend v1\n"
+const frontendV2 = "---\nname: frontend\nversion: 2.0.0\ndescription: Frontend agent\n---\n# Frontend v2\n"
+
+// setupSource creates an origin repository with agents/frontend.md and a
+// clone of it, the way a source is added to a workspace
+func setupSource(t *testing.T) (origin, source string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	origin = t.TempDir()
+	_, err := git.Run(origin, "init", "-q")
+	require.NoError(t, err)
+	commitFile(t, origin, "agents/frontend.md", frontendV1)
+
+	source = filepath.Join(t.TempDir(), "source")
+	_, err = git.Run(filepath.Dir(source), "clone", "-q", origin, source)
+	require.NoError(t, err)
+
+	return origin, source
+}
+
+func commitFile(t *testing.T, repo, name, content string) {
+	t.Helper()
+	path := filepath.Join(repo, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	_, err := git.Run(repo, "add", "-A")
+	require.NoError(t, err)
+	_, err = git.Run(repo, "commit", "-q", "-m", "update "+name)
+	require.NoError(t, err)
+}
+
+// recordDeployment writes a project manifest as if the agent at agentPath
+// had just been deployed
+func recordDeployment(t *testing.T, projectPath, agentPath, commit string) {
+	t.Helper()
+	ag, err := agent.LoadAgent(agentPath)
+	require.NoError(t, err)
+
+	pm := &manifest.ProjectManifest{
+		Version: "2",
+		State:   manifest.StateCAMINative,
+		Agents: []manifest.DeployedAgent{{
+			Name:        ag.Name,
+			Version:     ag.Version,
+			Source:      "team",
+			SourcePath:  agentPath,
+			DeployedAt:  time.Now(),
+			ContentHash: manifest.HashContent([]byte(ag.FullContent())),
+			Commit:      commit,
+		}},
+	}
+	require.NoError(t, manifest.WriteProjectManifest(projectPath, pm))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("pins agents to their commit", func(t *testing.T) {
+		origin, source := setupSource(t)
+		project := t.TempDir()
+		recordDeployment(t, project, filepath.Join(source, "agents", "frontend.md"), "")
+
+		l, err := Generate(project, nil)
+		require.NoError(t, err)
+
+		head, err := git.Commit(source, "HEAD")
+		require.NoError(t, err)
+
+		require.Len(t, l.Agents, 1)
+		entry := l.Agents[0]
+		assert.Equal(t, "frontend", entry.Name)
+		assert.Equal(t, "1.0.0", entry.Version)
+		assert.Equal(t, "team", entry.Source)
+		assert.Equal(t, origin, entry.Remote)
+		assert.Equal(t, head, entry.Commit)
+		assert.Equal(t, "agents/frontend.md", entry.Path)
+	})
+
+	t.Run("prefers the configured remote", func(t *testing.T) {
+		_, source := setupSource(t)
+		project := t.TempDir()
+		recordDeployment(t, project, filepath.Join(source, "agents", "frontend.md"), "")
+
+		sources := []config.AgentSource{{
+			Name: "team",
+			Path: source,
+			Git:  &config.GitConfig{Enabled: true, Remote: "git@example.com:team/agents.git"},
+		}}
+		l, err := Generate(project, sources)
+		require.NoError(t, err)
+		assert.Equal(t, "git@example.com:team/agents.git", l.Agents[0].Remote)
+	})
+
+	t.Run("recorded commit survives later source changes", func(t *testing.T) {
+		_, source := setupSource(t)
+		project := t.TempDir()
+		agentPath := filepath.Join(source, "agents", "frontend.md")
+
+		deployed, err := git.Commit(source, "HEAD")
+		require.NoError(t, err)
+		recordDeployment(t, project, agentPath, deployed)
+		commitFile(t, source, "agents/frontend.md", frontendV2)
+
+		l, err := Generate(project, nil)
+		require.NoError(t, err)
+		assert.Equal(t, deployed, l.Agents[0].Commit)
+	})
+
+	t.Run("rejects content that differs from the commit", func(t *testing.T) {
+		_, source := setupSource(t)
+		project := t.TempDir()
+		agentPath := filepath.Join(source, "agents", "frontend.md")
+
+		require.NoError(t, os.WriteFile(agentPath, []byte(frontendV2), 0644))
+		recordDeployment(t, project, agentPath, "")
+
+		_, err := Generate(project, nil)
+		assert.ErrorContains(t, err, "deployed content does not match")
+	})
+
+	t.Run("rejects agents outside git", func(t *testing.T) {
+		dir := t.TempDir()
+		agentPath := filepath.Join(dir, "frontend.md")
+		require.NoError(t, os.WriteFile(agentPath, []byte(frontendV1), 0644))
+
+		project := t.TempDir()
+		recordDeployment(t, project, agentPath, "")
+
+		_, err := Generate(project, nil)
+		assert.EqualError(t, err, "agents without a git source cannot be locked: frontend")
+	})
+}
+
+func TestReadWrite(t *testing.T) {
+	project := t.TempDir()
+	assert.False(t, Exists(project))
+
+	_, err := Read(project)
+	assert.ErrorContains(t, err, "lock file not found")
+
+	l := &Lock{Version: Version, Agents: []Entry{{
+		Name:        "frontend",
+		Version:     "1.0.0",
+		Remote:      "git@example.com:team/agents.git",
+		Commit:      "0123456789abcdef0123456789abcdef01234567",
+		Path:        "agents/frontend.md",
+		ContentHash: "sha256:abc",
+	}}}
+	require.NoError(t, Write(project, l))
+	assert.True(t, Exists(project))
+
+	read, err := Read(project)
+	require.NoError(t, err)
+	assert.Equal(t, l, read)
+	assert.NotNil(t, read.Find("frontend"))
+	assert.Nil(t, read.Find("backend"))
+}
+
+func TestFetch(t *testing.T) {
+	lockSource := func(t *testing.T) *Lock {
+		_, source := setupSource(t)
+		project := t.TempDir()
+		recordDeployment(t, project, filepath.Join(source, "agents", "frontend.md"), "")
+
+		l, err := Generate(project, nil)
+		require.NoError(t, err)
+		return l
+	}
+
+	t.Run("reads agents at the locked commit", func(t *testing.T) {
+		l := lockSource(t)
+
+		agents, err := Fetch(l)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		assert.Equal(t, "1.0.0", agents[0].Version)
+		assert.Equal(t, "frontend.md", agents[0].FileName())
+		assert.Equal(t, frontendV1, agents[0].FullContent())
+	})
+
+	t.Run("fails on hash mismatch", func(t *testing.T) {
+		l := lockSource(t)
+		l.Agents[0].ContentHash = manifest.HashContent([]byte(frontendV2))
+
+		_, err := Fetch(l)
+		assert.ErrorContains(t, err, "content hash mismatch")
+	})
+
+	t.Run("fails on unknown commit", func(t *testing.T) {
+		l := lockSource(t)
+		l.Agents[0].Commit = "0123456789abcdef0123456789abcdef01234567"
+
+		_, err := Fetch(l)
+		assert.Error(t, err)
+	})
+
+	t.Run("fails without a remote", func(t *testing.T) {
+		_, err := Fetch(&Lock{Agents: []Entry{{Name: "frontend"}}})
+		assert.EqualError(t, err, "frontend has no locked remote and commit")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	project := t.TempDir()
+	agentsDir := filepath.Join(project, ".claude", "agents")
+	require.NoError(t, os.MkdirAll(agentsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "frontend.md"), []byte(frontendV1), 0644))
+
+	l := &Lock{Agents: []Entry{{
+		Name:        "frontend",
+		Path:        "agents/frontend.md",
+		ContentHash: manifest.HashContent([]byte(frontendV1)),
+	}}}
+	assert.NoError(t, Verify(project, l))
+
+	l.Agents = append(l.Agents, Entry{Name: "backend", Path: "backend.md"})
+	assert.EqualError(t, Verify(project, l), "deployed agents do not match the lock file: backend")
+}
+
+func TestCheckSpec(t *testing.T) {
+	l := &Lock{Agents: []Entry{
+		{Name: "frontend", Version: "2.1.0"},
+		{Name: "backend", Version: "1.0.0"},
+	}}
+
+	assert.NoError(t, l.CheckSpec(&spec.Spec{Agents: []spec.Requirement{
+		{Name: "frontend", Version: "^2"},
+		{Name: "backend"},
+	}}))
+
+	err := l.CheckSpec(&spec.Spec{Agents: []spec.Requirement{
+		{Name: "frontend", Version: "^3"},
+		{Name: "database"},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "frontend 2.1.0 does not satisfy constraint ^3")
+	assert.Contains(t, err.Error(), "database is not locked")
+}
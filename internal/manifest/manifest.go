@@ -7,9 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/lando/cami/internal/agent"
 	"gopkg.in/yaml.v3"
 )
 
@@ -23,19 +25,24 @@ const (
 	StateNonCAMI    ProjectState = "non-cami"    // No agents directory
 )
 
-// DeployedAgent represents an agent in a manifest
+// DeployedAgent represents an agent, or another artifact such as a slash
+// command, in a manifest
 type DeployedAgent struct {
-	Name           string    `yaml:"name"`
-	Version        string    `yaml:"version"`
-	Source         string    `yaml:"source"`      // Source name
-	SourcePath     string    `yaml:"source_path"` // Full path to source file
-	Priority       int       `yaml:"priority"`
-	DeployedAt     time.Time `yaml:"deployed_at"`
-	ContentHash    string    `yaml:"content_hash"`            // SHA256 of normalized content
-	MetadataHash   string    `yaml:"metadata_hash"`           // SHA256 of frontmatter only
-	CustomOverride bool      `yaml:"custom_override"`         // Intentionally customized
-	NeedsUpgrade   bool      `yaml:"needs_upgrade,omitempty"` // Missing version, etc.
-	Origin         string    `yaml:"origin,omitempty"`        // "cami", "external", "manual"
+	Name           string     `yaml:"name"`
+	Kind           agent.Kind `yaml:"kind,omitempty"` // "command" or "skill"; empty for agents
+	Version        string     `yaml:"version"`
+	Source         string     `yaml:"source"`      // Source name
+	SourcePath     string     `yaml:"source_path"` // Full path to source file
+	Priority       int        `yaml:"priority"`
+	DeployedAt     time.Time  `yaml:"deployed_at"`
+	ContentHash    string     `yaml:"content_hash"`            // SHA256 of normalized content
+	MetadataHash   string     `yaml:"metadata_hash"`           // SHA256 of frontmatter only
+	CustomOverride bool       `yaml:"custom_override"`         // Intentionally customized
+	NeedsUpgrade   bool       `yaml:"needs_upgrade,omitempty"` // Missing version, etc.
+	Origin         string     `yaml:"origin,omitempty"`        // "cami", "external", "manual"
+	Commit         string     `yaml:"commit,omitempty"`        // Source commit deployed from, for git sources
+	MCPServers     []string   `yaml:"mcp_servers,omitempty"`   // MCP servers CAMI added to .mcp.json for this agent
+	Bundle         string     `yaml:"bundle,omitempty"`        // Bundle the agent was last deployed from
 }
 
 // ProjectManifest represents a project's deployment manifest (local)
@@ -103,9 +110,9 @@ func WriteProjectManifest(projectPath string, manifest *ProjectManifest) error {
 		return fmt.Errorf("failed to create .claude directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(manifest)
+	data, err := MarshalProjectManifest(manifest)
 	if err != nil {
-		return fmt.Errorf("failed to marshal project manifest: %w", err)
+		return err
 	}
 
 	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
@@ -115,14 +122,86 @@ func WriteProjectManifest(projectPath string, manifest *ProjectManifest) error {
 	return nil
 }
 
-// ReadCentralManifest reads the central deployments manifest
-func ReadCentralManifest() (*CentralManifest, error) {
+// MarshalProjectManifest encodes a project manifest as it is written to disk
+func MarshalProjectManifest(manifest *ProjectManifest) ([]byte, error) {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project manifest: %w", err)
+	}
+	return data, nil
+}
+
+// ID returns the name the entry is tracked by: the agent name, or /name for
+// a slash command
+func (d *DeployedAgent) ID() string {
+	return agent.ID(d.Kind, d.Name)
+}
+
+// FindAgent returns the manifest entry for an agent, or for a command given
+// as /name, or nil if it isn't tracked
+func (m *ProjectManifest) FindAgent(id string) *DeployedAgent {
+	for i := range m.Agents {
+		if m.Agents[i].ID() == id {
+			return &m.Agents[i]
+		}
+	}
+	return nil
+}
+
+// RemoveAgent drops the manifest entry for an agent, or for a command given
+// as /name, reporting whether it was tracked
+func (m *ProjectManifest) RemoveAgent(id string) bool {
+	for i := range m.Agents {
+		if m.Agents[i].ID() == id {
+			m.Agents = append(m.Agents[:i], m.Agents[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateCentralDeployment records a project's manifest in the central manifest
+func UpdateCentralDeployment(projectPath string, projectManifest *ProjectManifest) error {
+	centralManifest, err := ReadCentralManifest()
+	if err != nil {
+		return err
+	}
+
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if centralManifest.Deployments == nil {
+		centralManifest.Deployments = make(map[string]ProjectDeployment)
+	}
+
+	centralManifest.Deployments[absPath] = ProjectDeployment{
+		State:        projectManifest.State,
+		NormalizedAt: projectManifest.NormalizedAt,
+		LastScanned:  time.Now(),
+		Agents:       projectManifest.Agents,
+	}
+
+	return WriteCentralManifest(centralManifest)
+}
+
+// CentralManifestPath returns the path of the central deployments manifest
+func CentralManifestPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	manifestPath := filepath.Join(homeDir, "cami-workspace", CentralManifestFilename)
+	return filepath.Join(homeDir, "cami-workspace", CentralManifestFilename), nil
+}
+
+// ReadCentralManifest reads the central deployments manifest
+func ReadCentralManifest() (*CentralManifest, error) {
+	manifestPath, err := CentralManifestPath()
+	if err != nil {
+		return nil, err
+	}
 
 	data, err := os.ReadFile(manifestPath)
 	if err != nil {
@@ -148,18 +227,15 @@ func ReadCentralManifest() (*CentralManifest, error) {
 
 // WriteCentralManifest writes the central deployments manifest
 func WriteCentralManifest(manifest *CentralManifest) error {
-	homeDir, err := os.UserHomeDir()
+	manifestPath, err := CentralManifestPath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	camiDir := filepath.Join(homeDir, "cami-workspace")
-	if err := os.MkdirAll(camiDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0755); err != nil {
 		return fmt.Errorf("failed to create cami-workspace directory: %w", err)
 	}
 
-	manifestPath := filepath.Join(camiDir, CentralManifestFilename)
-
 	// Update last updated timestamp
 	manifest.LastUpdated = time.Now()
 
@@ -182,11 +258,45 @@ func CalculateContentHash(filePath string) (string, error) {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Normalize content (strip excess whitespace, normalize line endings)
-	normalized := NormalizeContent(data)
+	return HashContent(data), nil
+}
 
-	hash := sha256.Sum256(normalized)
-	return fmt.Sprintf("sha256:%x", hash), nil
+// CalculateTreeHash calculates the SHA256 tree hash of every file below a
+// directory, such as a deployed skill
+func CalculateTreeHash(dir string) (string, error) {
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	return HashTree(files), nil
+}
+
+// CalculateArtifactHash calculates the hash recorded for an artifact deployed
+// at filePath: its content hash, or for a skill, whose file is its SKILL.md,
+// the tree hash of its directory
+func CalculateArtifactHash(kind agent.Kind, filePath string) (string, error) {
+	if kind == agent.KindSkill {
+		return CalculateTreeHash(filepath.Dir(filePath))
+	}
+	return CalculateContentHash(filePath)
 }
 
 // CalculateMetadataHash calculates SHA256 hash of frontmatter only
@@ -196,6 +306,46 @@ func CalculateMetadataHash(filePath string) (string, error) {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return HashMetadata(data)
+}
+
+// HashContent calculates the SHA256 hash of normalized content
+func HashContent(data []byte) string {
+	// Normalize content (strip excess whitespace, normalize line endings)
+	normalized := NormalizeContent(data)
+
+	hash := sha256.Sum256(normalized)
+	return fmt.Sprintf("sha256:%x", hash)
+}
+
+// HashTree calculates the SHA256 hash of a set of files keyed by
+// slash-separated path. Each file's content is normalized as in HashContent,
+// and renaming a file changes the hash.
+func HashTree(files map[string][]byte) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(hash, "%s\x00%s\n", path, HashContent(files[path]))
+	}
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil))
+}
+
+// HashArtifact calculates the hash recorded for an artifact when it is
+// deployed: the content hash of its file, or the tree hash of a skill
+func HashArtifact(ag *agent.Agent) string {
+	if ag.ArtifactKind() == agent.KindSkill {
+		return HashTree(ag.Tree())
+	}
+	return HashContent([]byte(ag.FullContent()))
+}
+
+// HashMetadata calculates the SHA256 hash of the frontmatter in content
+func HashMetadata(data []byte) (string, error) {
 	// Extract frontmatter
 	frontmatter, err := extractFrontmatter(data)
 	if err != nil {
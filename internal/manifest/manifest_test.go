@@ -3,9 +3,11 @@ package manifest
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lando/cami/internal/agent"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -90,6 +92,33 @@ func TestProjectManifestReadWrite(t *testing.T) {
 	})
 }
 
+func TestRemoveAgent(t *testing.T) {
+	m := &ProjectManifest{Agents: []DeployedAgent{{Name: "frontend"}, {Name: "backend"}, {Name: "qa"}}}
+
+	t.Run("removes a tracked agent", func(t *testing.T) {
+		assert.True(t, m.RemoveAgent("backend"))
+		assert.Nil(t, m.FindAgent("backend"))
+		require.Len(t, m.Agents, 2)
+		assert.Equal(t, "frontend", m.Agents[0].Name)
+		assert.Equal(t, "qa", m.Agents[1].Name)
+	})
+
+	t.Run("untracked agent", func(t *testing.T) {
+		assert.False(t, m.RemoveAgent("backend"))
+		assert.Len(t, m.Agents, 2)
+	})
+
+	t.Run("commands are matched by /name", func(t *testing.T) {
+		m := &ProjectManifest{Agents: []DeployedAgent{{Name: "review"}, {Name: "review", Kind: agent.KindCommand}}}
+
+		require.NotNil(t, m.FindAgent("/review"))
+		assert.Equal(t, agent.KindCommand, m.FindAgent("/review").Kind)
+		assert.True(t, m.RemoveAgent("/review"))
+		require.Len(t, m.Agents, 1)
+		assert.Equal(t, "review", m.Agents[0].ID())
+	})
+}
+
 func TestCentralManifestReadWrite(t *testing.T) {
 	t.Run("write and read central manifest", func(t *testing.T) {
 		// Override home directory for testing
@@ -255,6 +284,39 @@ This is a test agent.`
 	})
 }
 
+func TestHashTree(t *testing.T) {
+	files := map[string][]byte{
+		"SKILL.md":       []byte("---\nname: pdf\n---\nUse the scripts.\n"),
+		"scripts/run.sh": []byte("echo run\n"),
+	}
+	hash := HashTree(files)
+	assert.True(t, strings.HasPrefix(hash, "sha256:"))
+
+	t.Run("matches the directory it was written to", func(t *testing.T) {
+		dir := t.TempDir()
+		for path, data := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, path), data, 0644))
+		}
+
+		treeHash, err := CalculateTreeHash(dir)
+		require.NoError(t, err)
+		assert.Equal(t, hash, treeHash)
+
+		artifactHash, err := CalculateArtifactHash(agent.KindSkill, filepath.Join(dir, "SKILL.md"))
+		require.NoError(t, err)
+		assert.Equal(t, hash, artifactHash)
+	})
+
+	t.Run("renaming or changing a file changes the hash", func(t *testing.T) {
+		renamed := map[string][]byte{"SKILL.md": files["SKILL.md"], "scripts/start.sh": files["scripts/run.sh"]}
+		assert.NotEqual(t, hash, HashTree(renamed))
+
+		changed := map[string][]byte{"SKILL.md": files["SKILL.md"], "scripts/run.sh": []byte("echo stop\n")}
+		assert.NotEqual(t, hash, HashTree(changed))
+	})
+}
+
 func TestCalculateMetadataHash(t *testing.T) {
 	t.Run("calculate hash of frontmatter only", func(t *testing.T) {
 		tmpDir := t.TempDir()
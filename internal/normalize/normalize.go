@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/lando/cami/internal/agent"
 	"github.com/lando/cami/internal/backup"
 	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/deploy"
 	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/plan"
 )
 
 // SourceIssue represents a problem with an agent in a source
@@ -174,52 +177,104 @@ func NormalizeSource(sourceName string, sourcePath string, options SourceNormali
 	}
 	result.BackupPath = backupPath
 
-	// Load all agents
+	fixes, err := fixSourceAgents(sourcePath, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write updated agents
+	for _, fix := range fixes {
+		result.Changes = append(result.Changes, fix.changes...)
+		if err := writeAgent(fix.agent); err != nil {
+			return nil, fmt.Errorf("failed to write agent %s: %w", fix.agent.FileName(), err)
+		}
+		result.AgentsUpdated++
+	}
+
+	// Create .camiignore if requested and missing
+	if needsCAMIIgnore(sourcePath, options) {
+		if err := createCAMIIgnoreTemplate(filepath.Join(sourcePath, ".camiignore")); err != nil {
+			return nil, fmt.Errorf("failed to create .camiignore: %w", err)
+		}
+		result.Changes = append(result.Changes, "Created .camiignore file")
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// PlanNormalizeSource plans what NormalizeSource would do without touching
+// disk: the backup, the agent files it would rewrite and the .camiignore it
+// would create
+func PlanNormalizeSource(sourceName string, sourcePath string, options SourceNormalizationOptions) (*plan.Plan, error) {
+	if _, err := os.Stat(sourcePath); err != nil {
+		return nil, fmt.Errorf("source path does not exist: %w", err)
+	}
+
+	p := plan.New("normalize source", sourcePath)
+	p.Write(backup.BackupPath(sourcePath, time.Now()), "backup of "+sourceName)
+
+	fixes, err := fixSourceAgents(sourcePath, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, fix := range fixes {
+		p.Write(fix.agent.FilePath, strings.Join(fix.changes, "; "))
+	}
+
+	if needsCAMIIgnore(sourcePath, options) {
+		p.Write(filepath.Join(sourcePath, ".camiignore"), "ignore template")
+	}
+
+	return p, nil
+}
+
+// sourceFix is an agent changed by normalization, with a description of
+// each change
+type sourceFix struct {
+	agent   *agent.Agent
+	changes []string
+}
+
+// fixSourceAgents applies the requested fixes to a source's agents in memory
+// and returns the agents that changed
+func fixSourceAgents(sourcePath string, options SourceNormalizationOptions) ([]sourceFix, error) {
 	agents, err := agent.LoadAgentsFromPath(sourcePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load agents: %w", err)
 	}
 
-	// Process each agent
+	var fixes []sourceFix
 	for _, ag := range agents {
-		updated := false
+		var changes []string
 
 		// Add version if missing and requested
 		if options.AddVersions && ag.Version == "" {
 			ag.Version = "1.0.0"
-			updated = true
-			result.Changes = append(result.Changes, fmt.Sprintf("Added version 1.0.0 to %s", ag.FileName()))
+			changes = append(changes, fmt.Sprintf("Added version 1.0.0 to %s", ag.FileName()))
 		}
 
 		// Add description placeholder if missing and requested
 		if options.AddDescriptions && ag.Description == "" {
 			ag.Description = fmt.Sprintf("Description for %s agent", ag.Name)
-			updated = true
-			result.Changes = append(result.Changes, fmt.Sprintf("Added description placeholder to %s", ag.FileName()))
+			changes = append(changes, fmt.Sprintf("Added description placeholder to %s", ag.FileName()))
 		}
 
-		// Write updated agent if changes were made
-		if updated {
-			if err := writeAgent(ag); err != nil {
-				return nil, fmt.Errorf("failed to write agent %s: %w", ag.FileName(), err)
-			}
-			result.AgentsUpdated++
+		if len(changes) > 0 {
+			fixes = append(fixes, sourceFix{agent: ag, changes: changes})
 		}
 	}
 
-	// Create .camiignore if requested and missing
-	if options.CreateCAMIIgnore {
-		camiIgnorePath := filepath.Join(sourcePath, ".camiignore")
-		if _, err := os.Stat(camiIgnorePath); os.IsNotExist(err) {
-			if err := createCAMIIgnoreTemplate(camiIgnorePath); err != nil {
-				return nil, fmt.Errorf("failed to create .camiignore: %w", err)
-			}
-			result.Changes = append(result.Changes, "Created .camiignore file")
-		}
-	}
+	return fixes, nil
+}
 
-	result.Success = true
-	return result, nil
+// needsCAMIIgnore reports whether normalizing would create a .camiignore
+func needsCAMIIgnore(sourcePath string, options SourceNormalizationOptions) bool {
+	if !options.CreateCAMIIgnore {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(sourcePath, ".camiignore"))
+	return os.IsNotExist(err)
 }
 
 // AnalyzeProject analyzes a project's state for normalization
@@ -373,7 +428,7 @@ func NormalizeProject(projectPath string, options ProjectNormalizationOptions, a
 	switch options.Level {
 	case LevelMinimal:
 		// Just create manifests
-		if err := createMinimalManifests(projectPath, analysis); err != nil {
+		if err := recordManifests(projectPath, minimalEntries(analysis)); err != nil {
 			return nil, fmt.Errorf("failed to create manifests: %w", err)
 		}
 		result.Changes = append(result.Changes, "Created project manifest")
@@ -381,7 +436,7 @@ func NormalizeProject(projectPath string, options ProjectNormalizationOptions, a
 
 	case LevelStandard:
 		// Create manifests with source links
-		if err := createStandardManifests(projectPath, analysis, availableSources); err != nil {
+		if err := recordManifests(projectPath, standardEntries(analysis, availableSources)); err != nil {
 			return nil, fmt.Errorf("failed to create manifests: %w", err)
 		}
 		result.Changes = append(result.Changes, "Created project manifest with source links")
@@ -396,6 +451,50 @@ func NormalizeProject(projectPath string, options ProjectNormalizationOptions, a
 	return result, nil
 }
 
+// PlanNormalizeProject plans what NormalizeProject would do without touching
+// disk: the backup, and the manifests it would write with the entries they
+// would gain, change or lose
+func PlanNormalizeProject(projectPath string, options ProjectNormalizationOptions, availableSources []config.AgentSource) (*plan.Plan, error) {
+	analysis, err := AnalyzeProject(projectPath, availableSources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze project: %w", err)
+	}
+
+	p := plan.New("normalize project", projectPath)
+	p.Write(backup.BackupPath(projectPath, time.Now()), "backup of project")
+
+	var found []manifest.DeployedAgent
+	switch options.Level {
+	case LevelMinimal:
+		found = minimalEntries(analysis)
+	case LevelStandard:
+		found = standardEntries(analysis, availableSources)
+	case LevelFull:
+		return nil, fmt.Errorf("full normalization not yet implemented")
+	default:
+		return p, nil
+	}
+
+	after, err := deploy.LoadProjectManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	deploy.RecordFound(after, found...)
+
+	var before *manifest.ProjectManifest
+	if analysis.HasManifest {
+		if before, err = manifest.ReadProjectManifest(projectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.WriteManifests(projectPath, before, after); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
 // writeAgent writes an agent back to its file
 func writeAgent(ag *agent.Agent) error {
 	content := ag.FullContent()
@@ -421,15 +520,9 @@ templates/
 	return os.WriteFile(path, []byte(template), 0644)
 }
 
-// createMinimalManifests creates basic project and central manifests
-func createMinimalManifests(projectPath string, analysis *ProjectAnalysis) error {
-	// Create project manifest
-	projectManifest := &manifest.ProjectManifest{
-		Version:      "2",
-		State:        manifest.StateCAMINative,
-		NormalizedAt: time.Now(),
-		Agents:       []manifest.DeployedAgent{},
-	}
+// minimalEntries builds basic manifest entries for the project's agents
+func minimalEntries(analysis *ProjectAnalysis) []manifest.DeployedAgent {
+	var entries []manifest.DeployedAgent
 
 	// Add agents from analysis
 	for _, ag := range analysis.Agents {
@@ -445,24 +538,15 @@ func createMinimalManifests(projectPath string, analysis *ProjectAnalysis) error
 			CustomOverride: false,
 			NeedsUpgrade:   false,
 		}
-		projectManifest.Agents = append(projectManifest.Agents, deployedAgent)
+		entries = append(entries, deployedAgent)
 	}
 
-	// Write project manifest
-	if err := manifest.WriteProjectManifest(projectPath, projectManifest); err != nil {
-		return fmt.Errorf("failed to write project manifest: %w", err)
-	}
-
-	// Update central manifest
-	if err := updateCentralManifest(projectPath, projectManifest); err != nil {
-		return fmt.Errorf("failed to update central manifest: %w", err)
-	}
-
-	return nil
+	return entries
 }
 
-// createStandardManifests creates manifests with source links
-func createStandardManifests(projectPath string, analysis *ProjectAnalysis, availableSources []config.AgentSource) error {
+// standardEntries builds manifest entries for the project's agents with
+// source links
+func standardEntries(analysis *ProjectAnalysis, availableSources []config.AgentSource) []manifest.DeployedAgent {
 	// Build map of source agents
 	sourceAgentsMap := make(map[string]*agent.Agent)
 	sourcePriorityMap := make(map[string]int)
@@ -482,13 +566,7 @@ func createStandardManifests(projectPath string, analysis *ProjectAnalysis, avai
 		}
 	}
 
-	// Create project manifest
-	projectManifest := &manifest.ProjectManifest{
-		Version:      "2",
-		State:        manifest.StateCAMINative,
-		NormalizedAt: time.Now(),
-		Agents:       []manifest.DeployedAgent{},
-	}
+	var entries []manifest.DeployedAgent
 
 	// Add agents with source links
 	for _, ag := range analysis.Agents {
@@ -518,44 +596,20 @@ func createStandardManifests(projectPath string, analysis *ProjectAnalysis, avai
 			deployedAgent.Priority = 999
 		}
 
-		projectManifest.Agents = append(projectManifest.Agents, deployedAgent)
+		entries = append(entries, deployedAgent)
 	}
 
-	// Write project manifest
-	if err := manifest.WriteProjectManifest(projectPath, projectManifest); err != nil {
-		return fmt.Errorf("failed to write project manifest: %w", err)
-	}
-
-	// Update central manifest
-	if err := updateCentralManifest(projectPath, projectManifest); err != nil {
-		return fmt.Errorf("failed to update central manifest: %w", err)
-	}
-
-	return nil
+	return entries
 }
 
-// updateCentralManifest updates the central manifest with project info
-func updateCentralManifest(projectPath string, projectManifest *manifest.ProjectManifest) error {
-	// Read central manifest
-	centralManifest, err := manifest.ReadCentralManifest()
+// recordManifests records entries for the project's agents in its manifest,
+// keeping what the manifest already tracks, and in the central manifest
+func recordManifests(projectPath string, entries []manifest.DeployedAgent) error {
+	projectManifest, err := deploy.LoadProjectManifest(projectPath)
 	if err != nil {
-		return err
-	}
-
-	// Get absolute path
-	absPath, err := filepath.Abs(projectPath)
-	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
-	}
-
-	// Update or add deployment
-	centralManifest.Deployments[absPath] = manifest.ProjectDeployment{
-		State:        projectManifest.State,
-		NormalizedAt: projectManifest.NormalizedAt,
-		LastScanned:  time.Now(),
-		Agents:       projectManifest.Agents,
+		return fmt.Errorf("failed to read project manifest: %w", err)
 	}
 
-	// Write central manifest
-	return manifest.WriteCentralManifest(centralManifest)
+	deploy.RecordFound(projectManifest, entries...)
+	return deploy.WriteManifests(projectPath, projectManifest)
 }
@@ -6,8 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/backup"
 	"github.com/lando/cami/internal/config"
 	"github.com/lando/cami/internal/manifest"
+	"github.com/lando/cami/internal/plan"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -376,6 +379,44 @@ func TestNormalizeProject(t *testing.T) {
 		assert.Len(t, deployment.Agents, 1)
 	})
 
+	t.Run("existing entries are kept", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		agentsDir := filepath.Join(tmpDir, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+		createTestAgent(t, agentsDir, "agent1.md", "agent1", "1.1.0", "Description")
+
+		require.NoError(t, manifest.WriteProjectManifest(tmpDir, &manifest.ProjectManifest{
+			Version: "1",
+			State:   manifest.StateCAMINative,
+			Agents: []manifest.DeployedAgent{
+				{Name: "agent1", Version: "1.0.0", Source: "team", SourcePath: "/src/team/agent1.md", CustomOverride: true, Origin: "cami", MCPServers: []string{"github"}, Bundle: "web"},
+				{Name: "review", Kind: agent.KindCommand, Version: "1.0.0", Origin: "cami"},
+			},
+		}))
+
+		_, err := NormalizeProject(tmpDir, ProjectNormalizationOptions{Level: LevelMinimal}, []config.AgentSource{})
+		require.NoError(t, err)
+
+		projectManifest, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, projectManifest.Agents, 2)
+
+		entry := projectManifest.FindAgent("agent1")
+		assert.Equal(t, "1.1.0", entry.Version)
+		assert.Equal(t, "team", entry.Source)
+		assert.True(t, entry.CustomOverride)
+		assert.Equal(t, "cami", entry.Origin)
+		assert.Equal(t, []string{"github"}, entry.MCPServers)
+		assert.Equal(t, "web", entry.Bundle)
+		assert.NotNil(t, projectManifest.FindAgent("/review"))
+
+		centralManifest, err := manifest.ReadCentralManifest()
+		require.NoError(t, err)
+		absPath, _ := filepath.Abs(tmpDir)
+		assert.Len(t, centralManifest.Deployments[absPath].Agents, 2)
+	})
+
 	t.Run("backup is created before normalization", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
@@ -412,6 +453,105 @@ func TestNormalizeProject(t *testing.T) {
 	})
 }
 
+func TestPlanNormalizeSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceDir := filepath.Join(tmpDir, "source")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+
+	createTestAgent(t, sourceDir, "agent1.md", "agent1", "", "")
+	createTestAgent(t, sourceDir, "agent2.md", "agent2", "1.0.0", "Description")
+	before, err := os.ReadFile(filepath.Join(sourceDir, "agent1.md"))
+	require.NoError(t, err)
+
+	options := SourceNormalizationOptions{
+		AddVersions:      true,
+		AddDescriptions:  true,
+		CreateCAMIIgnore: true,
+	}
+
+	p, err := PlanNormalizeSource("test-source", sourceDir, options)
+	require.NoError(t, err)
+
+	// Backup, agent1 and .camiignore; agent2 is already compliant
+	require.Len(t, p.Files, 3)
+	assert.Equal(t, plan.FileCreate, p.Files[0].Action)
+	assert.Contains(t, p.Files[0].Path, backup.BackupPrefix)
+	assert.Equal(t, filepath.Join(sourceDir, "agent1.md"), p.Files[1].Path)
+	assert.Equal(t, plan.FileOverwrite, p.Files[1].Action)
+	assert.Contains(t, p.Files[1].Reason, "Added version 1.0.0")
+	assert.Contains(t, p.Files[1].Reason, "Added description placeholder")
+	assert.Equal(t, filepath.Join(sourceDir, ".camiignore"), p.Files[2].Path)
+	assert.Equal(t, plan.FileCreate, p.Files[2].Action)
+
+	// Nothing was written
+	after, err := os.ReadFile(filepath.Join(sourceDir, "agent1.md"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	assert.NoFileExists(t, filepath.Join(sourceDir, ".camiignore"))
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestPlanNormalizeProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	t.Run("new manifest adds every agent", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		agentsDir := filepath.Join(tmpDir, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+		createTestAgent(t, agentsDir, "agent1.md", "agent1", "1.0.0", "Description")
+
+		p, err := PlanNormalizeProject(tmpDir, ProjectNormalizationOptions{Level: LevelMinimal}, nil)
+		require.NoError(t, err)
+
+		manifestPath := filepath.Join(tmpDir, manifest.ProjectManifestFilename)
+		require.Len(t, p.Manifests, 1)
+		assert.Equal(t, plan.ManifestAdd, p.Manifests[0].Action)
+		assert.Equal(t, "agent1", p.Manifests[0].Agent)
+		assert.Equal(t, manifestPath, p.Manifests[0].Manifest)
+
+		var paths []string
+		for _, f := range p.Files {
+			paths = append(paths, f.Path)
+		}
+		assert.Contains(t, paths, manifestPath)
+		assert.NoFileExists(t, manifestPath)
+	})
+
+	t.Run("relinking existing manifest updates entries", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		sourceDir := t.TempDir()
+		createTestAgent(t, sourceDir, "agent1.md", "agent1", "1.0.0", "Description")
+
+		agentsDir := filepath.Join(tmpDir, ".claude", "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+		createTestAgent(t, agentsDir, "agent1.md", "agent1", "1.0.0", "Description")
+
+		_, err := NormalizeProject(tmpDir, ProjectNormalizationOptions{Level: LevelMinimal}, nil)
+		require.NoError(t, err)
+
+		sources := []config.AgentSource{{Name: "test-source", Path: sourceDir, Priority: 50}}
+		p, err := PlanNormalizeProject(tmpDir, ProjectNormalizationOptions{Level: LevelStandard}, sources)
+		require.NoError(t, err)
+
+		require.Len(t, p.Manifests, 1)
+		assert.Equal(t, plan.ManifestUpdate, p.Manifests[0].Action)
+
+		projectManifest, err := manifest.ReadProjectManifest(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, "unknown", projectManifest.Agents[0].Source)
+	})
+
+	t.Run("full normalization returns not implemented error", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".claude", "agents"), 0755))
+
+		_, err := PlanNormalizeProject(tmpDir, ProjectNormalizationOptions{Level: LevelFull}, nil)
+		assert.Error(t, err)
+	})
+}
+
 // Helper functions
 
 func createTestAgent(t *testing.T, dir, filename, name, version, description string) {
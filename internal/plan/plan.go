@@ -0,0 +1,238 @@
+package plan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/manifest"
+)
+
+// FileAction describes what an operation will do with one file
+type FileAction string
+
+const (
+	FileCreate    FileAction = "create"    // File doesn't exist yet
+	FileOverwrite FileAction = "overwrite" // Existing file is replaced
+	FileDelete    FileAction = "delete"    // Existing file is removed
+	FileSkip      FileAction = "skip"      // File is left alone, see Reason
+)
+
+// FileChange is the planned change to one file
+type FileChange struct {
+	Path   string     `json:"path"`
+	Action FileAction `json:"action"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// ManifestAction describes what an operation will do with one manifest entry
+type ManifestAction string
+
+const (
+	ManifestAdd    ManifestAction = "add"
+	ManifestUpdate ManifestAction = "update"
+	ManifestRemove ManifestAction = "remove"
+)
+
+// ManifestChange is the planned change to one agent's entry in a manifest
+type ManifestChange struct {
+	Manifest    string         `json:"manifest"` // Path of the manifest file
+	Agent       string         `json:"agent"`    // Agent name, or /name for a command
+	Action      ManifestAction `json:"action"`
+	FromVersion string         `json:"from_version,omitempty"`
+	ToVersion   string         `json:"to_version,omitempty"`
+}
+
+// Plan lists the files an operation would write or delete and the manifest
+// entries it would change, so they can be reviewed before anything on disk
+// is touched
+type Plan struct {
+	Operation string           `json:"operation"`
+	Target    string           `json:"target"`
+	Files     []FileChange     `json:"files"`
+	Manifests []ManifestChange `json:"manifests"`
+}
+
+// New starts an empty plan for an operation on target
+func New(operation, target string) *Plan {
+	return &Plan{
+		Operation: operation,
+		Target:    target,
+		Files:     []FileChange{},
+		Manifests: []ManifestChange{},
+	}
+}
+
+// Write records that path will be written, as a create or an overwrite
+// depending on whether it exists now
+func (p *Plan) Write(path, reason string) {
+	action := FileCreate
+	if _, err := os.Stat(path); err == nil {
+		action = FileOverwrite
+	}
+	p.Files = append(p.Files, FileChange{Path: path, Action: action, Reason: reason})
+}
+
+// Delete records that path will be removed
+func (p *Plan) Delete(path, reason string) {
+	p.Files = append(p.Files, FileChange{Path: path, Action: FileDelete, Reason: reason})
+}
+
+// Skip records that path will be left alone
+func (p *Plan) Skip(path, reason string) {
+	p.Files = append(p.Files, FileChange{Path: path, Action: FileSkip, Reason: reason})
+}
+
+// DiffManifest records the entries that differ between a manifest as it is
+// and as the operation will write it. Before may be nil for a new manifest.
+func (p *Plan) DiffManifest(path string, before, after *manifest.ProjectManifest) {
+	existing := make(map[string]manifest.DeployedAgent)
+	if before != nil {
+		for _, entry := range before.Agents {
+			existing[entry.ID()] = entry
+		}
+	}
+
+	kept := make(map[string]bool)
+	for _, entry := range after.Agents {
+		id := entry.ID()
+		kept[id] = true
+
+		old, ok := existing[id]
+		if !ok {
+			p.Manifests = append(p.Manifests, ManifestChange{
+				Manifest:  path,
+				Agent:     id,
+				Action:    ManifestAdd,
+				ToVersion: entry.Version,
+			})
+			continue
+		}
+
+		if entryChanged(old, entry) {
+			p.Manifests = append(p.Manifests, ManifestChange{
+				Manifest:    path,
+				Agent:       id,
+				Action:      ManifestUpdate,
+				FromVersion: old.Version,
+				ToVersion:   entry.Version,
+			})
+		}
+	}
+
+	var removed []string
+	for name := range existing {
+		if !kept[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		p.Manifests = append(p.Manifests, ManifestChange{
+			Manifest:    path,
+			Agent:       name,
+			Action:      ManifestRemove,
+			FromVersion: existing[name].Version,
+		})
+	}
+}
+
+// WriteManifests records writing a project's manifest, with the entries that
+// change from before to after, and recording the project in the central
+// manifest
+func (p *Plan) WriteManifests(projectPath string, before, after *manifest.ProjectManifest) error {
+	manifestPath := filepath.Join(projectPath, manifest.ProjectManifestFilename)
+	p.Write(manifestPath, "project manifest")
+	p.DiffManifest(manifestPath, before, after)
+
+	centralPath, err := manifest.CentralManifestPath()
+	if err != nil {
+		return err
+	}
+	p.Write(centralPath, "central manifest")
+
+	return nil
+}
+
+// entryChanged reports whether rewriting an entry changes what it records.
+// Timestamps are ignored; they move on every write.
+func entryChanged(old, updated manifest.DeployedAgent) bool {
+	old.DeployedAt = updated.DeployedAt
+	return !reflect.DeepEqual(old, updated)
+}
+
+// Empty reports whether the plan changes nothing
+func (p *Plan) Empty() bool {
+	for _, f := range p.Files {
+		if f.Action != FileSkip {
+			return false
+		}
+	}
+	return len(p.Manifests) == 0
+}
+
+// Text renders the plan for display
+func (p *Plan) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan: %s %s\n", p.Operation, p.Target)
+
+	if p.Empty() {
+		b.WriteString("\nNo changes.\n")
+	}
+
+	if len(p.Files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, f := range p.Files {
+			fmt.Fprintf(&b, "  %s %-9s %s", fileSymbol(f.Action), f.Action, f.Path)
+			if f.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", f.Reason)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(p.Manifests) > 0 {
+		b.WriteString("\nManifest entries:\n")
+		for _, m := range p.Manifests {
+			fmt.Fprintf(&b, "  %s %-6s %s", manifestSymbol(m.Action), m.Action, m.Agent)
+			switch {
+			case m.FromVersion != "" && m.ToVersion != "" && m.FromVersion != m.ToVersion:
+				fmt.Fprintf(&b, " (%s → %s)", m.FromVersion, m.ToVersion)
+			case m.ToVersion != "":
+				fmt.Fprintf(&b, " (%s)", m.ToVersion)
+			case m.FromVersion != "":
+				fmt.Fprintf(&b, " (%s)", m.FromVersion)
+			}
+			fmt.Fprintf(&b, " in %s\n", m.Manifest)
+		}
+	}
+
+	return b.String()
+}
+
+func fileSymbol(action FileAction) string {
+	switch action {
+	case FileCreate:
+		return "+"
+	case FileOverwrite:
+		return "~"
+	case FileDelete:
+		return "-"
+	default:
+		return "!"
+	}
+}
+
+func manifestSymbol(action ManifestAction) string {
+	switch action {
+	case ManifestAdd:
+		return "+"
+	case ManifestRemove:
+		return "-"
+	default:
+		return "~"
+	}
+}
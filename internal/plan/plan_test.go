@@ -0,0 +1,93 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing.md")
+	require.NoError(t, os.WriteFile(existing, []byte("content"), 0644))
+
+	p := New("deploy", tmpDir)
+	p.Write(existing, "")
+	p.Write(filepath.Join(tmpDir, "new.md"), "")
+
+	assert.Equal(t, FileOverwrite, p.Files[0].Action)
+	assert.Equal(t, FileCreate, p.Files[1].Action)
+}
+
+func TestDiffManifest(t *testing.T) {
+	now := time.Now()
+	before := &manifest.ProjectManifest{Agents: []manifest.DeployedAgent{
+		{Name: "same", Version: "1.0.0", ContentHash: "a", DeployedAt: now},
+		{Name: "changed", Version: "1.0.0", ContentHash: "b"},
+		{Name: "gone", Version: "2.0.0"},
+	}}
+	after := &manifest.ProjectManifest{Agents: []manifest.DeployedAgent{
+		{Name: "same", Version: "1.0.0", ContentHash: "a", DeployedAt: now.Add(time.Hour)},
+		{Name: "changed", Version: "1.1.0", ContentHash: "c"},
+		{Name: "new", Version: "0.1.0"},
+	}}
+
+	t.Run("reports adds, updates and removals", func(t *testing.T) {
+		p := New("deploy", "/project")
+		p.DiffManifest("/project/.claude/cami-manifest.yaml", before, after)
+
+		require.Len(t, p.Manifests, 3)
+		assert.Equal(t, ManifestChange{
+			Manifest: "/project/.claude/cami-manifest.yaml", Agent: "changed",
+			Action: ManifestUpdate, FromVersion: "1.0.0", ToVersion: "1.1.0",
+		}, p.Manifests[0])
+		assert.Equal(t, ManifestAdd, p.Manifests[1].Action)
+		assert.Equal(t, "new", p.Manifests[1].Agent)
+		assert.Equal(t, ManifestRemove, p.Manifests[2].Action)
+		assert.Equal(t, "gone", p.Manifests[2].Agent)
+	})
+
+	t.Run("nil before adds everything", func(t *testing.T) {
+		p := New("import", "/project")
+		p.DiffManifest("manifest", nil, after)
+
+		require.Len(t, p.Manifests, 3)
+		for _, change := range p.Manifests {
+			assert.Equal(t, ManifestAdd, change.Action)
+		}
+	})
+}
+
+func TestText(t *testing.T) {
+	t.Run("empty plan", func(t *testing.T) {
+		p := New("deploy", "/project")
+		p.Skip("/project/.claude/agents/a.md", "file already exists")
+
+		text := p.Text()
+		assert.Contains(t, text, "Plan: deploy /project")
+		assert.Contains(t, text, "No changes.")
+		assert.Contains(t, text, "! skip      /project/.claude/agents/a.md (file already exists)")
+	})
+
+	t.Run("files and manifest entries", func(t *testing.T) {
+		p := New("deploy", "/project")
+		p.Files = append(p.Files,
+			FileChange{Path: "/project/a.md", Action: FileCreate},
+			FileChange{Path: "/project/b.md", Action: FileDelete},
+		)
+		p.Manifests = append(p.Manifests,
+			ManifestChange{Manifest: "m.yaml", Agent: "a", Action: ManifestUpdate, FromVersion: "1.0.0", ToVersion: "1.1.0"},
+		)
+
+		text := p.Text()
+		assert.NotContains(t, text, "No changes.")
+		assert.Contains(t, text, "+ create    /project/a.md")
+		assert.Contains(t, text, "- delete    /project/b.md")
+		assert.Contains(t, text, "~ update a (1.0.0 → 1.1.0) in m.yaml")
+	})
+}
@@ -0,0 +1,376 @@
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/semver"
+	"github.com/lando/cami/internal/spec"
+)
+
+// ErrNotFound is returned when no source provides an agent at all
+var ErrNotFound = errors.New("agent not found")
+
+// UnsatisfiedError is returned when an agent exists but no available
+// version satisfies the requested constraint
+type UnsatisfiedError struct {
+	Requirement spec.Requirement
+	Available   []string // Versions on offer, highest first
+}
+
+func (e *UnsatisfiedError) Error() string {
+	available := "none"
+	if len(e.Available) > 0 {
+		available = strings.Join(e.Available, ", ")
+	}
+	return fmt.Sprintf("no version of %s satisfies %s (available: %s)", e.Requirement.Name, e.Requirement.Version, available)
+}
+
+// Candidate is one available copy of an agent: the working tree of a source
+// or the source as of a git tag
+type Candidate struct {
+	Agent    *agent.Agent
+	Source   string
+	Priority int
+	Ref      string // Git tag; empty for the working tree
+	Version  semver.Version
+	Valid    bool // Whether the agent's version parsed as semver
+}
+
+// Resolver picks the agent version to deploy for a requirement
+type Resolver struct {
+	sources    []agent.AgentSource
+	available  map[string]*agent.Agent
+	candidates map[string][]*Candidate
+}
+
+// New creates a resolver. Available are the priority-deduplicated agents,
+// commands and skills used when a requirement has no constraint;
+// requirements name commands as /name and skills as skill:name. Sources are
+// scanned for every version, including git tags, only when a constraint
+// needs them. With no sources, constraints are checked against the available
+// agents alone.
+func New(sources []agent.AgentSource, available []*agent.Agent) *Resolver {
+	r := &Resolver{
+		sources:   sources,
+		available: make(map[string]*agent.Agent),
+	}
+	for _, ag := range available {
+		r.available[ag.ID()] = ag
+	}
+	return r
+}
+
+// Resolve returns the agent satisfying a requirement: the highest matching
+// version, preferring a source's working tree over its tags and higher
+// priority sources over lower ones when versions tie
+func (r *Resolver) Resolve(req spec.Requirement) (*agent.Agent, error) {
+	constraint, err := req.Constraint()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Version == "" && req.Source == "" {
+		if ag, ok := r.available[req.Name]; ok {
+			return ag, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, req.Name)
+	}
+
+	var candidates []*Candidate
+	for _, c := range r.Candidates(req.Name) {
+		if req.Source == "" || c.Source == req.Source {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		if req.Source != "" {
+			return nil, fmt.Errorf("%w: %s in source %s", ErrNotFound, req.Name, req.Source)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, req.Name)
+	}
+
+	var matches []*Candidate
+	for _, c := range candidates {
+		// Unversioned agents only satisfy an empty constraint
+		if (c.Valid && constraint.Check(c.Version)) || (!c.Valid && req.Version == "") {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, &UnsatisfiedError{Requirement: req, Available: versions(candidates)}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+		if c := a.Version.Compare(b.Version); c != 0 {
+			return c > 0
+		}
+		if (a.Ref == "") != (b.Ref == "") {
+			return a.Ref == ""
+		}
+		return a.Priority < b.Priority
+	})
+
+	return matches[0].Agent, nil
+}
+
+// ResolveProject resolves "name" or "name@constraint" requests for a
+// project, in order, followed by every agent they require, transitively,
+// as declared in their requires frontmatter. Requests
+// without a constraint use the one in the project spec, and explicit
+// constraints must still satisfy the spec, so a deploy can never move an
+// agent outside the range the project declared. Requirements declared by
+// agents follow the same rules, and must also accept any version already
+// chosen for the same agent.
+func (r *Resolver) ResolveProject(projectPath string, requests []string) ([]*agent.Agent, error) {
+	projectSpec := &spec.Spec{}
+	if spec.Exists(projectPath) {
+		var err error
+		if projectSpec, err = spec.Read(projectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var agents []*agent.Agent
+	var notFound []string
+
+	for _, request := range requests {
+		req, err := spec.ParseRequirement(request)
+		if err != nil {
+			return nil, err
+		}
+
+		ag, err := r.resolveForProject(projectSpec, req)
+		if errors.Is(err, ErrNotFound) {
+			notFound = append(notFound, req.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		agents = append(agents, ag)
+	}
+
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("agents not found: %s", strings.Join(notFound, ", "))
+	}
+
+	chosen := make(map[string]*agent.Agent, len(agents))
+	for _, ag := range agents {
+		chosen[ag.ID()] = ag
+	}
+
+	return agent.ResolveDependencies(agents, func(requirement string) (*agent.Agent, error) {
+		req, err := spec.ParseRequirement(requirement)
+		if err != nil {
+			return nil, err
+		}
+
+		if ag, ok := chosen[req.Name]; ok {
+			if err := CheckRequirement(req, ag); err != nil {
+				return nil, err
+			}
+			return ag, nil
+		}
+
+		ag, err := r.resolveForProject(projectSpec, req)
+		if err != nil {
+			return nil, err
+		}
+		chosen[req.Name] = ag
+		return ag, nil
+	})
+}
+
+// resolveForProject resolves a requirement, falling back to the constraint
+// the project spec declares and checking explicit constraints against it
+func (r *Resolver) resolveForProject(projectSpec *spec.Spec, req spec.Requirement) (*agent.Agent, error) {
+	pinned := projectSpec.Find(req.Name)
+	if pinned != nil && req.Version == "" {
+		req.Version = pinned.Version
+		if req.Source == "" {
+			req.Source = pinned.Source
+		}
+	}
+
+	ag, err := r.Resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinned != nil && pinned.Version != "" && req.Version != pinned.Version {
+		if err := CheckRequirement(*pinned, ag); err != nil {
+			return nil, fmt.Errorf("%w (declared in %s)", err, spec.Filename)
+		}
+	}
+
+	return ag, nil
+}
+
+// CheckRequirement reports whether an agent satisfies a requirement's constraint
+func CheckRequirement(req spec.Requirement, ag *agent.Agent) error {
+	constraint, err := req.Constraint()
+	if err != nil {
+		return err
+	}
+	if req.Version == "" {
+		return nil
+	}
+
+	v, err := semver.Parse(ag.Version)
+	if err != nil || !constraint.Check(v) {
+		version := ag.Version
+		if version == "" {
+			version = "(unversioned)"
+		}
+		return fmt.Errorf("%s %s does not satisfy constraint %s", ag.Name, version, req.Version)
+	}
+	return nil
+}
+
+// Candidates returns every available copy of an agent, or of a command given
+// as /name, across all sources and their git tags
+func (r *Resolver) Candidates(name string) []*Candidate {
+	if r.candidates == nil {
+		r.loadCandidates()
+	}
+	return r.candidates[name]
+}
+
+func (r *Resolver) loadCandidates() {
+	r.candidates = make(map[string][]*Candidate)
+
+	// Without sources to scan, the available agents are the only versions
+	if len(r.sources) == 0 {
+		for _, ag := range r.available {
+			r.addCandidates(agent.AgentSource{Name: ag.Source}, ag.Ref, []*agent.Agent{ag})
+		}
+		return
+	}
+
+	for _, source := range r.sources {
+		if agents, _, err := agent.LoadAgents(source.Path); err == nil {
+			r.addCandidates(source, "", agents)
+		}
+		if commands, _, err := agent.LoadCommands(source.Path); err == nil {
+			r.addCandidates(source, "", commands)
+		}
+		if skills, _, err := agent.LoadSkills(source.Path); err == nil {
+			r.addCandidates(source, "", skills)
+		}
+
+		if !git.IsRepo(source.Path) {
+			continue
+		}
+		tags, err := git.Tags(source.Path)
+		if err != nil {
+			continue
+		}
+		for _, tag := range tags {
+			agents, err := loadAgentsAtRef(source.Path, tag)
+			if err != nil {
+				continue
+			}
+			r.addCandidates(source, tag, agents)
+		}
+	}
+}
+
+func (r *Resolver) addCandidates(source agent.AgentSource, ref string, agents []*agent.Agent) {
+	for _, ag := range agents {
+		ag.Source = source.Name
+		ag.Ref = ref
+
+		c := &Candidate{
+			Agent:    ag,
+			Source:   source.Name,
+			Priority: source.Priority,
+			Ref:      ref,
+		}
+		if v, err := semver.Parse(ag.Version); err == nil {
+			c.Version = v
+			c.Valid = true
+		}
+
+		r.candidates[ag.ID()] = append(r.candidates[ag.ID()], c)
+	}
+}
+
+// loadAgentsAtRef loads the agents, commands and skills a source directory
+// contained at a git ref
+func loadAgentsAtRef(dir, ref string) ([]*agent.Agent, error) {
+	prefix, err := git.Prefix(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := git.ListFiles(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var wanted []string
+	for _, p := range paths {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(p, prefix)
+		if strings.HasSuffix(p, ".md") || strings.HasSuffix(p, ".mcp.json") || path.Base(p) == ".camiignore" || agent.IsSkillFile(rel) {
+			wanted = append(wanted, p)
+		}
+	}
+
+	contents, err := git.ReadFiles(dir, ref, wanted)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(contents))
+	for p, data := range contents {
+		files[strings.TrimPrefix(p, prefix)] = data
+	}
+
+	agents := agent.LoadAgentsFromFiles(dir, files)
+	agents = append(agents, agent.LoadCommandsFromFiles(dir, files)...)
+	return append(agents, agent.LoadSkillsFromFiles(dir, files)...), nil
+}
+
+// versions lists the distinct versions among candidates, highest first
+func versions(candidates []*Candidate) []string {
+	var valid []semver.Version
+	seen := make(map[string]bool)
+	unversioned := false
+
+	for _, c := range candidates {
+		if !c.Valid {
+			unversioned = true
+			continue
+		}
+		if key := c.Version.String(); !seen[key] {
+			seen[key] = true
+			valid = append(valid, c.Version)
+		}
+	}
+
+	sort.Slice(valid, func(i, j int) bool { return valid[j].LessThan(valid[i]) })
+
+	var out []string
+	for _, v := range valid {
+		out = append(out, v.String())
+	}
+	if unversioned {
+		out = append(out, "unversioned")
+	}
+	return out
+}
@@ -0,0 +1,281 @@
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/git"
+	"github.com/lando/cami/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAgent(t *testing.T, dir, name, version string) {
+	t.Helper()
+	content := fmt.Sprintf("---\nname: %s\nversion: %s\ndescription: %s agent\n---\n# %s %s\n", name, version, name, name, version)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".md"), []byte(content), 0644))
+}
+
+// commitAndTag commits everything in dir and tags the commit
+func commitAndTag(t *testing.T, dir, tag string) {
+	t.Helper()
+	_, err := git.Run(dir, "add", "-A")
+	require.NoError(t, err)
+	_, err = git.Run(dir, "commit", "-q", "-m", tag)
+	require.NoError(t, err)
+	_, err = git.Run(dir, "tag", tag)
+	require.NoError(t, err)
+}
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	_, err := git.Run(dir, "init", "-q")
+	require.NoError(t, err)
+	return dir
+}
+
+// newResolver builds a resolver over sources the way callers do: the
+// priority winners are loaded from the same sources
+func newResolver(t *testing.T, sources ...agent.AgentSource) *Resolver {
+	t.Helper()
+	available, _, err := agent.LoadAgentsFromSources(sources)
+	require.NoError(t, err)
+	return New(sources, available)
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("no constraint uses the priority winner", func(t *testing.T) {
+		high, low := t.TempDir(), t.TempDir()
+		writeAgent(t, high, "frontend", "1.0.0")
+		writeAgent(t, low, "frontend", "2.0.0")
+
+		r := newResolver(t,
+			agent.AgentSource{Name: "high", Path: high, Priority: 10},
+			agent.AgentSource{Name: "low", Path: low, Priority: 100},
+		)
+
+		ag, err := r.Resolve(spec.Requirement{Name: "frontend"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", ag.Version)
+	})
+
+	t.Run("constraint resolves across sources", func(t *testing.T) {
+		high, low := t.TempDir(), t.TempDir()
+		writeAgent(t, high, "frontend", "1.0.0")
+		writeAgent(t, low, "frontend", "2.1.0")
+
+		r := newResolver(t,
+			agent.AgentSource{Name: "high", Path: high, Priority: 10},
+			agent.AgentSource{Name: "low", Path: low, Priority: 100},
+		)
+
+		ag, err := r.Resolve(spec.Requirement{Name: "frontend", Version: "^2"})
+		require.NoError(t, err)
+		assert.Equal(t, "2.1.0", ag.Version)
+		assert.Equal(t, "low", ag.Source)
+
+		ag, err = r.Resolve(spec.Requirement{Name: "frontend", Version: "1.x"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", ag.Version)
+	})
+
+	t.Run("source restriction", func(t *testing.T) {
+		high, low := t.TempDir(), t.TempDir()
+		writeAgent(t, high, "frontend", "1.0.0")
+		writeAgent(t, low, "frontend", "1.0.0")
+
+		r := newResolver(t,
+			agent.AgentSource{Name: "high", Path: high, Priority: 10},
+			agent.AgentSource{Name: "low", Path: low, Priority: 100},
+		)
+
+		ag, err := r.Resolve(spec.Requirement{Name: "frontend", Source: "low"})
+		require.NoError(t, err)
+		assert.Equal(t, "low", ag.Source)
+
+		_, err = r.Resolve(spec.Requirement{Name: "frontend", Source: "elsewhere"})
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("resolves versions from git tags", func(t *testing.T) {
+		repo := initRepo(t)
+		writeAgent(t, repo, "frontend", "2.0.0")
+		commitAndTag(t, repo, "v2.0.0")
+		writeAgent(t, repo, "frontend", "2.4.0")
+		commitAndTag(t, repo, "v2.4.0")
+		writeAgent(t, repo, "frontend", "3.0.0")
+
+		r := newResolver(t, agent.AgentSource{Name: "team", Path: repo, Priority: 10})
+
+		ag, err := r.Resolve(spec.Requirement{Name: "frontend", Version: "^2"})
+		require.NoError(t, err)
+		assert.Equal(t, "2.4.0", ag.Version)
+		assert.Equal(t, "v2.4.0", ag.Ref)
+		assert.Equal(t, "team", ag.Source)
+		assert.Contains(t, ag.FullContent(), "# frontend 2.4.0")
+
+		ag, err = r.Resolve(spec.Requirement{Name: "frontend", Version: ">=2"})
+		require.NoError(t, err)
+		assert.Equal(t, "3.0.0", ag.Version)
+		assert.Equal(t, "", ag.Ref)
+	})
+
+	t.Run("tags in a source subdirectory", func(t *testing.T) {
+		repo := initRepo(t)
+		agentsDir := filepath.Join(repo, "agents")
+		require.NoError(t, os.MkdirAll(agentsDir, 0755))
+		writeAgent(t, agentsDir, "frontend", "1.0.0")
+		writeAgent(t, repo, "outside", "1.0.0")
+		commitAndTag(t, repo, "v1")
+		writeAgent(t, agentsDir, "frontend", "2.0.0")
+
+		r := newResolver(t, agent.AgentSource{Name: "team", Path: agentsDir, Priority: 10})
+
+		ag, err := r.Resolve(spec.Requirement{Name: "frontend", Version: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", ag.Ref)
+		assert.Equal(t, filepath.Join(agentsDir, "frontend.md"), ag.FilePath)
+
+		assert.Empty(t, r.Candidates("outside"))
+	})
+
+	t.Run("unsatisfied constraint lists available versions", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAgent(t, dir, "frontend", "1.2.0")
+
+		r := newResolver(t, agent.AgentSource{Name: "team", Path: dir, Priority: 10})
+
+		_, err := r.Resolve(spec.Requirement{Name: "frontend", Version: "^2"})
+		var unsatisfied *UnsatisfiedError
+		require.True(t, errors.As(err, &unsatisfied))
+		assert.Equal(t, []string{"1.2.0"}, unsatisfied.Available)
+		assert.Contains(t, err.Error(), "no version of frontend satisfies ^2")
+	})
+
+	t.Run("unknown agent", func(t *testing.T) {
+		r := newResolver(t)
+
+		_, err := r.Resolve(spec.Requirement{Name: "ghost", Version: "^1"})
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("commands resolve by /name", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAgent(t, dir, "review", "1.0.0")
+		commandsDir := filepath.Join(dir, "commands")
+		require.NoError(t, os.MkdirAll(commandsDir, 0755))
+		writeAgent(t, commandsDir, "review", "2.0.0")
+
+		source := agent.AgentSource{Name: "team", Path: dir, Priority: 10}
+		agents, _, err := agent.LoadAgentsFromSources([]agent.AgentSource{source})
+		require.NoError(t, err)
+		commands, _, err := agent.LoadCommandsFromSources([]agent.AgentSource{source})
+		require.NoError(t, err)
+		r := New([]agent.AgentSource{source}, append(agents, commands...))
+
+		ag, err := r.Resolve(spec.Requirement{Name: "/review"})
+		require.NoError(t, err)
+		assert.Equal(t, agent.KindCommand, ag.Kind)
+		assert.Equal(t, "2.0.0", ag.Version)
+
+		ag, err = r.Resolve(spec.Requirement{Name: "/review", Version: "^2"})
+		require.NoError(t, err)
+		assert.Equal(t, agent.KindCommand, ag.Kind)
+
+		ag, err = r.Resolve(spec.Requirement{Name: "review"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", ag.Version)
+	})
+}
+
+func TestResolveProject(t *testing.T) {
+	setup := func(t *testing.T) (*Resolver, string) {
+		high, low := t.TempDir(), t.TempDir()
+		writeAgent(t, high, "frontend", "3.0.0")
+		writeAgent(t, low, "frontend", "2.3.0")
+		writeAgent(t, high, "backend", "1.0.0")
+
+		r := newResolver(t,
+			agent.AgentSource{Name: "high", Path: high, Priority: 10},
+			agent.AgentSource{Name: "low", Path: low, Priority: 100},
+		)
+		return r, t.TempDir()
+	}
+
+	t.Run("without a spec", func(t *testing.T) {
+		r, project := setup(t)
+
+		agents, err := r.ResolveProject(project, []string{"frontend", "backend@1"})
+		require.NoError(t, err)
+		require.Len(t, agents, 2)
+		assert.Equal(t, "3.0.0", agents[0].Version)
+		assert.Equal(t, "1.0.0", agents[1].Version)
+	})
+
+	t.Run("spec constraints apply to plain requests", func(t *testing.T) {
+		r, project := setup(t)
+		require.NoError(t, spec.Write(project, &spec.Spec{Agents: []spec.Requirement{{Name: "frontend", Version: "^2.1"}}}))
+
+		agents, err := r.ResolveProject(project, []string{"frontend"})
+		require.NoError(t, err)
+		assert.Equal(t, "2.3.0", agents[0].Version)
+	})
+
+	t.Run("explicit versions must satisfy the spec", func(t *testing.T) {
+		r, project := setup(t)
+		require.NoError(t, spec.Write(project, &spec.Spec{Agents: []spec.Requirement{{Name: "frontend", Version: "^2.1"}}}))
+
+		_, err := r.ResolveProject(project, []string{"frontend@3"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not satisfy constraint ^2.1")
+	})
+
+	t.Run("reports all missing agents", func(t *testing.T) {
+		r, project := setup(t)
+
+		_, err := r.ResolveProject(project, []string{"ghost", "frontend", "phantom@1"})
+		assert.EqualError(t, err, "agents not found: ghost, phantom")
+	})
+	t.Run("required agents are pulled in", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAgent(t, dir, "frontend", "2.0.0")
+		writeAgent(t, dir, "backend", "1.0.0")
+		orchestrator := "---\nname: orchestrator\nversion: 1.0.0\nrequires:\n  - frontend@^2\n  - backend\n---\nDelegate.\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "orchestrator.md"), []byte(orchestrator), 0644))
+		r := newResolver(t, agent.AgentSource{Name: "team", Path: dir, Priority: 10})
+
+		agents, err := r.ResolveProject(t.TempDir(), []string{"orchestrator"})
+		require.NoError(t, err)
+		require.Len(t, agents, 3)
+		assert.Equal(t, "orchestrator", agents[0].Name)
+		assert.Equal(t, "frontend", agents[1].Name)
+		assert.Equal(t, "backend", agents[2].Name)
+	})
+
+	t.Run("required constraints must accept requested versions", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAgent(t, dir, "frontend", "3.0.0")
+		orchestrator := "---\nname: orchestrator\nversion: 1.0.0\nrequires: frontend@^2\n---\nDelegate.\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "orchestrator.md"), []byte(orchestrator), 0644))
+		r := newResolver(t, agent.AgentSource{Name: "team", Path: dir, Priority: 10})
+
+		_, err := r.ResolveProject(t.TempDir(), []string{"frontend", "orchestrator"})
+		var missing *agent.MissingDependencyError
+		require.True(t, errors.As(err, &missing), "got %v", err)
+		assert.Contains(t, err.Error(), "orchestrator requires frontend@^2")
+	})
+}
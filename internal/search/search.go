@@ -0,0 +1,403 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/lando/cami/internal/semver"
+)
+
+// DefaultLimit is how many results a search returns unless told otherwise
+const DefaultLimit = 10
+
+// snippetLength is roughly how many characters of context a snippet shows
+const snippetLength = 160
+
+// field is a part of an agent that is searched
+type field int
+
+const (
+	fieldName field = iota
+	fieldSpecialty
+	fieldDescription
+	fieldBody
+	fieldCount
+)
+
+// fieldWeights rank a match in the name above one in the specialty, the
+// description and, least, the body
+var fieldWeights = [fieldCount]float64{
+	fieldName:        4,
+	fieldSpecialty:   3,
+	fieldDescription: 2,
+	fieldBody:        1,
+}
+
+// Filter narrows a search to agents with facet values. Empty fields match
+// every agent; Class, Category and Source match case-insensitively, and
+// Version is a constraint such as "^2" or ">=1.5.0".
+type Filter struct {
+	Class    string `json:"class,omitempty"`
+	Category string `json:"category,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// Result is an agent matching a search
+type Result struct {
+	Agent       *agent.Agent `json:"-"`
+	ID          string       `json:"id"`
+	Version     string       `json:"version,omitempty"`
+	Description string       `json:"description"`
+	Class       string       `json:"class,omitempty"`
+	Category    string       `json:"category,omitempty"`
+	Source      string       `json:"source,omitempty"`
+	Score       float64      `json:"score"`
+	Snippet     string       `json:"snippet,omitempty"` // Where the query matched the description or body
+}
+
+// Results are the top results of a search, with facet counts over every
+// matching agent so a caller can see how to narrow the search
+type Results struct {
+	Query   string                    `json:"query"`
+	Filter  Filter                    `json:"filter"`
+	Total   int                       `json:"total"` // Matching agents, before the limit
+	Results []Result                  `json:"results"`
+	Facets  map[string]map[string]int `json:"facets"` // class, category and source values, with counts
+}
+
+// posting is one agent's occurrences of a term in one field
+type posting struct {
+	doc   int
+	field field
+	count int
+}
+
+// Index is an in-memory inverted index over agents
+type Index struct {
+	agents   []*agent.Agent
+	postings map[string][]posting
+	lengths  [][fieldCount]int // Term count of each field of each agent
+	average  [fieldCount]float64
+}
+
+// New indexes agents by the terms in their name, specialty, description and
+// body
+func New(agents []*agent.Agent) *Index {
+	idx := &Index{
+		agents:   agents,
+		postings: make(map[string][]posting),
+		lengths:  make([][fieldCount]int, len(agents)),
+	}
+
+	for doc, ag := range agents {
+		for f, text := range fieldText(ag) {
+			terms := Tokenize(text)
+			idx.lengths[doc][f] = len(terms)
+			idx.average[f] += float64(len(terms))
+
+			counts := make(map[string]int)
+			for _, term := range terms {
+				counts[term]++
+			}
+			for term, count := range counts {
+				idx.postings[term] = append(idx.postings[term], posting{doc: doc, field: field(f), count: count})
+			}
+		}
+	}
+
+	if len(agents) > 0 {
+		for f := range idx.average {
+			idx.average[f] /= float64(len(agents))
+		}
+	}
+	return idx
+}
+
+// fieldText returns the text of each searched field of an agent
+func fieldText(ag *agent.Agent) [fieldCount]string {
+	return [fieldCount]string{
+		fieldName:        ag.Name,
+		fieldSpecialty:   ag.Specialty,
+		fieldDescription: ag.Description,
+		fieldBody:        ag.Content,
+	}
+}
+
+// Tokenize splits text into lowercase terms of letters and digits, so
+// "react-development" is "react" and "development"
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Search ranks the agents the filter allows by relevance to query, scoring
+// each field with BM25 and weighting it by the field, and returns the top
+// limit (DefaultLimit if limit is not positive). Every query term must occur
+// in an agent for it to match. An empty query matches every agent the filter
+// allows, in name order.
+func (idx *Index) Search(query string, filter Filter, limit int) (*Results, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	allowed, err := idx.filter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := unique(Tokenize(query))
+	scores := make(map[int]float64)
+	if len(terms) == 0 {
+		for doc := range allowed {
+			scores[doc] = 0
+		}
+	} else {
+		matched := make(map[int]int)
+		for _, term := range terms {
+			postings := idx.postings[term]
+			idf := idx.idf(postings)
+			seen := make(map[int]bool)
+			for _, p := range postings {
+				if !allowed[p.doc] {
+					continue
+				}
+				scores[p.doc] += fieldWeights[p.field] * idf * idx.termWeight(p)
+				if !seen[p.doc] {
+					seen[p.doc] = true
+					matched[p.doc]++
+				}
+			}
+		}
+		for doc := range scores {
+			if matched[doc] < len(terms) {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		a, b := docs[i], docs[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return idx.agents[a].ID() < idx.agents[b].ID()
+	})
+
+	results := &Results{
+		Query:   query,
+		Filter:  filter,
+		Total:   len(docs),
+		Results: []Result{},
+		Facets: map[string]map[string]int{
+			"class":    {},
+			"category": {},
+			"source":   {},
+		},
+	}
+	for i, doc := range docs {
+		ag := idx.agents[doc]
+		countFacet(results.Facets["class"], ag.Class)
+		countFacet(results.Facets["category"], ag.Category)
+		countFacet(results.Facets["source"], ag.Source)
+
+		if i < limit {
+			results.Results = append(results.Results, Result{
+				Agent:       ag,
+				ID:          ag.ID(),
+				Version:     ag.Version,
+				Description: ag.Description,
+				Class:       ag.Class,
+				Category:    ag.Category,
+				Source:      ag.Source,
+				Score:       math.Round(scores[doc]*1000) / 1000,
+				Snippet:     Snippet(ag, terms),
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// filter returns the agents the filter allows
+func (idx *Index) filter(filter Filter) (map[int]bool, error) {
+	var constraint *semver.Constraint
+	if filter.Version != "" {
+		var err error
+		if constraint, err = semver.ParseConstraint(filter.Version); err != nil {
+			return nil, fmt.Errorf("invalid version filter: %w", err)
+		}
+	}
+
+	allowed := make(map[int]bool, len(idx.agents))
+	for doc, ag := range idx.agents {
+		if !matchFacet(filter.Class, ag.Class) || !matchFacet(filter.Category, ag.Category) || !matchFacet(filter.Source, ag.Source) {
+			continue
+		}
+		if constraint != nil {
+			version, err := semver.Parse(ag.Version)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+		}
+		allowed[doc] = true
+	}
+	return allowed, nil
+}
+
+// idf is the inverse document frequency of a term with postings
+func (idx *Index) idf(postings []posting) float64 {
+	docs := make(map[int]bool)
+	for _, p := range postings {
+		docs[p.doc] = true
+	}
+	n, df := float64(len(idx.agents)), float64(len(docs))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// termWeight is the BM25 term frequency component of a posting, which
+// saturates with repeats and favors short fields
+func (idx *Index) termWeight(p posting) float64 {
+	const k1, b = 1.2, 0.75
+
+	length := float64(idx.lengths[p.doc][p.field])
+	average := idx.average[p.field]
+	if average == 0 {
+		average = 1
+	}
+	tf := float64(p.count)
+	return tf * (k1 + 1) / (tf + k1*(1-b+b*length/average))
+}
+
+// Snippet returns the part of an agent's description or body where a term
+// first occurs, or the start of its description if none does
+func Snippet(ag *agent.Agent, terms []string) string {
+	for _, text := range []string{ag.Description, ag.Content} {
+		text = strings.Join(strings.Fields(text), " ")
+		if at := firstTerm(text, terms); at >= 0 {
+			return excerpt(text, at)
+		}
+	}
+	return excerpt(strings.Join(strings.Fields(ag.Description), " "), 0)
+}
+
+// firstTerm returns the byte offset in text of the first term, split as
+// Tokenize splits it, that is one of terms, or -1
+func firstTerm(text string, terms []string) int {
+	want := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		want[term] = true
+	}
+
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && want[strings.ToLower(text[start:i])] {
+			return start
+		}
+		start = -1
+	}
+	if start >= 0 && want[strings.ToLower(text[start:])] {
+		return start
+	}
+	return -1
+}
+
+// excerpt returns about snippetLength bytes of text around offset, cut at
+// spaces where there are any and otherwise between runes, with ellipses
+// where text was cut
+func excerpt(text string, offset int) string {
+	if len(text) <= snippetLength {
+		return text
+	}
+
+	start := offset - snippetLength/4
+	if start <= 0 {
+		start = 0
+	} else if space := strings.IndexByte(text[start:offset], ' '); space >= 0 {
+		start += space + 1
+	} else {
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+	}
+
+	end := start + snippetLength
+	if end >= len(text) {
+		end = len(text)
+	} else if space := strings.LastIndexByte(text[start:end], ' '); space > 0 {
+		end = start + space
+	} else {
+		for end > start && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+
+	snippet := text[start:end]
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// FacetLine lists facet values with their counts, most common first, as
+// "core (2), design (1)"
+func FacetLine(counts map[string]int) string {
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%s (%d)", value, counts[value])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func matchFacet(want, value string) bool {
+	return want == "" || strings.EqualFold(want, value)
+}
+
+func countFacet(counts map[string]int, value string) {
+	if value != "" {
+		counts[value]++
+	}
+}
+
+// unique returns terms without repeats, in order
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var result []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/lando/cami/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAgents() []*agent.Agent {
+	return []*agent.Agent{
+		{
+			Name:        "frontend",
+			Version:     "2.1.0",
+			Description: "Builds React user interfaces",
+			Class:       "technology-implementer",
+			Specialty:   "react-development",
+			Category:    "core",
+			Source:      "team",
+			Content:     "You build frontends with React, TypeScript and CSS.",
+		},
+		{
+			Name:        "backend",
+			Version:     "1.4.0",
+			Description: "Builds APIs and services",
+			Class:       "technology-implementer",
+			Specialty:   "api-development",
+			Category:    "core",
+			Source:      "team",
+			Content:     "You build Go services. Some of them serve a React frontend.",
+		},
+		{
+			Name:        "designer",
+			Version:     "1.0.0",
+			Description: "Designs user interfaces and design systems",
+			Class:       "strategic-planner",
+			Specialty:   "ui-design",
+			Category:    "design",
+			Source:      "guild",
+			Content:     "You design accessible interfaces.",
+		},
+		{
+			Name:        "qa",
+			Description: "Tests software",
+			Class:       "compliance-auditor",
+			Category:    "quality",
+			Source:      "guild",
+			Content:     "You write tests.",
+		},
+	}
+}
+
+func ids(results *Results) []string {
+	var ids []string
+	for _, r := range results.Results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"react", "development", "v2", "ui"}, Tokenize("React-Development, v2 (UI)"))
+	assert.Empty(t, Tokenize(" -- "))
+}
+
+func TestSearch(t *testing.T) {
+	idx := New(testAgents())
+
+	t.Run("ranks a name match above a body match", func(t *testing.T) {
+		results, err := idx.Search("frontend", Filter{}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend", "backend"}, ids(results))
+		assert.Greater(t, results.Results[0].Score, results.Results[1].Score)
+	})
+
+	t.Run("requires every query term", func(t *testing.T) {
+		results, err := idx.Search("user interfaces react", Filter{}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend"}, ids(results))
+	})
+
+	t.Run("matches specialty terms", func(t *testing.T) {
+		results, err := idx.Search("API", Filter{}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend"}, ids(results))
+	})
+
+	t.Run("returns nothing for unknown terms", func(t *testing.T) {
+		results, err := idx.Search("kubernetes", Filter{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, results.Results)
+		assert.Equal(t, 0, results.Total)
+	})
+
+	t.Run("limits results but counts every match", func(t *testing.T) {
+		results, err := idx.Search("", Filter{}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "designer"}, ids(results))
+		assert.Equal(t, 4, results.Total)
+	})
+
+	t.Run("filters by facets", func(t *testing.T) {
+		results, err := idx.Search("interfaces", Filter{Class: "Strategic-Planner"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"designer"}, ids(results))
+
+		results, err = idx.Search("", Filter{Category: "core", Source: "team"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "frontend"}, ids(results))
+
+		results, err = idx.Search("", Filter{Source: "nowhere"}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, results.Results)
+	})
+
+	t.Run("filters by version constraint", func(t *testing.T) {
+		results, err := idx.Search("", Filter{Version: ">=1.4.0"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "frontend"}, ids(results))
+
+		results, err = idx.Search("", Filter{Version: "^1"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "designer"}, ids(results), "unversioned agents don't match")
+
+		_, err = idx.Search("", Filter{Version: "not a version"}, 0)
+		assert.ErrorContains(t, err, "invalid version filter")
+	})
+
+	t.Run("counts facets over every match", func(t *testing.T) {
+		results, err := idx.Search("", Filter{}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"technology-implementer": 2, "strategic-planner": 1, "compliance-auditor": 1}, results.Facets["class"])
+		assert.Equal(t, map[string]int{"core": 2, "design": 1, "quality": 1}, results.Facets["category"])
+		assert.Equal(t, map[string]int{"team": 2, "guild": 2}, results.Facets["source"])
+	})
+
+	t.Run("indexes no agents", func(t *testing.T) {
+		results, err := New(nil).Search("react", Filter{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, results.Results)
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	t.Run("shows the description when it matches", func(t *testing.T) {
+		ag := testAgents()[0]
+		assert.Equal(t, "Builds React user interfaces", Snippet(ag, []string{"react"}))
+	})
+
+	t.Run("falls back to the body", func(t *testing.T) {
+		ag := testAgents()[1]
+		assert.Equal(t, ag.Content, Snippet(ag, []string{"frontend"}))
+	})
+
+	t.Run("matches whole terms only", func(t *testing.T) {
+		ag := &agent.Agent{Description: "Reactive streams", Content: "Uses React."}
+		assert.Equal(t, "Uses React.", Snippet(ag, []string{"react"}))
+	})
+
+	t.Run("cuts long text around the match", func(t *testing.T) {
+		body := strings.Repeat("filler words here ", 30) + "the kubernetes operator " + strings.Repeat("more filler text ", 30)
+		ag := &agent.Agent{Description: "Runs clusters", Content: body}
+
+		snippet := Snippet(ag, []string{"kubernetes"})
+		assert.Contains(t, snippet, "kubernetes")
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.LessOrEqual(t, len(snippet), snippetLength+2*len("…"))
+	})
+
+	t.Run("cuts text without spaces between runes", func(t *testing.T) {
+		for _, body := range []string{
+			strings.Repeat("é", 100) + "-kubernetes-" + strings.Repeat("é", 100),
+			strings.Repeat("日本語", 40) + " kubernetes " + strings.Repeat("日本語", 40),
+		} {
+			ag := &agent.Agent{Description: "Runs clusters", Content: body}
+
+			snippet := Snippet(ag, []string{"kubernetes"})
+			assert.True(t, utf8.ValidString(snippet), "invalid UTF-8: %q", snippet)
+			assert.Contains(t, snippet, "kubernetes")
+		}
+	})
+
+	t.Run("finds terms after text that changes length when lowercased", func(t *testing.T) {
+		// "İ" lowercases to three bytes from two
+		ag := &agent.Agent{Description: strings.Repeat("İ ", 300) + "uses React daily " + strings.Repeat("x ", 300)}
+		assert.Contains(t, Snippet(ag, []string{"react"}), "uses React daily")
+	})
+
+	t.Run("uses the description without a match", func(t *testing.T) {
+		ag := testAgents()[3]
+		assert.Equal(t, "Tests software", Snippet(ag, nil))
+	})
+}
+
+func TestFacetLine(t *testing.T) {
+	assert.Equal(t, "core (2), design (1), quality (1)", FacetLine(map[string]int{"quality": 1, "core": 2, "design": 1}))
+	assert.Empty(t, FacetLine(nil))
+}
@@ -0,0 +1,217 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a version range such as "^2.1", "~1.4.0", "2.x",
+// ">=1.2 <2" or "^1 || ^2". Space- or comma-separated comparators must all
+// match; "||" separates alternatives.
+type Constraint struct {
+	raw  string
+	sets [][]comparator
+}
+
+type comparator struct {
+	op string // "=", ">", ">=", "<", "<="
+	v  Version
+}
+
+// ParseConstraint parses a version constraint. An empty constraint, "*",
+// "x" and "latest" match any release.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+
+	for _, alt := range strings.Split(c.raw, "||") {
+		set, err := parseComparatorSet(alt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics on invalid input
+func MustParseConstraint(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	if c.raw == "" {
+		return "*"
+	}
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint. Prerelease versions only
+// match a comparator set that names a prerelease of the same release, so
+// "^2.0" never picks up "2.1.0-beta".
+func (c *Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if setMatches(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func setMatches(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+
+	if v.Prerelease == "" {
+		return true
+	}
+
+	for _, cmp := range set {
+		if cmp.v.Prerelease != "" && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) matches(v Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func parseComparatorSet(s string) ([]comparator, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+
+	// Join operators written apart from their version (">= 1.2")
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+		if strings.Trim(term, "<>=^~") == "" && i+1 < len(fields) {
+			term += fields[i+1]
+			i++
+		}
+		terms = append(terms, term)
+	}
+
+	set := []comparator{}
+	for _, term := range terms {
+		cmps, err := parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cmps...)
+	}
+
+	return set, nil
+}
+
+// parseTerm expands one constraint term into primitive comparators
+func parseTerm(term string) ([]comparator, error) {
+	if term == "latest" {
+		return nil, nil
+	}
+
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op = prefix
+			term = term[len(prefix):]
+			break
+		}
+	}
+
+	v, parts, _, err := parsePartial(term)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wildcard major matches everything, except as an upper bound
+	if parts == 0 {
+		if op == "<" || op == ">" {
+			return []comparator{{op: "<", v: Version{Prerelease: "0"}}}, nil
+		}
+		return nil, nil
+	}
+
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []comparator{{op: "=", v: v}}, nil
+		}
+		return span(v, bumpAt(v, parts-1)), nil
+
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return span(v, bumpAt(v, 0)), nil
+		case v.Minor > 0 || parts == 2:
+			return span(v, bumpAt(v, 1)), nil
+		default:
+			return span(v, bumpAt(v, 2)), nil
+		}
+
+	case "~":
+		if parts == 1 {
+			return span(v, bumpAt(v, 0)), nil
+		}
+		return span(v, bumpAt(v, 1)), nil
+
+	case ">=", "<":
+		return []comparator{{op: op, v: v}}, nil
+
+	case ">":
+		if parts == 3 {
+			return []comparator{{op: ">", v: v}}, nil
+		}
+		// ">2.1" means beyond all of 2.1.x
+		return []comparator{{op: ">=", v: bumpAt(v, parts-1)}}, nil
+
+	case "<=":
+		if parts == 3 {
+			return []comparator{{op: "<=", v: v}}, nil
+		}
+		// "<=2.1" includes all of 2.1.x
+		return []comparator{{op: "<", v: bumpAt(v, parts-1)}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator in %q", term)
+}
+
+// span returns the comparators for lower <= v < upper
+func span(lower, upper Version) []comparator {
+	return []comparator{{op: ">=", v: lower}, {op: "<", v: upper}}
+}
+
+// bumpAt increments the component at index (0 = major) and zeroes the rest.
+// The result carries a "0" prerelease so that the exclusive upper bound also
+// excludes prereleases of the next release.
+func bumpAt(v Version, index int) Version {
+	switch index {
+	case 0:
+		return Version{Major: v.Major + 1, Prerelease: "0"}
+	case 1:
+		return Version{Major: v.Major, Minor: v.Minor + 1, Prerelease: "0"}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Prerelease: "0"}
+	}
+}
@@ -0,0 +1,167 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Build metadata is accepted but ignored.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse parses a version such as "1.2.3", "v2.0.0-beta.1" or a shortened
+// "1.2", with missing components treated as zero
+func Parse(s string) (Version, error) {
+	v, _, wildcard, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if wildcard {
+		return Version{}, fmt.Errorf("invalid version %q: wildcards not allowed", s)
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics on invalid input
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String formats the version as MAJOR.MINOR.PATCH[-PRERELEASE]
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than o
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// LessThan reports whether v sorts before o
+func (v Version) LessThan(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// parsePartial parses a possibly partial version. It returns how many
+// numeric components were given (0-3) and whether a wildcard stood in for
+// the rest; wildcard components count as missing.
+func parsePartial(s string) (v Version, parts int, wildcard bool, err error) {
+	raw := s
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "v")
+	s = strings.TrimPrefix(s, "V")
+
+	if s == "" {
+		return Version{}, 0, false, fmt.Errorf("invalid version %q", raw)
+	}
+
+	// Drop build metadata; it never affects precedence
+	if idx := strings.IndexByte(s, '+'); idx != -1 {
+		s = s[:idx]
+	}
+
+	if idx := strings.IndexByte(s, '-'); idx != -1 {
+		v.Prerelease = s[idx+1:]
+		s = s[:idx]
+		if v.Prerelease == "" {
+			return Version{}, 0, false, fmt.Errorf("invalid version %q: empty prerelease", raw)
+		}
+	}
+
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 {
+		return Version{}, 0, false, fmt.Errorf("invalid version %q", raw)
+	}
+
+	targets := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, field := range fields {
+		if isWildcard(field) {
+			wildcard = true
+			break
+		}
+		n, convErr := strconv.Atoi(field)
+		if convErr != nil || n < 0 {
+			return Version{}, 0, false, fmt.Errorf("invalid version %q", raw)
+		}
+		*targets[i] = n
+		parts++
+	}
+
+	if v.Prerelease != "" && parts < 3 {
+		return Version{}, 0, false, fmt.Errorf("invalid version %q: prerelease requires a full version", raw)
+	}
+
+	return v, parts, wildcard, nil
+}
+
+func isWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease orders prerelease strings per semver: a release sorts
+// after any prerelease, numeric identifiers compare numerically and sort
+// before alphanumeric ones
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := compareInt(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return compareInt(len(as), len(bs))
+}
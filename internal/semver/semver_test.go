@@ -0,0 +1,129 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("full versions", func(t *testing.T) {
+		v, err := Parse("1.2.3")
+		require.NoError(t, err)
+		assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3}, v)
+	})
+
+	t.Run("v prefix and build metadata", func(t *testing.T) {
+		v, err := Parse("v2.0.1+build.7")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.1", v.String())
+	})
+
+	t.Run("short versions", func(t *testing.T) {
+		assert.Equal(t, "1.0.0", MustParse("1").String())
+		assert.Equal(t, "1.2.0", MustParse("1.2").String())
+	})
+
+	t.Run("prerelease", func(t *testing.T) {
+		v, err := Parse("3.0.0-beta.2")
+		require.NoError(t, err)
+		assert.Equal(t, "beta.2", v.Prerelease)
+		assert.Equal(t, "3.0.0-beta.2", v.String())
+	})
+
+	t.Run("invalid versions", func(t *testing.T) {
+		for _, s := range []string{"", "abc", "1.2.3.4", "1.-2", "2.x", "1.2-beta", "1.2.3-"} {
+			_, err := Parse(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"0.9.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	var versions []Version
+	for i := len(ordered) - 1; i >= 0; i-- {
+		versions = append(versions, MustParse(ordered[i]))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].LessThan(versions[j]) })
+
+	for i, v := range versions {
+		assert.Equal(t, ordered[i], v.String())
+	}
+
+	assert.Equal(t, 0, MustParse("1.2").Compare(MustParse("v1.2.0")))
+}
+
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{"", []string{"0.1.0", "9.9.9"}, []string{"1.0.0-beta"}},
+		{"*", []string{"1.0.0"}, nil},
+		{"latest", []string{"1.0.0"}, nil},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4", "1.2.2"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"2", []string{"2.0.0", "2.9.1"}, []string{"1.9.9", "3.0.0"}},
+		{"2.x", []string{"2.0.0", "2.9.1"}, []string{"3.0.0", "3.0.0-beta"}},
+		{"2.1.x", []string{"2.1.0", "2.1.9"}, []string{"2.2.0"}},
+		{"^2.1", []string{"2.1.0", "2.9.0"}, []string{"2.0.9", "3.0.0"}},
+		{"^2.1.3", []string{"2.1.3", "2.2.0"}, []string{"2.1.2", "3.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.4", []string{"1.4.0", "1.4.7"}, []string{"1.5.0", "1.3.9"}},
+		{"~1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.4.1", "1.5.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.2", []string{"1.2.0", "5.0.0"}, []string{"1.1.9"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{">1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"<2", []string{"1.9.9"}, []string{"2.0.0"}},
+		{"<=2.1", []string{"2.1.9"}, []string{"2.2.0"}},
+		{">=1.2 <2", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0"}},
+		{">= 1.2, < 2", []string{"1.5.0"}, []string{"2.0.0"}},
+		{"^1 || ^3", []string{"1.2.0", "3.1.0"}, []string{"2.0.0"}},
+		{">=3.0.0-beta.1", []string{"3.0.0-beta.2", "3.0.0"}, []string{"3.0.0-alpha", "3.1.0-beta.1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			require.NoError(t, err)
+
+			for _, v := range tc.match {
+				assert.True(t, c.Check(MustParse(v)), "%s should satisfy %q", v, tc.constraint)
+			}
+			for _, v := range tc.reject {
+				assert.False(t, c.Check(MustParse(v)), "%s should not satisfy %q", v, tc.constraint)
+			}
+		})
+	}
+
+	t.Run("invalid constraints", func(t *testing.T) {
+		for _, s := range []string{"^abc", ">=1.2.3.4", "1.2 || foo"} {
+			_, err := ParseConstraint(s)
+			assert.Error(t, err, s)
+		}
+	})
+
+	t.Run("string form", func(t *testing.T) {
+		assert.Equal(t, "^2.1", MustParseConstraint(" ^2.1 ").String())
+		assert.Equal(t, "*", MustParseConstraint("").String())
+	})
+}
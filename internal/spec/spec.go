@@ -0,0 +1,183 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lando/cami/internal/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// Filename is the project spec path relative to the project root
+const Filename = ".claude/cami.yaml"
+
+// Spec declares the agents a project wants and the versions it accepts
+type Spec struct {
+	Agents []Requirement `yaml:"agents"`
+}
+
+// Requirement is one agent a project depends on. In YAML it is written
+// either as "name@constraint" or as a mapping with name, version and source.
+type Requirement struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version,omitempty"` // Semver constraint, e.g. "^2.1"; empty accepts any
+	Source  string `yaml:"source,omitempty"`  // Restrict resolution to one source
+}
+
+// ParseRequirement parses "name" or "name@constraint", e.g. "frontend@^2.1"
+func ParseRequirement(s string) (Requirement, error) {
+	s = strings.TrimSpace(s)
+	name, constraint, _ := strings.Cut(s, "@")
+
+	req := Requirement{
+		Name:    strings.TrimSpace(name),
+		Version: strings.TrimSpace(constraint),
+	}
+	if err := req.Validate(); err != nil {
+		return Requirement{}, err
+	}
+	return req, nil
+}
+
+// String formats the requirement as "name" or "name@constraint"
+func (r Requirement) String() string {
+	if r.Version == "" {
+		return r.Name
+	}
+	return r.Name + "@" + r.Version
+}
+
+// Constraint parses the requirement's version constraint
+func (r Requirement) Constraint() (*semver.Constraint, error) {
+	return semver.ParseConstraint(r.Version)
+}
+
+// Validate checks the requirement has a name and a valid constraint
+func (r Requirement) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("agent requirement %q has no name", r.String())
+	}
+	if _, err := r.Constraint(); err != nil {
+		return fmt.Errorf("agent %s: %w", r.Name, err)
+	}
+	return nil
+}
+
+// UnmarshalYAML accepts both the short string and the mapping form
+func (r *Requirement) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		req, err := ParseRequirement(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*r = req
+		return nil
+	}
+
+	// Decode through an alias type to avoid recursing into this method
+	type plain Requirement
+	var req plain
+	if err := node.Decode(&req); err != nil {
+		return err
+	}
+	*r = Requirement(req)
+	return nil
+}
+
+// MarshalYAML writes the short string form unless a source is set
+func (r Requirement) MarshalYAML() (any, error) {
+	if r.Source == "" {
+		return r.String(), nil
+	}
+	type plain Requirement
+	return plain(r), nil
+}
+
+// Find returns the requirement for an agent, or nil if the spec doesn't list it
+func (s *Spec) Find(name string) *Requirement {
+	for i := range s.Agents {
+		if s.Agents[i].Name == name {
+			return &s.Agents[i]
+		}
+	}
+	return nil
+}
+
+// Set adds a requirement or replaces the existing one for the same agent
+func (s *Spec) Set(req Requirement) {
+	if existing := s.Find(req.Name); existing != nil {
+		*existing = req
+		return
+	}
+	s.Agents = append(s.Agents, req)
+}
+
+// Validate checks every requirement and rejects duplicate agents
+func (s *Spec) Validate() error {
+	seen := make(map[string]bool)
+	for _, req := range s.Agents {
+		if err := req.Validate(); err != nil {
+			return err
+		}
+		if seen[req.Name] {
+			return fmt.Errorf("agent %s is listed more than once", req.Name)
+		}
+		seen[req.Name] = true
+	}
+	return nil
+}
+
+// Exists reports whether a project has a spec file
+func Exists(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, Filename))
+	return err == nil
+}
+
+// Read reads and validates a project's spec file
+func Read(projectPath string) (*Spec, error) {
+	specPath := filepath.Join(projectPath, Filename)
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("project spec not found at %s", specPath)
+		}
+		return nil, fmt.Errorf("failed to read project spec: %w", err)
+	}
+
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse project spec: %w", err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid project spec %s: %w", specPath, err)
+	}
+
+	return &spec, nil
+}
+
+// Write writes a project's spec file
+func Write(projectPath string, spec *Spec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	specPath := filepath.Join(projectPath, Filename)
+	if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+		return fmt.Errorf("failed to create .claude directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project spec: %w", err)
+	}
+
+	if err := os.WriteFile(specPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write project spec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpec(t *testing.T, projectPath, content string) {
+	t.Helper()
+	path := filepath.Join(projectPath, Filename)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestParseRequirement(t *testing.T) {
+	t.Run("name only", func(t *testing.T) {
+		req, err := ParseRequirement("frontend")
+		require.NoError(t, err)
+		assert.Equal(t, Requirement{Name: "frontend"}, req)
+	})
+
+	t.Run("name with constraint", func(t *testing.T) {
+		req, err := ParseRequirement(" frontend@^2.1 ")
+		require.NoError(t, err)
+		assert.Equal(t, Requirement{Name: "frontend", Version: "^2.1"}, req)
+		assert.Equal(t, "frontend@^2.1", req.String())
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		_, err := ParseRequirement("frontend@^banana")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		_, err := ParseRequirement("@1.0.0")
+		assert.Error(t, err)
+	})
+}
+
+func TestReadWrite(t *testing.T) {
+	t.Run("reads short and long forms", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeSpec(t, tmpDir, `agents:
+  - frontend@^2.1
+  - backend
+  - name: qa
+    version: ~1.4
+    source: team-agents
+`)
+
+		spec, err := Read(tmpDir)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Requirement{
+			{Name: "frontend", Version: "^2.1"},
+			{Name: "backend"},
+			{Name: "qa", Version: "~1.4", Source: "team-agents"},
+		}, spec.Agents)
+		assert.Equal(t, "~1.4", spec.Find("qa").Version)
+		assert.Nil(t, spec.Find("missing"))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		spec := &Spec{Agents: []Requirement{
+			{Name: "frontend", Version: "^2.1"},
+			{Name: "qa", Source: "team-agents"},
+		}}
+
+		require.NoError(t, Write(tmpDir, spec))
+		assert.True(t, Exists(tmpDir))
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, Filename))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "- frontend@^2.1\n")
+
+		read, err := Read(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, spec, read)
+	})
+
+	t.Run("missing spec", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		assert.False(t, Exists(tmpDir))
+		_, err := Read(tmpDir)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("rejects duplicates", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeSpec(t, tmpDir, "agents:\n  - frontend@1\n  - frontend@2\n")
+
+		_, err := Read(tmpDir)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "more than once")
+	})
+
+	t.Run("rejects invalid constraints", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeSpec(t, tmpDir, "agents:\n  - frontend@^nope\n")
+
+		_, err := Read(tmpDir)
+		assert.Error(t, err)
+	})
+}
+
+func TestSet(t *testing.T) {
+	spec := &Spec{}
+	spec.Set(Requirement{Name: "frontend", Version: "^1"})
+	spec.Set(Requirement{Name: "backend"})
+	spec.Set(Requirement{Name: "frontend", Version: "^2"})
+
+	assert.Equal(t, []Requirement{
+		{Name: "frontend", Version: "^2"},
+		{Name: "backend"},
+	}, spec.Agents)
+}
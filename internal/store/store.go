@@ -0,0 +1,107 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lando/cami/internal/config"
+	"github.com/lando/cami/internal/manifest"
+)
+
+// storeDirName is the snapshot directory inside the CAMI workspace
+const storeDirName = "store"
+
+// Dir returns the directory deployed-content snapshots are stored in
+func Dir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, storeDirName), nil
+}
+
+// Put stores content as deployed and returns its key, the same hash recorded
+// as ContentHash in manifests. Storing the same content twice is a no-op.
+func Put(data []byte) (string, error) {
+	hash := manifest.HashContent(data)
+
+	path, err := objectPath(hash)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	// Write to a temp file first so a partial write never looks like a snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return hash, nil
+}
+
+// Get returns the content stored under a manifest content hash
+func Get(hash string) ([]byte, error) {
+	path, err := objectPath(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshot stored for %s", hash)
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	return data, nil
+}
+
+// Has reports whether content is stored under a manifest content hash
+func Has(hash string) bool {
+	path, err := objectPath(hash)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// objectPath maps a "sha256:<hex>" hash to its file, fanned out by the first
+// two hex digits to keep directories small
+func objectPath(hash string) (string, error) {
+	hex, ok := strings.CutPrefix(hash, "sha256:")
+	if !ok || len(hex) != 64 || strings.Trim(hex, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid content hash: %q", hash)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, hex[:2], hex[2:]), nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lando/cami/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("put and get round trip", func(t *testing.T) {
+		t.Setenv("CAMI_DIR", t.TempDir())
+		data := []byte("---\nname: frontend\n---\n# Frontend\n")
+
+		hash, err := Put(data)
+		require.NoError(t, err)
+		assert.Equal(t, manifest.HashContent(data), hash)
+		assert.True(t, Has(hash))
+
+		stored, err := Get(hash)
+		require.NoError(t, err)
+		assert.Equal(t, data, stored)
+	})
+
+	t.Run("stored under workspace", func(t *testing.T) {
+		camiDir := t.TempDir()
+		t.Setenv("CAMI_DIR", camiDir)
+
+		hash, err := Put([]byte("content"))
+		require.NoError(t, err)
+
+		hex := hash[len("sha256:"):]
+		_, err = os.Stat(filepath.Join(camiDir, "store", hex[:2], hex[2:]))
+		assert.NoError(t, err)
+	})
+
+	t.Run("put is idempotent", func(t *testing.T) {
+		t.Setenv("CAMI_DIR", t.TempDir())
+
+		first, err := Put([]byte("content"))
+		require.NoError(t, err)
+		second, err := Put([]byte("content"))
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("missing snapshot", func(t *testing.T) {
+		t.Setenv("CAMI_DIR", t.TempDir())
+		hash := manifest.HashContent([]byte("never stored"))
+
+		assert.False(t, Has(hash))
+		_, err := Get(hash)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid hashes", func(t *testing.T) {
+		t.Setenv("CAMI_DIR", t.TempDir())
+
+		_, err := Get("../../etc/passwd")
+		assert.Error(t, err)
+		assert.False(t, Has("sha256:short"))
+	})
+}
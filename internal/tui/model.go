@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -38,6 +40,10 @@ type Model struct {
 	message        string
 	err            error
 
+	// Files that failed to load or have schema problems, shown with w
+	loadReport       *agent.LoadReport
+	showLoadProblems bool
+
 	// Location management
 	locationCursor         int
 	locationViewportOffset int // For scrolling the location list
@@ -141,16 +147,45 @@ var (
 			Bold(true)
 )
 
-// NewModel creates a new TUI model
-func NewModel(agents []*agent.Agent, cfg *config.Config) Model {
+// categoryOrder is the order agent categories are listed in
+var categoryOrder = []string{"core", "specialized", "infrastructure", "integration", "design", "meta", "uncategorized"}
+
+// NewModel creates a new TUI model. The load report's problems are listed
+// below the agents.
+func NewModel(agents []*agent.Agent, report *agent.LoadReport, cfg *config.Config) Model {
+	// Order agents as they are displayed, so the cursor moves down the list
+	rank := make(map[string]int, len(categoryOrder))
+	for i, category := range categoryOrder {
+		rank[category] = i
+	}
+	position := func(ag *agent.Agent) int {
+		if i, ok := rank[displayCategory(ag)]; ok {
+			return i
+		}
+		return len(categoryOrder)
+	}
+	agents = append([]*agent.Agent(nil), agents...)
+	sort.SliceStable(agents, func(i, j int) bool {
+		return position(agents[i]) < position(agents[j])
+	})
+
 	return Model{
 		state:          ViewAgentSelection,
 		agents:         agents,
 		selectedAgents: make(map[int]bool),
 		config:         cfg,
+		loadReport:     report,
 	}
 }
 
+// displayCategory returns the category an agent is listed under
+func displayCategory(ag *agent.Agent) string {
+	if ag.Category == "" {
+		return "uncategorized"
+	}
+	return ag.Category
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return nil
@@ -237,6 +272,9 @@ func (m Model) updateAgentSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.state = ViewDeployment
 		m.cursor = 0
+	case msg.String() == "w" && m.loadReport.HasProblems():
+		m.showLoadProblems = !m.showLoadProblems
+		m.adjustViewport()
 	case msg.String() == "i":
 		// Enter discovery view and trigger scan
 		m.state = ViewDiscovery
@@ -252,7 +290,7 @@ func (m Model) updateAgentSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m *Model) adjustViewport() {
 	// Calculate available height for agent list
 	// Title (3 lines) + "Select agents" (2 lines) + message (2 lines if present) + help (2 lines) = ~9 lines overhead
-	overhead := 9
+	overhead := 9 + m.loadProblemLines()
 	if m.message != "" {
 		overhead += 2
 	}
@@ -275,6 +313,18 @@ func (m *Model) adjustViewport() {
 	}
 }
 
+// loadProblemLines returns how many lines the load problems take below the
+// agent list
+func (m *Model) loadProblemLines() int {
+	if !m.loadReport.HasProblems() {
+		return 0
+	}
+	if m.showLoadProblems {
+		return 2 + len(m.loadReport.Problems())
+	}
+	return 2
+}
+
 // adjustLocationViewport ensures the location cursor is visible
 func (m *Model) adjustLocationViewport() {
 	// Title (3 lines) + help (2 lines) = ~5 lines overhead
@@ -421,12 +471,13 @@ func (m Model) updateDeployment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor--
 		}
 	case key.Matches(msg, keys.Down):
-		if m.cursor < len(m.config.Locations)-1 {
+		if m.cursor < len(m.config.DeployTargets())-1 {
 			m.cursor++
 		}
 	case key.Matches(msg, keys.Deploy), msg.String() == "enter":
-		if m.cursor < len(m.config.Locations) {
-			m.deployLocation = &m.config.Locations[m.cursor]
+		targets := m.config.DeployTargets()
+		if m.cursor < len(targets) {
+			m.deployLocation = &targets[m.cursor]
 
 			// Get selected agents
 			var selectedAgents []*agent.Agent
@@ -436,8 +487,8 @@ func (m Model) updateDeployment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// Deploy agents
-			results, err := deploy.DeployAgents(selectedAgents, m.deployLocation.Path, false)
+			// Deploy agents and track them in the manifests, as cami deploy does
+			results, err := deploy.DeployAndRecord(selectedAgents, m.deployLocation.Path, false, m.config.AgentSources)
 			if err != nil {
 				m.err = err
 				m.state = ViewAgentSelection
@@ -510,15 +561,21 @@ func (m Model) updateDiscovery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			locStatus := m.discoveryResult.LocationStatuses[m.discoveryLocationIdx]
 			if m.discoveryAgentIdx < len(locStatus.AgentStatuses) {
 				agentStatus := locStatus.AgentStatuses[m.discoveryAgentIdx]
-				if agentStatus.Status == discovery.StatusUpdateAvailable || agentStatus.Status == discovery.StatusNotDeployed {
-					// Deploy single agent
-					results, err := deploy.DeployAgents([]*agent.Agent{agentStatus.Agent}, locStatus.Location.Path, false)
-					if err == nil && len(results) > 0 && results[0].Success {
-						m.message = fmt.Sprintf("Updated %s to v%s", agentStatus.Agent.Name, agentStatus.Agent.Version)
-						// Rescan to update status
-						m.discoveryLoading = true
-						return m, m.scanLocations()
+				if agentStatus.Status == discovery.StatusUpdateAvailable || agentStatus.Status == discovery.StatusSourceModified || agentStatus.Status == discovery.StatusNotDeployed {
+					// Deploy single agent over the old copy and record it as the new baseline
+					results, err := deploy.DeployAndRecord([]*agent.Agent{agentStatus.Agent}, locStatus.Location.Path, true, m.config.AgentSources)
+					if err != nil {
+						m.message = fmt.Sprintf("Failed to update %s: %v", agentStatus.Agent.Name, err)
+						return m, nil
 					}
+					if len(results) > 0 && !results[0].Success {
+						m.message = fmt.Sprintf("Failed to update %s: %s", agentStatus.Agent.Name, results[0].Message)
+						return m, nil
+					}
+					m.message = fmt.Sprintf("Updated %s to v%s", agentStatus.Agent.Name, agentStatus.Agent.Version)
+					// Rescan to update status
+					m.discoveryLoading = true
+					return m, m.scanLocations()
 				}
 			}
 		}
@@ -528,28 +585,43 @@ func (m Model) updateDiscovery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			locStatus := m.discoveryResult.LocationStatuses[m.discoveryLocationIdx]
 			var agentsToUpdate []*agent.Agent
 			for _, agentStatus := range locStatus.AgentStatuses {
-				if agentStatus.Status == discovery.StatusUpdateAvailable || agentStatus.Status == discovery.StatusNotDeployed {
+				if agentStatus.Status == discovery.StatusUpdateAvailable || agentStatus.Status == discovery.StatusSourceModified || agentStatus.Status == discovery.StatusNotDeployed {
 					agentsToUpdate = append(agentsToUpdate, agentStatus.Agent)
 				}
 			}
 			if len(agentsToUpdate) > 0 {
-				_, err := deploy.DeployAgents(agentsToUpdate, locStatus.Location.Path, false)
-				if err == nil {
-					m.message = fmt.Sprintf("Updated %d agents", len(agentsToUpdate))
-					// Rescan to update status
-					m.discoveryLoading = true
-					return m, m.scanLocations()
+				results, err := deploy.DeployAndRecord(agentsToUpdate, locStatus.Location.Path, true, m.config.AgentSources)
+				if err != nil {
+					m.message = fmt.Sprintf("Failed to update agents: %v", err)
+					return m, nil
+				}
+
+				var failed []string
+				for _, result := range results {
+					if !result.Success {
+						failed = append(failed, fmt.Sprintf("%s (%s)", result.Agent.ID(), result.Message))
+					}
+				}
+				if len(failed) > 0 {
+					m.message = fmt.Sprintf("Failed to update %d of %d agents: %s", len(failed), len(results), strings.Join(failed, ", "))
+					return m, nil
 				}
+
+				m.message = fmt.Sprintf("Updated %d agents", len(results))
+				// Rescan to update status
+				m.discoveryLoading = true
+				return m, m.scanLocations()
 			}
 		}
 	}
 	return m, nil
 }
 
-// scanLocations performs an asynchronous scan of all locations
+// scanLocations performs an asynchronous scan of the user scope and all
+// configured locations
 func (m Model) scanLocations() tea.Cmd {
 	return func() tea.Msg {
-		result, err := discovery.ScanAllLocations(m.config.Locations, m.agents)
+		result, err := discovery.ScanAllLocations(m.config.DeployTargets(), m.agents)
 		return scanCompleteMsg{
 			result: result,
 			err:    err,
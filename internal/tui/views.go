@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lando/cami/internal/config"
 	"github.com/lando/cami/internal/discovery"
 )
 
@@ -19,7 +21,7 @@ func (m Model) viewAgentSelection() string {
 	b.WriteString("Select agents to deploy:\n\n")
 
 	// Calculate viewport parameters
-	overhead := 9
+	overhead := 9 + m.loadProblemLines()
 	if m.message != "" {
 		overhead += 2
 	}
@@ -32,7 +34,6 @@ func (m Model) viewAgentSelection() string {
 	}
 
 	var displayItems []displayItem
-	categoryOrder := []string{"core", "specialized", "infrastructure", "integration", "design", "meta", "uncategorized"}
 
 	for _, category := range categoryOrder {
 		var categoryAgents []int
@@ -138,6 +139,20 @@ func (m Model) viewAgentSelection() string {
 		b.WriteString(versionStyle.Render(fmt.Sprintf("  ↓ scroll down...\n")))
 	}
 
+	// Load problems
+	if m.loadReport.HasProblems() {
+		problems := m.loadReport.Problems()
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render(fmt.Sprintf("⚠ %d load problem(s)", len(problems))))
+		b.WriteString("\n")
+		if m.showLoadProblems {
+			for _, problem := range problems {
+				b.WriteString(versionStyle.Render("  " + problem))
+				b.WriteString("\n")
+			}
+		}
+	}
+
 	// Message
 	if m.message != "" {
 		b.WriteString("\n")
@@ -146,8 +161,12 @@ func (m Model) viewAgentSelection() string {
 	}
 
 	// Help
+	help := "space: select  •  enter: deploy  •  l: locations  •  i: discovery  •  q: quit"
+	if m.loadReport.HasProblems() {
+		help = "space: select  •  enter: deploy  •  l: locations  •  i: discovery  •  w: load problems  •  q: quit"
+	}
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("space: select  •  enter: deploy  •  l: locations  •  i: discovery  •  q: quit"))
+	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
 }
@@ -177,6 +196,12 @@ func (m Model) viewLocationManagement() string {
 		b.WriteString("\n")
 		b.WriteString(helpStyle.Render("tab: switch field  •  enter: save  •  esc: cancel"))
 	} else {
+		// The user scope is always available and can't be edited
+		if user, err := config.UserScope(); err == nil {
+			path := versionStyle.Render(filepath.Join(user.Path, ".claude", "agents") + " (user scope, always available)")
+			b.WriteString(fmt.Sprintf("  %s - %s\n\n", user.Name, path))
+		}
+
 		if len(m.config.Locations) == 0 {
 			b.WriteString("No deployment locations configured.\n\n")
 			b.WriteString("Press 'a' to add a location.\n")
@@ -271,14 +296,15 @@ func (m Model) viewDeployment() string {
 	}
 	b.WriteString(fmt.Sprintf("Deploying: %s\n\n", strings.Join(selectedNames, ", ")))
 
-	if len(m.config.Locations) == 0 {
+	targets := m.config.DeployTargets()
+	if len(targets) == 0 {
 		b.WriteString(errorStyle.Render("No deployment locations configured."))
 		b.WriteString("\n\n")
 		b.WriteString("Press 'esc' to go back and configure locations.\n")
 	} else {
 		b.WriteString("Select destination:\n\n")
 
-		for i, loc := range m.config.Locations {
+		for i, loc := range targets {
 			cursor := " "
 			if m.cursor == i {
 				cursor = ">"
@@ -290,6 +316,9 @@ func (m Model) viewDeployment() string {
 			}
 
 			path := versionStyle.Render(loc.Path)
+			if loc.Name == config.UserScopeName {
+				path = versionStyle.Render(filepath.Join(loc.Path, ".claude", "agents") + " (user scope)")
+			}
 			b.WriteString(fmt.Sprintf("%s %s - %s\n", cursor, name, path))
 		}
 
@@ -397,22 +426,35 @@ func (m Model) viewDiscovery() string {
 		upToDate := 0
 		updateAvailable := 0
 		notDeployed := 0
+		modified := 0
+		unmanaged := 0
 		for _, agentStatus := range locStatus.AgentStatuses {
 			switch agentStatus.Status {
 			case discovery.StatusUpToDate:
 				upToDate++
-			case discovery.StatusUpdateAvailable:
+			case discovery.StatusUpdateAvailable, discovery.StatusSourceModified:
 				updateAvailable++
 			case discovery.StatusNotDeployed:
 				notDeployed++
+			case discovery.StatusLocallyModified, discovery.StatusBothModified:
+				modified++
+			case discovery.StatusOrphaned, discovery.StatusUntracked:
+				unmanaged++
 			}
 		}
 
 		// Summary
-		b.WriteString(fmt.Sprintf("Status: %s up-to-date, %s updates available, %s not deployed\n\n",
+		summary := fmt.Sprintf("Status: %s up-to-date, %s updates available, %s not deployed",
 			successStyle.Render(fmt.Sprintf("%d", upToDate)),
 			warningStyle.Render(fmt.Sprintf("%d", updateAvailable)),
-			versionStyle.Render(fmt.Sprintf("%d", notDeployed))))
+			versionStyle.Render(fmt.Sprintf("%d", notDeployed)))
+		if modified > 0 {
+			summary += fmt.Sprintf(", %s locally modified", errorStyle.Render(fmt.Sprintf("%d", modified)))
+		}
+		if unmanaged > 0 {
+			summary += fmt.Sprintf(", %s orphaned or untracked", versionStyle.Render(fmt.Sprintf("%d", unmanaged)))
+		}
+		b.WriteString(summary + "\n\n")
 
 		// Agent list with scrolling
 		b.WriteString("Agents:\n")
@@ -453,9 +495,11 @@ func (m Model) viewDiscovery() string {
 			switch agentStatus.Status {
 			case discovery.StatusUpToDate:
 				statusStyle = successStyle
-			case discovery.StatusUpdateAvailable:
+			case discovery.StatusUpdateAvailable, discovery.StatusSourceModified:
 				statusStyle = warningStyle
-			case discovery.StatusNotDeployed:
+			case discovery.StatusLocallyModified, discovery.StatusBothModified:
+				statusStyle = errorStyle
+			default:
 				statusStyle = versionStyle
 			}
 
@@ -465,14 +509,25 @@ func (m Model) viewDiscovery() string {
 			}
 
 			versionInfo := ""
-			if agentStatus.DeployedVersion != "" {
-				if agentStatus.Status == discovery.StatusUpdateAvailable {
-					versionInfo = fmt.Sprintf("v%s → v%s", agentStatus.DeployedVersion, agentStatus.AvailableVersion)
-				} else {
-					versionInfo = fmt.Sprintf("v%s", agentStatus.DeployedVersion)
-				}
-			} else {
+			switch {
+			case agentStatus.DeployedVersion == "" && agentStatus.Status == discovery.StatusNotDeployed:
 				versionInfo = fmt.Sprintf("v%s available", agentStatus.AvailableVersion)
+			case agentStatus.Status == discovery.StatusUpdateAvailable,
+				agentStatus.Status == discovery.StatusSourceModified && agentStatus.DeployedVersion != agentStatus.AvailableVersion:
+				versionInfo = fmt.Sprintf("v%s → v%s", agentStatus.DeployedVersion, agentStatus.AvailableVersion)
+			default:
+				versionInfo = fmt.Sprintf("v%s", agentStatus.DeployedVersion)
+			}
+			switch agentStatus.Status {
+			case discovery.StatusLocallyModified, discovery.StatusSourceModified, discovery.StatusBothModified,
+				discovery.StatusOrphaned, discovery.StatusUntracked:
+				versionInfo += " " + string(agentStatus.Status)
+			}
+			if agentStatus.OverridesUser {
+				versionInfo += " overrides user scope"
+			}
+			if len(agentStatus.ShadowedBy) > 0 {
+				versionInfo += " shadowed in " + strings.Join(agentStatus.ShadowedBy, ", ")
 			}
 
 			line := fmt.Sprintf("%s %s %-20s %s", cursor, statusStyle.Render(symbol), name, versionStyle.Render(versionInfo))